@@ -0,0 +1,611 @@
+// Package agent implements an LLDP agent, which periodically transmits LLDP
+// frames describing the local system on a set of ports, and maintains a table
+// of the neighbors discovered by receiving LLDP frames on those ports.
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+	"github.com/mdlayher/lldp"
+	"github.com/mdlayher/lldp/host"
+)
+
+// A Conn is a connection which sends and receives LLDP Ethernet frames on
+// a single interface.
+type Conn interface {
+	// ReadFrame reads the next LLDP Ethernet frame.  It must return an
+	// error wrapping net.ErrClosed once the Conn is closed, and an error
+	// wrapping ErrMalformedFrame if a received frame cannot be decoded.
+	// Other errors, such as those which occur while the link is down, are
+	// considered temporary.
+	ReadFrame() (*ethernet.Frame, error)
+
+	// WriteFrame writes an Ethernet frame.  If the frame's source address
+	// is not set, the address of the interface is used.
+	WriteFrame(f *ethernet.Frame) error
+
+	// Close closes the Conn.
+	Close() error
+}
+
+// ErrMalformedFrame is returned by a Conn when a received frame is not a
+// valid Ethernet frame.
+var ErrMalformedFrame = errors.New("agent: malformed Ethernet frame")
+
+// A ListenFunc opens a Conn on the named interface.
+type ListenFunc func(ifname string) (Conn, error)
+
+// An Agent transmits and receives LLDP frames on a set of ports.
+type Agent struct {
+	collector host.Collector
+	listen    ListenFunc
+	neighbors *NeighborTable
+	loops     *loopDetector
+	ll        *log.Logger
+
+	mu    sync.Mutex
+	cfg   Config
+	ctx   context.Context
+	ports map[string]*port
+	stats map[string]*portCounters
+
+	// alarmMu guards alarmFunc separately from mu, as alarms are reported
+	// by receive goroutines which mu's holders may wait for.
+	alarmMu   sync.Mutex
+	alarmFunc func(AlarmEvent)
+}
+
+// New creates an Agent which operates on the ports specified by cfg, using c
+// to produce transmitted frames and listen to open a Conn on each port.  If
+// ll is nil, errors encountered on each port are not logged.
+func New(cfg Config, c host.Collector, listen ListenFunc, ll *log.Logger) *Agent {
+	if ll == nil {
+		ll = log.New(io.Discard, "", 0)
+	}
+
+	return &Agent{
+		collector: c,
+		listen:    listen,
+		neighbors: NewNeighborTable(),
+		loops:     newLoopDetector(),
+		ll:        ll,
+		cfg:       cfg.clone(),
+		ports:     make(map[string]*port),
+		stats:     make(map[string]*portCounters),
+	}
+}
+
+// Neighbors returns the NeighborTable populated by an Agent.
+func (a *Agent) Neighbors() *NeighborTable {
+	return a.neighbors
+}
+
+// Alarms returns the loop and miscabling alarms which are currently raised,
+// sorted by type and chassis ID.
+func (a *Agent) Alarms() []Alarm {
+	return a.loops.alarms()
+}
+
+// SetAlarmFunc sets a function which is called with each AlarmEvent as
+// alarms are raised and cleared.  fn is called from the Agent's receive
+// goroutines and must not block.  If fn is nil, events are only logged.
+func (a *Agent) SetAlarmFunc(fn func(AlarmEvent)) {
+	a.alarmMu.Lock()
+	defer a.alarmMu.Unlock()
+
+	a.alarmFunc = fn
+}
+
+// alarm logs and reports alarm events.
+func (a *Agent) alarm(events []AlarmEvent) {
+	if len(events) == 0 {
+		return
+	}
+
+	a.alarmMu.Lock()
+	fn := a.alarmFunc
+	a.alarmMu.Unlock()
+
+	for _, e := range events {
+		a.ll.Print(e)
+		if fn != nil {
+			fn(e)
+		}
+	}
+}
+
+// Run starts transmitting and receiving on each configured port, and blocks
+// until ctx is canceled.  When Run returns, a shutdown frame with a TTL of
+// zero has been sent on each port which was transmitting, so that neighbors
+// can discard the information advertised by this Agent immediately.
+func (a *Agent) Run(ctx context.Context) error {
+	a.mu.Lock()
+	if a.ctx != nil {
+		a.mu.Unlock()
+		return errors.New("agent: already running")
+	}
+
+	a.ctx = ctx
+	err := a.apply(a.cfg)
+	a.mu.Unlock()
+	if err != nil {
+		a.stop()
+		return err
+	}
+
+	a.readBonds()
+
+	t := time.NewTicker(time.Second)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			a.stop()
+			return nil
+		case <-t.C:
+			a.expire()
+		}
+	}
+}
+
+// expire removes expired neighbors, counting an ageout on the port where each
+// neighbor was discovered, and clears alarms whose frames have expired or
+// whose interfaces have since been bonded.
+func (a *Agent) expire() {
+	a.readBonds()
+	a.alarm(a.loops.expire())
+
+	expired := a.neighbors.Expire()
+	if len(expired) == 0 {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, n := range expired {
+		if c, ok := a.stats[n.Interface]; ok {
+			c.ageoutsTotal.Add(1)
+		}
+		a.ll.Printf("%s: neighbor %s expired", n.Interface, n.Source)
+	}
+}
+
+// Reload applies a new Config to a running Agent.  Ports which are no
+// longer transmitting send a shutdown frame, and neighbors are discarded
+// only for ports which are no longer receiving.  All other neighbor
+// information is preserved.
+func (a *Agent) Reload(cfg Config) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.cfg = cfg.clone()
+	if a.ctx == nil {
+		// Not yet running; the Config will be applied by Run.
+		return nil
+	}
+
+	return a.apply(a.cfg)
+}
+
+// apply applies cfg to every port which is running or configured.  The
+// caller must hold a.mu.
+func (a *Agent) apply(cfg Config) error {
+	a.neighbors.SetMax(cfg.MaxNeighbors)
+
+	names := make(map[string]struct{}, len(a.ports)+len(cfg.Ports))
+	for name := range a.ports {
+		names[name] = struct{}{}
+	}
+	for name := range cfg.Ports {
+		names[name] = struct{}{}
+	}
+
+	var errs []error
+	for name := range names {
+		if err := a.applyPort(name, cfg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// applyPort stops a port if it is running, and restarts it using its
+// configuration in cfg, if any.  The caller must hold a.mu.
+func (a *Agent) applyPort(name string, cfg Config) error {
+	pc, ok := cfg.Ports[name]
+	if p, running := a.ports[name]; running {
+		p.stop(!ok || !pc.AdminStatus.tx())
+		delete(a.ports, name)
+
+		// A restarted port records its chassis ID again before it
+		// transmits, which may differ under its new configuration.
+		a.loops.removeLocal(name)
+	}
+	if !ok || !pc.AdminStatus.rx() {
+		a.neighbors.RemoveInterface(name)
+	}
+	if !ok || pc.AdminStatus == AdminStatusDisabled {
+		return nil
+	}
+
+	p, err := a.startPort(name, pc)
+	if err != nil {
+		return err
+	}
+
+	a.ports[name] = p
+	return nil
+}
+
+// SetAdminStatus changes the AdminStatus of a single configured port,
+// restarting only that port.  The change persists until the next Reload.
+func (a *Agent) SetAdminStatus(ifname string, status AdminStatus) error {
+	if _, ok := adminStatusNames[status]; !ok {
+		return fmt.Errorf("agent: invalid admin status %v", status)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	pc, ok := a.cfg.Ports[ifname]
+	if !ok {
+		return fmt.Errorf("agent: interface %q is not configured", ifname)
+	}
+
+	pc.AdminStatus = status
+	a.cfg.Ports[ifname] = pc
+	if a.ctx == nil {
+		return nil
+	}
+
+	return a.applyPort(ifname, a.cfg)
+}
+
+// AdminStatus returns the AdminStatus of each configured port, keyed by
+// interface name.
+func (a *Agent) AdminStatus() map[string]AdminStatus {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	m := make(map[string]AdminStatus, len(a.cfg.Ports))
+	for name, pc := range a.cfg.Ports {
+		m[name] = pc.AdminStatus
+	}
+
+	return m
+}
+
+// LocalFrames returns the Frame most recently transmitted on each port,
+// keyed by interface name.  Ports which have not transmitted are omitted.
+func (a *Agent) LocalFrames() map[string]*lldp.Frame {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	m := make(map[string]*lldp.Frame, len(a.ports))
+	for name, p := range a.ports {
+		if f := p.lastFrame(); f != nil {
+			m[name] = f
+		}
+	}
+
+	return m
+}
+
+// Stats returns the statistics of each port which has been started, keyed by
+// interface name.  Statistics are preserved when ports are reconfigured.
+func (a *Agent) Stats() map[string]PortStats {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	m := make(map[string]PortStats, len(a.stats))
+	for name, c := range a.stats {
+		m[name] = c.snapshot()
+	}
+
+	return m
+}
+
+// readBonds records the bonds of the local system, so that a neighbor
+// discovered on each slave of a bond does not raise an alarm.
+func (a *Agent) readBonds() {
+	fsys := a.collector.FS
+	if fsys == nil {
+		fsys = os.DirFS("/")
+	}
+
+	bb, err := host.ReadBonds(fsys)
+	if err != nil {
+		a.ll.Printf("failed to read bonds: %v", err)
+		return
+	}
+
+	a.loops.setBonds(bb)
+}
+
+// stop stops all running ports, sending a shutdown frame on each port which
+// is transmitting.
+func (a *Agent) stop() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for name, p := range a.ports {
+		p.stop(true)
+		delete(a.ports, name)
+	}
+}
+
+// startPort opens a Conn and starts transmitting and receiving on a port.
+func (a *Agent) startPort(name string, pc PortConfig) (*port, error) {
+	c, err := a.listen(name)
+	if err != nil {
+		return nil, fmt.Errorf("agent: failed to listen on %q: %w", name, err)
+	}
+
+	collector := a.collector
+	collector.ChassisID = pc.ChassisID
+	collector.TTL = pc.ttl()
+	collector.VLANs = pc.TLVs&TLVVLAN != 0
+	collector.Inventory = pc.TLVs&TLVInventory != 0
+	if pc.TLVs&TLVMACPHY == 0 {
+		collector.Links = nil
+	}
+
+	stats, ok := a.stats[name]
+	if !ok {
+		stats = new(portCounters)
+		a.stats[name] = stats
+	}
+
+	ctx, cancel := context.WithCancel(a.ctx)
+	p := &port{
+		name:      name,
+		cfg:       pc,
+		conn:      c,
+		collector: &collector,
+		neighbors: a.neighbors,
+		loops:     a.loops,
+		alarm:     a.alarm,
+		stats:     stats,
+		ll:        a.ll,
+		cancel:    cancel,
+	}
+
+	if pc.AdminStatus.tx() {
+		p.txWG.Add(1)
+		go p.transmit(ctx)
+	}
+	if pc.AdminStatus.rx() {
+		p.rxWG.Add(1)
+		go p.receive(ctx)
+	}
+
+	return p, nil
+}
+
+// A port transmits and receives LLDP frames on a single interface.
+type port struct {
+	name      string
+	cfg       PortConfig
+	conn      Conn
+	collector *host.Collector
+	neighbors *NeighborTable
+	loops     *loopDetector
+	alarm     func([]AlarmEvent)
+	stats     *portCounters
+	ll        *log.Logger
+
+	cancel context.CancelFunc
+	txWG   sync.WaitGroup
+	rxWG   sync.WaitGroup
+
+	// mu guards last, the most recently transmitted Frame.
+	mu   sync.Mutex
+	last *lldp.Frame
+}
+
+// lastFrame returns the most recently transmitted Frame.
+func (p *port) lastFrame() *lldp.Frame {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.last
+}
+
+// transmit sends a frame immediately, and then once per transmit interval
+// until ctx is canceled.
+func (p *port) transmit(ctx context.Context) {
+	defer p.txWG.Done()
+
+	t := time.NewTicker(p.cfg.txInterval())
+	defer t.Stop()
+
+	for {
+		if err := p.send(); err != nil {
+			p.ll.Printf("%s: failed to transmit: %v", p.name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// send builds and transmits a single frame.
+func (p *port) send() error {
+	f, err := p.collector.Frame(p.name)
+	if err != nil {
+		return err
+	}
+
+	optional := f.Optional[:0:0]
+	for _, t := range f.Optional {
+		if p.cfg.TLVs.selected(t) {
+			optional = append(optional, t)
+		}
+	}
+	f.Optional = optional
+
+	// Record the chassis ID before transmitting, so that the frame is
+	// recognized if it is looped back.
+	p.loops.setLocal(p.name, f.ChassisID)
+	if err := p.write(f); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	p.last = f
+	p.mu.Unlock()
+
+	return nil
+}
+
+// write marshals a Frame into an Ethernet frame and transmits it.
+func (p *port) write(f *lldp.Frame) error {
+	b, err := f.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	err = p.conn.WriteFrame(&ethernet.Frame{
+		Destination: lldp.NearestBridge,
+		EtherType:   lldp.EtherType,
+		Payload:     b,
+	})
+	if err != nil {
+		return err
+	}
+
+	p.stats.framesOutTotal.Add(1)
+	return nil
+}
+
+// receive reads frames until the Conn is closed or ctx is canceled, storing
+// each valid frame in the neighbor table.  Malformed frames are counted and
+// discarded, and temporary read errors do not stop reception.  Frames carrying a local chassis ID are discarded, and
+// raise an alarm along with neighbors discovered on more than one port.
+func (p *port) receive(ctx context.Context) {
+	defer p.rxWG.Done()
+
+	// retry is the delay after a temporary read error, such as ENETDOWN
+	// while the link is down.  Only the first error of a run is logged.
+	const retry = time.Second
+	var failing bool
+
+	for {
+		ef, err := p.conn.ReadFrame()
+		switch {
+		case err == nil:
+			failing = false
+		case errors.Is(err, net.ErrClosed) || ctx.Err() != nil:
+			return
+		case errors.Is(err, ErrMalformedFrame):
+			p.stats.framesDiscardedTotal.Add(1)
+			p.stats.framesInErrorsTotal.Add(1)
+			p.ll.Printf("%s: discarded frame: %v", p.name, err)
+			continue
+		default:
+			if !failing {
+				p.ll.Printf("%s: failed to receive, retrying: %v", p.name, err)
+				failing = true
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(retry):
+			}
+			continue
+		}
+
+		lf := new(lldp.EthernetFrame)
+		if err := lf.UnmarshalEthernet(ef); err != nil {
+			if !errors.Is(err, lldp.ErrNotLLDP) {
+				p.stats.framesDiscardedTotal.Add(1)
+				p.stats.framesInErrorsTotal.Add(1)
+				p.ll.Printf("%s: discarded frame from %s: %v", p.name, ef.Source, err)
+			}
+			continue
+		}
+
+		p.stats.framesInTotal.Add(1)
+		p.check(lf)
+
+		self, events := p.loops.observe(p.name, lf.Frame)
+		p.alarm(events)
+		if self {
+			// The frame was transmitted by this Agent, so it does not
+			// describe a neighbor.
+			p.stats.framesDiscardedTotal.Add(1)
+			continue
+		}
+
+		switch p.neighbors.Update(p.name, lf) {
+		case ChangeInserted:
+			p.ll.Printf("%s: new neighbor %s", p.name, lf.Source)
+		case ChangeDropped:
+			p.stats.framesDiscardedTotal.Add(1)
+			p.ll.Printf("%s: dropped neighbor %s: neighbor table is full", p.name, lf.Source)
+		}
+	}
+}
+
+// check removes malformed and duplicate optional TLVs from a received frame,
+// counting them along with any unrecognized TLVs.
+func (p *port) check(lf *lldp.EthernetFrame) {
+	_, unrecognized, discarded := lf.Frame.CheckOptional()
+	p.stats.tlvsUnrecognizedTotal.Add(uint64(len(unrecognized)))
+	if len(discarded) == 0 {
+		return
+	}
+
+	p.stats.tlvsDiscardedTotal.Add(uint64(len(discarded)))
+
+	drop := make(map[*lldp.TLV]bool, len(discarded))
+	for _, t := range discarded {
+		drop[t] = true
+	}
+
+	f := *lf.Frame
+	f.Optional = nil
+	for _, t := range lf.Frame.Optional {
+		if !drop[t] {
+			f.Optional = append(f.Optional, t)
+		}
+	}
+	lf.Frame = &f
+}
+
+// stop stops transmitting and receiving.  If shutdown is set and the port
+// was transmitting, a shutdown frame is sent before its Conn is closed.
+func (p *port) stop(shutdown bool) {
+	p.cancel()
+	p.txWG.Wait()
+
+	if last := p.lastFrame(); shutdown && last != nil {
+		err := p.write(&lldp.Frame{
+			ChassisID: last.ChassisID,
+			PortID:    last.PortID,
+			TTL:       0,
+		})
+		if err != nil {
+			p.ll.Printf("%s: failed to transmit shutdown frame: %v", p.name, err)
+		}
+	}
+
+	_ = p.conn.Close()
+	p.rxWG.Wait()
+}
@@ -0,0 +1,472 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+	"github.com/mdlayher/lldp"
+	"github.com/mdlayher/lldp/host"
+)
+
+func TestAgentTransmitReceiveReload(t *testing.T) {
+	conns := make(chan *fakeConn, 8)
+	listen := func(ifname string) (Conn, error) {
+		if ifname != "eth0" {
+			return nil, errors.New("no such interface")
+		}
+
+		c := newFakeConn()
+		conns <- c
+		return c, nil
+	}
+
+	cfg := Config{Ports: map[string]PortConfig{
+		"eth0": {
+			TxInterval: 30 * time.Second,
+			TLVs:       TLVSystemName,
+			ChassisID:  host.Fixed("host1"),
+		},
+	}}
+
+	a := New(cfg, testCollector(), listen, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- a.Run(ctx) }()
+
+	// The first frame is transmitted immediately, carrying only the
+	// selected optional TLVs.
+	c := <-conns
+	f := readFrame(t, c)
+	if want, got := 120*time.Second, f.TTL; want != got {
+		t.Fatalf("unexpected TTL: %v != %v", want, got)
+	}
+	if len(f.Optional) != 1 || f.Optional[0].Type != lldp.TLVTypeSystemName {
+		t.Fatalf("unexpected optional TLVs: %v", f.Optional)
+	}
+
+	c.in <- mustEthernet(t, testEthernetFrame("sw1", "1", time.Minute))
+	waitNeighbors(t, a, 1)
+
+	// Reloading with rx still enabled preserves neighbors, and stopping
+	// transmit sends a shutdown frame.
+	cfg.Ports["eth0"] = PortConfig{
+		AdminStatus: AdminStatusRx,
+		ChassisID:   host.Fixed("host1"),
+	}
+	if err := a.Reload(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	f = readFrame(t, c)
+	if f.TTL != 0 || len(f.Optional) != 0 || string(f.ChassisID.ID) != "host1" {
+		t.Fatalf("unexpected shutdown frame: %v", f)
+	}
+	waitNeighbors(t, a, 1)
+
+	c = <-conns
+	c.in <- mustEthernet(t, testEthernetFrame("sw1", "1", 0))
+	waitNeighbors(t, a, 0)
+	c.in <- mustEthernet(t, testEthernetFrame("sw2", "1", time.Minute))
+	waitNeighbors(t, a, 1)
+
+	// Unknown ports are reported, but do not affect other ports.
+	cfg.Ports["eth9"] = PortConfig{}
+	if err := a.Reload(cfg); err == nil {
+		t.Fatal("expected an error for unknown interface")
+	}
+	waitNeighbors(t, a, 1)
+
+	// Removing a port discards its neighbors.
+	if err := a.Reload(Config{}); err != nil {
+		t.Fatal(err)
+	}
+	waitNeighbors(t, a, 0)
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAgentRunShutdown(t *testing.T) {
+	c := newFakeConn()
+	listen := func(string) (Conn, error) { return c, nil }
+
+	a := New(Config{Ports: map[string]PortConfig{
+		"eth0": {
+			AdminStatus: AdminStatusTx,
+			ChassisID:   host.Fixed("host1"),
+		},
+	}}, testCollector(), listen, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- a.Run(ctx) }()
+
+	if f := readFrame(t, c); f.TTL == 0 {
+		t.Fatal("expected a non-zero TTL in the first frame")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	if f := readFrame(t, c); f.TTL != 0 {
+		t.Fatalf("expected a shutdown frame, but got TTL %v", f.TTL)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.closed {
+		t.Fatal("expected Conn to be closed")
+	}
+}
+
+func TestAgentSetAdminStatusLocalFramesStats(t *testing.T) {
+	conns := make(chan *fakeConn, 8)
+	listen := func(string) (Conn, error) {
+		c := newFakeConn()
+		conns <- c
+		return c, nil
+	}
+
+	a := New(Config{Ports: map[string]PortConfig{
+		"eth0": {ChassisID: host.Fixed("host1")},
+	}}, testCollector(), listen, nil)
+
+	if err := a.SetAdminStatus("eth1", AdminStatusRx); err == nil {
+		t.Fatal("expected an error for unconfigured interface")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- a.Run(ctx) }()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	c := <-conns
+	readFrame(t, c)
+
+	local := a.LocalFrames()
+	if f, ok := local["eth0"]; !ok || string(f.ChassisID.ID) != "host1" {
+		t.Fatalf("unexpected local frames: %v", local)
+	}
+
+	c.in <- mustEthernet(t, testEthernetFrame("sw1", "1", time.Minute))
+	c.in <- &ethernet.Frame{
+		Destination: lldp.NearestBridge,
+		EtherType:   lldp.EtherType,
+		Payload:     []byte{0xff},
+	}
+	waitNeighbors(t, a, 1)
+
+	// The invalid frame is only counted once the next read begins, so
+	// send another valid frame to synchronize.  It carries a reserved TLV
+	// and a malformed system capabilities TLV.
+	lf := testEthernetFrame("sw1", "1", time.Minute)
+	lf.Frame.Optional = []*lldp.TLV{
+		{Type: 9, Length: 1, Value: []byte{0}},
+		{Type: lldp.TLVTypeSystemCapabilities, Length: 1, Value: []byte{0}},
+	}
+	c.in <- mustEthernet(t, lf)
+
+	if err := a.SetAdminStatus("eth0", AdminStatusDisabled); err != nil {
+		t.Fatal(err)
+	}
+	if f := readFrame(t, c); f.TTL != 0 {
+		t.Fatalf("expected a shutdown frame, but got TTL %v", f.TTL)
+	}
+	waitNeighbors(t, a, 0)
+
+	if want, got := AdminStatusDisabled, a.AdminStatus()["eth0"]; want != got {
+		t.Fatalf("unexpected admin status: %v != %v", want, got)
+	}
+	if n := len(a.LocalFrames()); n != 0 {
+		t.Fatalf("expected no local frames while disabled, but got %d", n)
+	}
+
+	// The chassis ID is no longer local once the port stops transmitting.
+	a.loops.mu.Lock()
+	n := len(a.loops.local)
+	a.loops.mu.Unlock()
+	if n != 0 {
+		t.Fatalf("expected no local chassis IDs while disabled, but got %d", n)
+	}
+
+	want := PortStats{
+		FramesOutTotal:        2,
+		FramesInTotal:         2,
+		FramesDiscardedTotal:  1,
+		FramesInErrorsTotal:   1,
+		TLVsDiscardedTotal:    1,
+		TLVsUnrecognizedTotal: 1,
+	}
+	if got := a.Stats()["eth0"]; want != got {
+		t.Fatalf("unexpected stats:\n- want: %+v\n-  got: %+v", want, got)
+	}
+}
+
+func TestAgentReceiveErrors(t *testing.T) {
+	conns := make(chan *fakeConn, 1)
+	listen := func(string) (Conn, error) {
+		c := newFakeConn()
+		conns <- c
+		return c, nil
+	}
+
+	a := New(Config{Ports: map[string]PortConfig{
+		"eth0": {AdminStatus: AdminStatusRx},
+	}}, testCollector(), listen, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- a.Run(ctx) }()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	c := <-conns
+
+	// Neither a malformed frame nor the link going down stops reception.
+	c.errs <- fmt.Errorf("%w: runt frame", ErrMalformedFrame)
+	c.errs <- errors.New("network is down")
+	c.in <- mustEthernet(t, testEthernetFrame("sw1", "1", time.Minute))
+	waitNeighbors(t, a, 1)
+
+	want := PortStats{
+		FramesInTotal:        1,
+		FramesDiscardedTotal: 1,
+		FramesInErrorsTotal:  1,
+	}
+	if got := a.Stats()["eth0"]; want != got {
+		t.Fatalf("unexpected stats:\n- want: %+v\n-  got: %+v", want, got)
+	}
+}
+
+func TestAgentSelfReception(t *testing.T) {
+	c := newFakeConn()
+	listen := func(string) (Conn, error) { return c, nil }
+
+	a := New(Config{Ports: map[string]PortConfig{
+		"eth0": {ChassisID: host.Fixed("host1")},
+	}}, testCollector(), listen, nil)
+
+	events := make(chan AlarmEvent, 1)
+	a.SetAlarmFunc(func(e AlarmEvent) { events <- e })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- a.Run(ctx) }()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	// Loop the transmitted frame back to the Agent.
+	readFrame(t, c)
+	c.in <- mustEthernet(t, testEthernetFrame("host1", "eth0", time.Minute))
+
+	select {
+	case e := <-events:
+		if e.State != AlarmRaised || e.Alarm.Type != AlarmSelfReception {
+			t.Fatalf("unexpected alarm event: %v", e)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for alarm event")
+	}
+
+	if want, got := []string{"eth0"}, a.Alarms(); len(got) != 1 || !reflect.DeepEqual(want, got[0].Interfaces) {
+		t.Fatalf("unexpected alarms: %v", got)
+	}
+
+	// Synchronize with another frame, and verify that the looped frame was
+	// not stored as a neighbor.
+	c.in <- mustEthernet(t, testEthernetFrame("sw1", "1", time.Minute))
+	waitNeighbors(t, a, 1)
+
+	if want, got := uint64(1), a.Stats()["eth0"].FramesDiscardedTotal; want != got {
+		t.Fatalf("unexpected discarded frames: %d != %d", want, got)
+	}
+}
+
+// testCollector returns a host.Collector for a host with a single interface.
+func testCollector() host.Collector {
+	fsys := fstest.MapFS{
+		"proc/sys/kernel/hostname":            {Data: []byte("host1\n")},
+		"sys/class/net/eth0/address":          {Data: []byte("de:ad:be:ef:00:01\n")},
+		"sys/class/net/eth0/addr_assign_type": {Data: []byte("0\n")},
+		"sys/class/net/eth0/ifindex":          {Data: []byte("2\n")},
+		"sys/class/net/eth0/type":             {Data: []byte("1\n")},
+		"sys/class/net/eth0/device/vendor":    {Data: []byte("0x8086\n")},
+	}
+
+	return host.Collector{
+		FS:    fsys,
+		Addrs: func(string) ([]net.IP, error) { return nil, nil },
+	}
+}
+
+// readFrame reads the next Frame written to a fakeConn.
+func readFrame(t *testing.T, c *fakeConn) *lldp.Frame {
+	t.Helper()
+
+	select {
+	case ef := <-c.out:
+		f := new(lldp.Frame)
+		if err := f.UnmarshalBinary(ef.Payload); err != nil {
+			t.Fatal(err)
+		}
+
+		return f
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for frame")
+	}
+
+	panic("unreachable")
+}
+
+// waitNeighbors waits until an Agent has n neighbors.
+func waitNeighbors(t *testing.T, a *Agent, n int) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if len(a.Neighbors().Neighbors()) == n {
+			return
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	t.Fatalf("timed out waiting for %d neighbors", n)
+}
+
+// mustEthernet wraps an EthernetFrame's Frame in an ethernet.Frame.
+func mustEthernet(t *testing.T, lf *lldp.EthernetFrame) *ethernet.Frame {
+	t.Helper()
+
+	b, err := lf.Frame.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &ethernet.Frame{
+		Destination: lf.Destination,
+		Source:      lf.Source,
+		EtherType:   lldp.EtherType,
+		Payload:     b,
+	}
+}
+
+// A fakeConn is a Conn which reads frames from in and writes frames to out.
+// Errors sent on errs are returned by ReadFrame.
+type fakeConn struct {
+	in   chan *ethernet.Frame
+	errs chan error
+	out  chan *ethernet.Frame
+	done chan struct{}
+
+	mu     sync.Mutex
+	closed bool
+}
+
+func newFakeConn() *fakeConn {
+	return &fakeConn{
+		in:   make(chan *ethernet.Frame),
+		errs: make(chan error),
+		out:  make(chan *ethernet.Frame, 16),
+		done: make(chan struct{}),
+	}
+}
+
+func (c *fakeConn) ReadFrame() (*ethernet.Frame, error) {
+	select {
+	case f := <-c.in:
+		return f, nil
+	case err := <-c.errs:
+		return nil, err
+	case <-c.done:
+		return nil, net.ErrClosed
+	}
+}
+
+func (c *fakeConn) WriteFrame(f *ethernet.Frame) error {
+	c.out <- f
+	return nil
+}
+
+func (c *fakeConn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.closed {
+		c.closed = true
+		close(c.done)
+	}
+
+	return nil
+}
+
+func TestAgentExpireCountsAgeouts(t *testing.T) {
+	a := New(Config{}, host.Collector{}, nil, nil)
+	a.stats["eth0"] = new(portCounters)
+
+	now := time.Unix(1000, 0)
+	a.neighbors.now = func() time.Time { return now }
+
+	a.neighbors.Update("eth0", testEthernetFrame("sw1", "1", time.Minute))
+	a.neighbors.Update("eth0", testEthernetFrame("sw2", "1", 2*time.Minute))
+
+	now = now.Add(time.Minute)
+	a.expire()
+
+	if want, got := uint64(1), a.Stats()["eth0"].AgeoutsTotal; want != got {
+		t.Fatalf("unexpected ageouts: %d != %d", want, got)
+	}
+	if want, got := uint64(1), a.Neighbors().Stats().Ageouts; want != got {
+		t.Fatalf("unexpected table ageouts: %d != %d", want, got)
+	}
+}
+
+func TestAgentExpireReadsBonds(t *testing.T) {
+	fsys := fstest.MapFS{
+		"sys/class/net/bond0/bonding/slaves": {Data: []byte("eth0 eth1\n")},
+		"sys/class/net/bond0/bonding/mode":   {Data: []byte("active-backup 1\n")},
+		"sys/class/net/bond0/ifindex":        {Data: []byte("10\n")},
+	}
+
+	a := New(Config{}, host.Collector{FS: fsys}, nil, nil)
+
+	var states []AlarmState
+	a.SetAlarmFunc(func(e AlarmEvent) {
+		states = append(states, e.State)
+	})
+
+	// Until the bonds are read, a neighbor discovered on both slaves raises
+	// an alarm, which clears once the slaves are known to be bonded.
+	for _, ifname := range []string{"eth0", "eth1"} {
+		_, events := a.loops.observe(ifname, testFrame("sw1", ifname, time.Minute, nil))
+		a.alarm(events)
+	}
+	a.expire()
+
+	if want, got := []AlarmState{AlarmRaised, AlarmCleared}, states; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected alarm states: %v != %v", want, got)
+	}
+	if n := len(a.Alarms()); n != 0 {
+		t.Fatalf("expected no alarms, but got %d", n)
+	}
+}
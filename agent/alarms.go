@@ -0,0 +1,414 @@
+package agent
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mdlayher/lldp"
+	"github.com/mdlayher/lldp/host"
+	"github.com/mdlayher/lldp/internal/format"
+)
+
+// An AlarmType is a kind of layer 2 loop or miscabling condition detected by
+// an Agent.
+type AlarmType int
+
+// List of valid AlarmType values.
+const (
+	// AlarmSelfReception indicates that an Agent received a frame carrying
+	// one of its own chassis IDs, so that its frames are looped back to
+	// it.
+	AlarmSelfReception AlarmType = iota
+
+	// AlarmDuplicateNeighbor indicates that the same neighbor was
+	// discovered on more than one local interface, and those interfaces
+	// are not all slaves of the same bond.  For an 802.3ad bond, the alarm
+	// is also raised if the neighbor reports that any of its ports is not
+	// a member of a link aggregation, as both ends must aggregate the
+	// links.  A neighbor's claim of aggregation is not trusted on its own,
+	// as unbonded local interfaces would still loop traffic.
+	AlarmDuplicateNeighbor
+)
+
+// alarmTypeNames maps AlarmType values to their text form.
+var alarmTypeNames = map[AlarmType]string{
+	AlarmSelfReception:     "self-reception",
+	AlarmDuplicateNeighbor: "duplicate-neighbor",
+}
+
+// String returns the text form of an AlarmType.
+func (t AlarmType) String() string {
+	if n, ok := alarmTypeNames[t]; ok {
+		return n
+	}
+
+	return fmt.Sprintf("AlarmType(%d)", int(t))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (t AlarmType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// An AlarmState indicates whether an AlarmEvent raised or cleared an alarm.
+type AlarmState int
+
+// List of valid AlarmState values.
+const (
+	AlarmRaised AlarmState = iota
+	AlarmCleared
+)
+
+// String returns the text form of an AlarmState.
+func (s AlarmState) String() string {
+	switch s {
+	case AlarmRaised:
+		return "raised"
+	case AlarmCleared:
+		return "cleared"
+	default:
+		return fmt.Sprintf("AlarmState(%d)", int(s))
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s AlarmState) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// An Alarm is a loop or miscabling condition detected by an Agent.
+type Alarm struct {
+	Type AlarmType
+
+	// ChassisID specifies the chassis ID carried in the frames which
+	// raised the alarm: a local chassis ID for AlarmSelfReception, or the
+	// neighbor's chassis ID for AlarmDuplicateNeighbor.
+	ChassisID *lldp.ChassisID
+
+	// Interfaces specifies the sorted names of the local interfaces on
+	// which the frames were received.
+	Interfaces []string
+
+	// Raised specifies when the alarm was raised.
+	Raised time.Time
+}
+
+// String returns a description of an Alarm.
+func (a Alarm) String() string {
+	_, chassis := format.ChassisID(a.ChassisID)
+	return fmt.Sprintf("%s of chassis %s on %s", a.Type, chassis, strings.Join(a.Interfaces, ", "))
+}
+
+// An AlarmEvent reports that an Alarm was raised or cleared.
+type AlarmEvent struct {
+	State AlarmState
+	Alarm Alarm
+
+	// Time specifies when the event occurred.
+	Time time.Time
+}
+
+// String returns a description of an AlarmEvent.
+func (e AlarmEvent) String() string {
+	return fmt.Sprintf("alarm %s: %s", e.State, e.Alarm)
+}
+
+// An alarmKey identifies an alarm by its type and chassis ID.
+type alarmKey struct {
+	typ     AlarmType
+	chassis string
+}
+
+// A sightingKey identifies the frames received from a single remote port on
+// a local interface.
+type sightingKey struct {
+	ifname string
+	port   string
+}
+
+// A sighting is the state of the frames received from a single remote port
+// on a local interface.
+type sighting struct {
+	expires time.Time
+
+	// unaggregated reports whether the neighbor reported that its port is
+	// not a member of a link aggregation.
+	unaggregated bool
+}
+
+// An alarmEntry tracks the frames which may raise an alarm.  Each sighting
+// expires when the TTL of its most recent frame elapses, and the alarm
+// clears once its condition no longer holds.
+type alarmEntry struct {
+	chassis   *lldp.ChassisID
+	raised    time.Time
+	sightings map[sightingKey]sighting
+
+	// interfaces are the interfaces of the alarm when its condition last
+	// held, which are reported when it clears.
+	interfaces []string
+}
+
+// A loopDetector detects loops and miscabling from the frames received on
+// each local interface.  It is safe for concurrent use.
+type loopDetector struct {
+	mu      sync.Mutex
+	local   map[string]string
+	bonds   map[string]*host.Bond
+	entries map[alarmKey]*alarmEntry
+	now     func() time.Time
+}
+
+// newLoopDetector creates a loopDetector with no local chassis IDs.
+func newLoopDetector() *loopDetector {
+	return &loopDetector{
+		local:   make(map[string]string),
+		bonds:   make(map[string]*host.Bond),
+		entries: make(map[alarmKey]*alarmEntry),
+		now:     time.Now,
+	}
+}
+
+// setLocal records the chassis ID transmitted on ifname.  Each interface may
+// transmit a different chassis ID, and a chassis ID is local if it has been
+// transmitted on any interface.
+func (d *loopDetector) setLocal(ifname string, id *lldp.ChassisID) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.local[ifname] = format.ChassisIDKey(id)
+}
+
+// removeLocal forgets the chassis ID transmitted on ifname, once the
+// interface no longer transmits.
+func (d *loopDetector) removeLocal(ifname string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.local, ifname)
+}
+
+// setBonds records the bonds of the local system, replacing any bonds
+// recorded previously.  Alarms are reevaluated with the new bonds on the next
+// call to expire.
+func (d *loopDetector) setBonds(bb []*host.Bond) {
+	bonds := make(map[string]*host.Bond)
+	for _, b := range bb {
+		for _, slave := range b.Slaves {
+			bonds[slave] = b
+		}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.bonds = bonds
+}
+
+// isLocal reports whether a chassis key belongs to the local system.  The
+// caller must hold d.mu.
+func (d *loopDetector) isLocal(chassis string) bool {
+	for _, c := range d.local {
+		if c == chassis {
+			return true
+		}
+	}
+
+	return false
+}
+
+// observe records a frame received on ifname, and returns any resulting
+// alarm events.  observe also reports whether the frame carries a local
+// chassis ID, in which case it is not a neighbor's frame.
+//
+// A frame with a TTL of zero is a shutdown frame, and withdraws the
+// information of previous frames from the same remote port instead.
+func (d *loopDetector) observe(ifname string, f *lldp.Frame) (bool, []AlarmEvent) {
+	chassis := format.ChassisIDKey(f.ChassisID)
+	sk := sightingKey{
+		ifname: ifname,
+		port:   format.PortIDKey(f.PortID),
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	k := alarmKey{typ: AlarmDuplicateNeighbor, chassis: chassis}
+	self := d.isLocal(chassis)
+	if self {
+		k.typ = AlarmSelfReception
+	}
+
+	e, ok := d.entries[k]
+	if !ok {
+		if f.TTL == 0 {
+			return self, nil
+		}
+
+		e = &alarmEntry{
+			chassis:   f.ChassisID,
+			sightings: make(map[sightingKey]sighting),
+		}
+		d.entries[k] = e
+	}
+
+	now := d.now()
+	if f.TTL == 0 {
+		delete(e.sightings, sk)
+	} else {
+		la, ok := f.LinkAggregation()
+		e.sightings[sk] = sighting{
+			expires:      now.Add(f.TTL),
+			unaggregated: ok && !la.Enabled,
+		}
+	}
+
+	return self, d.evaluate(k, e, now)
+}
+
+// expire removes sightings whose TTL has elapsed, and returns the events of
+// any alarms which cleared as a result.
+func (d *loopDetector) expire() []AlarmEvent {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+
+	var events []AlarmEvent
+	for k, e := range d.entries {
+		events = append(events, d.evaluate(k, e, now)...)
+	}
+
+	sortAlarmEvents(events)
+	return events
+}
+
+// evaluate removes the expired sightings of an entry and raises or clears
+// its alarm as needed, returning the resulting event, if any.  Entries with
+// no sightings are removed.  The caller must hold d.mu.
+func (d *loopDetector) evaluate(k alarmKey, e *alarmEntry, now time.Time) []AlarmEvent {
+	for sk, s := range e.sightings {
+		if !now.Before(s.expires) {
+			delete(e.sightings, sk)
+		}
+	}
+
+	var (
+		alarm  = alarmFor(k.typ, e)
+		active = len(alarm.Interfaces) > 0
+	)
+	if k.typ == AlarmDuplicateNeighbor {
+		active = len(alarm.Interfaces) > 1 && !d.bonded(alarm.Interfaces, e)
+	}
+
+	if len(e.sightings) == 0 {
+		delete(d.entries, k)
+	}
+
+	if active {
+		e.interfaces = alarm.Interfaces
+	}
+
+	switch {
+	case active && e.raised.IsZero():
+		e.raised = now
+		alarm.Raised = now
+		return []AlarmEvent{{State: AlarmRaised, Alarm: alarm, Time: now}}
+	case !active && !e.raised.IsZero():
+		e.raised = time.Time{}
+		alarm.Interfaces = e.interfaces
+		return []AlarmEvent{{State: AlarmCleared, Alarm: alarm, Time: now}}
+	default:
+		return nil
+	}
+}
+
+// alarms returns the alarms which are currently raised, sorted by type and
+// chassis ID.
+func (d *loopDetector) alarms() []Alarm {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var aa []Alarm
+	for k, e := range d.entries {
+		if !e.raised.IsZero() {
+			aa = append(aa, alarmFor(k.typ, e))
+		}
+	}
+
+	sort.Slice(aa, func(i, j int) bool {
+		return alarmLess(aa[i], aa[j])
+	})
+
+	return aa
+}
+
+// bonded reports whether a neighbor discovered on each of ifnames is expected
+// to be, because the interfaces are all slaves of the same bond and, for an
+// 802.3ad bond, the neighbor does not report any of its ports as not
+// aggregated.  The caller must hold d.mu.
+func (d *loopDetector) bonded(ifnames []string, e *alarmEntry) bool {
+	b := d.bonds[ifnames[0]]
+	if b == nil {
+		return false
+	}
+	for _, ifname := range ifnames[1:] {
+		if d.bonds[ifname] != b {
+			return false
+		}
+	}
+
+	return b.Mode != "802.3ad" || !e.unaggregated()
+}
+
+// unaggregated reports whether the neighbor reported any sighting of an
+// entry as not a member of a link aggregation.
+func (e *alarmEntry) unaggregated() bool {
+	for _, s := range e.sightings {
+		if s.unaggregated {
+			return true
+		}
+	}
+
+	return false
+}
+
+// alarmFor returns the Alarm of an entry.  Its Interfaces are those of the
+// entry's current sightings.
+func alarmFor(typ AlarmType, e *alarmEntry) Alarm {
+	seen := make(map[string]bool, len(e.sightings))
+	var ifnames []string
+	for sk := range e.sightings {
+		if !seen[sk.ifname] {
+			seen[sk.ifname] = true
+			ifnames = append(ifnames, sk.ifname)
+		}
+	}
+	sort.Strings(ifnames)
+
+	return Alarm{
+		Type:       typ,
+		ChassisID:  e.chassis,
+		Interfaces: ifnames,
+		Raised:     e.raised,
+	}
+}
+
+// alarmLess reports whether Alarm a sorts before Alarm b.
+func alarmLess(a, b Alarm) bool {
+	if a.Type != b.Type {
+		return a.Type < b.Type
+	}
+
+	return format.ChassisIDKey(a.ChassisID) < format.ChassisIDKey(b.ChassisID)
+}
+
+// sortAlarmEvents sorts events by their alarms.
+func sortAlarmEvents(events []AlarmEvent) {
+	sort.Slice(events, func(i, j int) bool {
+		return alarmLess(events[i].Alarm, events[j].Alarm)
+	})
+}
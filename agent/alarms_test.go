@@ -0,0 +1,213 @@
+package agent
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/lldp"
+	"github.com/mdlayher/lldp/host"
+)
+
+func TestLoopDetector(t *testing.T) {
+	now := time.Unix(1000, 0)
+	d := newLoopDetector()
+	d.now = func() time.Time { return now }
+
+	// Each port may transmit a different chassis ID.
+	d.setLocal("eth0", &lldp.ChassisID{Subtype: lldp.ChassisIDSubtypeLocallyAssigned, ID: []byte("host1")})
+	d.setLocal("eth1", &lldp.ChassisID{Subtype: lldp.ChassisIDSubtypeLocallyAssigned, ID: []byte("host2")})
+
+	d.setBonds([]*host.Bond{
+		{Name: "bond0", Mode: "802.3ad", Slaves: []string{"eth3", "eth4"}},
+		{Name: "bond1", Mode: "active-backup", Slaves: []string{"eth5", "eth6"}},
+	})
+
+	var (
+		aggregated   = &lldp.LinkAggregation{Capable: true, Enabled: true, PortID: 100}
+		unaggregated = &lldp.LinkAggregation{Capable: true}
+	)
+
+	var tests = []struct {
+		desc    string
+		ifname  string
+		f       *lldp.Frame
+		advance time.Duration
+		self    bool
+		events  []string
+	}{
+		{
+			desc:   "neighbor on eth0",
+			ifname: "eth0",
+			f:      testFrame("sw1", "1", time.Minute, nil),
+		},
+		{
+			desc:   "same neighbor on eth1",
+			ifname: "eth1",
+			f:      testFrame("sw1", "2", time.Minute, nil),
+			events: []string{`alarm raised: duplicate-neighbor of chassis sw1 on eth0, eth1`},
+		},
+		{
+			desc:   "duplicate neighbor refreshed",
+			ifname: "eth1",
+			f:      testFrame("sw1", "2", 2*time.Minute, nil),
+		},
+		{
+			desc:   "own frame on eth1",
+			ifname: "eth1",
+			f:      testFrame("host1", "eth0", 2*time.Minute, nil),
+			self:   true,
+			events: []string{`alarm raised: self-reception of chassis host1 on eth1`},
+		},
+		{
+			desc:   "own frame on another port",
+			ifname: "eth2",
+			f:      testFrame("host1", "eth0", time.Minute, nil),
+			self:   true,
+		},
+		{
+			desc:   "second local chassis ID",
+			ifname: "eth2",
+			f:      testFrame("host2", "eth1", 2*time.Minute, nil),
+			self:   true,
+			events: []string{`alarm raised: self-reception of chassis host2 on eth2`},
+		},
+		{
+			desc:    "TTL of eth0 neighbor expires",
+			advance: time.Minute,
+			events:  []string{`alarm cleared: duplicate-neighbor of chassis sw1 on eth0, eth1`},
+		},
+		{
+			desc:   "shutdown frame clears self-reception",
+			ifname: "eth1",
+			f:      testFrame("host1", "eth0", 0, nil),
+			self:   true,
+			events: []string{`alarm cleared: self-reception of chassis host1 on eth1`},
+		},
+		{
+			desc:   "aggregated neighbor on bonded eth3",
+			ifname: "eth3",
+			f:      testFrame("sw2", "1", time.Minute, aggregated),
+		},
+		{
+			desc:   "aggregated neighbor on bonded eth4",
+			ifname: "eth4",
+			f:      testFrame("sw2", "2", time.Minute, aggregated),
+		},
+		{
+			desc:   "aggregated neighbor leaves aggregation",
+			ifname: "eth4",
+			f:      testFrame("sw2", "2", time.Minute, unaggregated),
+			events: []string{`alarm raised: duplicate-neighbor of chassis sw2 on eth3, eth4`},
+		},
+		{
+			desc:   "unaggregated neighbor on active-backup eth5",
+			ifname: "eth5",
+			f:      testFrame("sw3", "1", time.Minute, unaggregated),
+		},
+		{
+			desc:   "unaggregated neighbor on active-backup eth6",
+			ifname: "eth6",
+			f:      testFrame("sw3", "2", time.Minute, unaggregated),
+		},
+		{
+			desc:   "aggregated neighbor on unbonded eth7",
+			ifname: "eth7",
+			f:      testFrame("sw4", "1", time.Minute, aggregated),
+		},
+		{
+			desc:   "aggregated neighbor on unbonded eth8",
+			ifname: "eth8",
+			f:      testFrame("sw4", "2", time.Minute, aggregated),
+			events: []string{`alarm raised: duplicate-neighbor of chassis sw4 on eth7, eth8`},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		now = now.Add(tt.advance)
+
+		var (
+			self   bool
+			events []AlarmEvent
+		)
+		if tt.f != nil {
+			self, events = d.observe(tt.ifname, tt.f)
+		} else {
+			events = d.expire()
+		}
+
+		if want, got := tt.self, self; want != got {
+			t.Fatalf("unexpected self-reception: %v != %v", want, got)
+		}
+
+		var got []string
+		for _, e := range events {
+			if !e.Time.Equal(now) {
+				t.Fatalf("unexpected event time: %v != %v", now, e.Time)
+			}
+
+			got = append(got, e.String())
+		}
+		if want := tt.events; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected events:\n- want: %q\n-  got: %q", want, got)
+		}
+	}
+
+	var got []string
+	for _, a := range d.alarms() {
+		got = append(got, a.String())
+	}
+
+	want := []string{
+		`self-reception of chassis host2 on eth2`,
+		`duplicate-neighbor of chassis sw2 on eth3, eth4`,
+		`duplicate-neighbor of chassis sw4 on eth7, eth8`,
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected alarms:\n- want: %q\n-  got: %q", want, got)
+	}
+
+	// All remaining frames expire.
+	now = now.Add(2 * time.Minute)
+	if n := len(d.expire()); n != 3 {
+		t.Fatalf("expected 3 cleared alarms, but got %d", n)
+	}
+	if n := len(d.entries); n != 0 {
+		t.Fatalf("expected no entries after expiry, but got %d", n)
+	}
+}
+
+func TestLoopDetectorRemoveLocal(t *testing.T) {
+	d := newLoopDetector()
+	d.setLocal("eth0", &lldp.ChassisID{Subtype: lldp.ChassisIDSubtypeLocallyAssigned, ID: []byte("host1")})
+
+	if self, _ := d.observe("eth1", testFrame("host1", "eth0", time.Minute, nil)); !self {
+		t.Fatal("expected self-reception while eth0 transmits")
+	}
+
+	// Once eth0 stops transmitting, its former chassis ID may belong to
+	// another system.
+	d.removeLocal("eth0")
+
+	if self, _ := d.observe("eth1", testFrame("host1", "eth0", time.Minute, nil)); self {
+		t.Fatal("unexpected self-reception after eth0 stopped transmitting")
+	}
+}
+
+// testFrame produces a Frame with the input chassis ID, port ID, and TTL,
+// optionally carrying a Link Aggregation TLV.
+func testFrame(chassis, port string, ttl time.Duration, la *lldp.LinkAggregation) *lldp.Frame {
+	f := testEthernetFrame(chassis, port, ttl).Frame
+	if la != nil {
+		b, _ := la.MarshalBinary()
+		f.Optional = append(f.Optional, &lldp.TLV{
+			Type:   lldp.TLVTypeOrganizationSpecific,
+			Length: uint16(len(b)),
+			Value:  b,
+		})
+	}
+
+	return f
+}
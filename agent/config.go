@@ -0,0 +1,248 @@
+package agent
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mdlayher/lldp"
+	"github.com/mdlayher/lldp/host"
+)
+
+// Default values used when a PortConfig field is not set.
+const (
+	DefaultTxInterval     = 30 * time.Second
+	DefaultHoldMultiplier = 4
+)
+
+// An AdminStatus specifies whether an Agent transmits and receives LLDP
+// frames on a port.
+type AdminStatus int
+
+// List of valid AdminStatus values.
+const (
+	AdminStatusTxRx AdminStatus = iota
+	AdminStatusTx
+	AdminStatusRx
+	AdminStatusDisabled
+)
+
+// adminStatusNames maps AdminStatus values to their text form.
+var adminStatusNames = map[AdminStatus]string{
+	AdminStatusTxRx:     "both",
+	AdminStatusTx:       "tx",
+	AdminStatusRx:       "rx",
+	AdminStatusDisabled: "disabled",
+}
+
+// String returns the text form of an AdminStatus.
+func (s AdminStatus) String() string {
+	if n, ok := adminStatusNames[s]; ok {
+		return n
+	}
+
+	return fmt.Sprintf("AdminStatus(%d)", int(s))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s AdminStatus) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText parses one of "both", "tx", "rx", or "disabled" into an
+// AdminStatus.
+func (s *AdminStatus) UnmarshalText(b []byte) error {
+	for k, v := range adminStatusNames {
+		if v == string(b) {
+			*s = k
+			return nil
+		}
+	}
+
+	return fmt.Errorf("agent: unknown admin status %q", string(b))
+}
+
+// tx and rx report whether an AdminStatus enables transmit and receive.
+func (s AdminStatus) tx() bool { return s == AdminStatusTxRx || s == AdminStatusTx }
+func (s AdminStatus) rx() bool { return s == AdminStatusTxRx || s == AdminStatusRx }
+
+// A TLVs is a bit mask which selects the optional TLVs an Agent transmits.
+type TLVs uint
+
+// List of valid TLVs values.
+const (
+	TLVPortDescription TLVs = 1 << iota
+	TLVSystemName
+	TLVSystemDescription
+	TLVSystemCapabilities
+	TLVManagementAddress
+	TLVMACPHY
+	TLVLinkAggregation
+	TLVVLAN
+	TLVInventory
+)
+
+// DefaultTLVs is the set of optional TLVs transmitted by default.
+const DefaultTLVs = TLVPortDescription | TLVSystemName | TLVSystemDescription |
+	TLVSystemCapabilities | TLVManagementAddress | TLVMACPHY | TLVLinkAggregation
+
+// tlvNames maps each TLVs value to its text form.
+var tlvNames = map[TLVs]string{
+	TLVPortDescription:    "port-description",
+	TLVSystemName:         "system-name",
+	TLVSystemDescription:  "system-description",
+	TLVSystemCapabilities: "system-capabilities",
+	TLVManagementAddress:  "management-address",
+	TLVMACPHY:             "mac-phy",
+	TLVLinkAggregation:    "link-aggregation",
+	TLVVLAN:               "vlan",
+	TLVInventory:          "inventory",
+}
+
+// ParseTLVs parses a list of TLV names into a TLVs.  Valid names are
+// "port-description", "system-name", "system-description",
+// "system-capabilities", "management-address", "mac-phy",
+// "link-aggregation", "vlan", and "inventory".
+func ParseTLVs(names []string) (TLVs, error) {
+	var t TLVs
+
+outer:
+	for _, n := range names {
+		for k, v := range tlvNames {
+			if v == strings.TrimSpace(n) {
+				t |= k
+				continue outer
+			}
+		}
+
+		return 0, fmt.Errorf("agent: unknown TLV %q", n)
+	}
+
+	return t, nil
+}
+
+// Strings returns the names of each TLV selected by a TLVs, in sorted order.
+func (t TLVs) Strings() []string {
+	var ss []string
+	for k, v := range tlvNames {
+		if t&k != 0 {
+			ss = append(ss, v)
+		}
+	}
+
+	sort.Strings(ss)
+	return ss
+}
+
+// selected determines if an optional TLV is selected for transmission.
+func (t TLVs) selected(tlv *lldp.TLV) bool {
+	switch tlv.Type {
+	case lldp.TLVTypePortDescription:
+		return t&TLVPortDescription != 0
+	case lldp.TLVTypeSystemName:
+		return t&TLVSystemName != 0
+	case lldp.TLVTypeSystemDescription:
+		return t&TLVSystemDescription != 0
+	case lldp.TLVTypeSystemCapabilities:
+		return t&TLVSystemCapabilities != 0
+	case lldp.TLVTypeManagementAddress:
+		return t&TLVManagementAddress != 0
+	case lldp.TLVTypeOrganizationSpecific:
+	default:
+		return true
+	}
+
+	o := new(lldp.OrganizationSpecific)
+	if err := o.UnmarshalBinary(tlv.Value); err != nil {
+		return true
+	}
+
+	switch {
+	case o.OUI == lldp.OUIIEEE8023 && o.Subtype == lldp.IEEE8023SubtypeMACPHY:
+		return t&TLVMACPHY != 0
+	case o.OUI == lldp.OUIIEEE8021 && o.Subtype == lldp.IEEE8021SubtypeLinkAggregation:
+		return t&TLVLinkAggregation != 0
+	case o.OUI == lldp.OUIIEEE8021 && o.Subtype <= lldp.IEEE8021SubtypeVLANName:
+		return t&TLVVLAN != 0
+	case o.OUI == lldp.OUITIA:
+		return t&TLVInventory != 0
+	default:
+		return true
+	}
+}
+
+// A PortConfig specifies how an Agent operates on a single port.
+type PortConfig struct {
+	// AdminStatus specifies whether LLDP frames are transmitted and
+	// received on the port.
+	AdminStatus AdminStatus
+
+	// TxInterval specifies the interval between transmitted frames.  If
+	// zero, DefaultTxInterval is used.
+	TxInterval time.Duration
+
+	// HoldMultiplier specifies the multiple of TxInterval which is
+	// advertised as the TTL of transmitted frames.  If zero,
+	// DefaultHoldMultiplier is used.
+	HoldMultiplier int
+
+	// TLVs specifies the optional TLVs which are transmitted.
+	TLVs TLVs
+
+	// ChassisID specifies the policy used to select the chassis ID.  If
+	// nil, host.DefaultChassisID is used.
+	ChassisID host.ChassisIDPolicy
+}
+
+// txInterval returns the transmit interval of a PortConfig.
+func (c PortConfig) txInterval() time.Duration {
+	if c.TxInterval <= 0 {
+		return DefaultTxInterval
+	}
+
+	return c.TxInterval
+}
+
+// ttl returns the TTL of frames transmitted using a PortConfig, which is
+// limited to the maximum TTL which can be carried in a Frame.
+func (c PortConfig) ttl() time.Duration {
+	hold := c.HoldMultiplier
+	if hold <= 0 {
+		hold = DefaultHoldMultiplier
+	}
+
+	ttl := c.txInterval() * time.Duration(hold)
+	if max := math.MaxUint16 * time.Second; ttl > max {
+		ttl = max
+	}
+
+	// TTLs are carried in whole seconds, so round up to avoid advertising
+	// a TTL of zero, which would indicate a shutdown.
+	return (ttl + time.Second - 1).Truncate(time.Second)
+}
+
+// A Config specifies the ports on which an Agent operates, keyed by
+// interface name.
+type Config struct {
+	Ports map[string]PortConfig
+
+	// MaxNeighbors specifies the maximum number of neighbors stored in the
+	// neighbor table.  If zero, the number of neighbors is unlimited.
+	MaxNeighbors int
+}
+
+// clone returns a copy of a Config, so that an Agent is unaffected by later
+// changes to the caller's Config.
+func (c Config) clone() Config {
+	ports := make(map[string]PortConfig, len(c.Ports))
+	for k, v := range c.Ports {
+		ports[k] = v
+	}
+
+	return Config{
+		Ports:        ports,
+		MaxNeighbors: c.MaxNeighbors,
+	}
+}
@@ -0,0 +1,179 @@
+package agent
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/lldp"
+)
+
+func TestAdminStatusText(t *testing.T) {
+	for _, s := range []AdminStatus{
+		AdminStatusTxRx,
+		AdminStatusTx,
+		AdminStatusRx,
+		AdminStatusDisabled,
+	} {
+		b, err := s.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got AdminStatus
+		if err := got.UnmarshalText(b); err != nil {
+			t.Fatal(err)
+		}
+
+		if s != got {
+			t.Fatalf("unexpected AdminStatus: %v != %v", s, got)
+		}
+	}
+
+	var s AdminStatus
+	if err := s.UnmarshalText([]byte("sometimes")); err == nil {
+		t.Fatal("expected an error for unknown admin status")
+	}
+}
+
+func TestParseTLVs(t *testing.T) {
+	var tests = []struct {
+		desc  string
+		names []string
+		t     TLVs
+		ok    bool
+	}{
+		{
+			desc: "empty",
+			ok:   true,
+		},
+		{
+			desc:  "unknown",
+			names: []string{"system-name", "power"},
+		},
+		{
+			desc:  "OK",
+			names: []string{"system-name", " vlan ", "mac-phy"},
+			t:     TLVSystemName | TLVVLAN | TLVMACPHY,
+			ok:    true,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		got, err := ParseTLVs(tt.names)
+		if err != nil {
+			if tt.ok {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			continue
+		}
+		if !tt.ok {
+			t.Fatal("expected an error, but none occurred")
+		}
+
+		if want := tt.t; want != got {
+			t.Fatalf("unexpected TLVs: %v != %v", want, got)
+		}
+	}
+
+	want := []string{"mac-phy", "system-name", "vlan"}
+	if got := (TLVSystemName | TLVVLAN | TLVMACPHY).Strings(); !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected TLV names:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+func TestTLVsSelected(t *testing.T) {
+	org := func(oui lldp.OUI, subtype uint8) *lldp.TLV {
+		b, _ := (&lldp.OrganizationSpecific{OUI: oui, Subtype: subtype}).MarshalBinary()
+		return &lldp.TLV{Type: lldp.TLVTypeOrganizationSpecific, Value: b}
+	}
+
+	var tests = []struct {
+		desc string
+		tlv  *lldp.TLV
+		t    TLVs
+	}{
+		{
+			desc: "system name",
+			tlv:  &lldp.TLV{Type: lldp.TLVTypeSystemName},
+			t:    TLVSystemName,
+		},
+		{
+			desc: "MAC/PHY",
+			tlv:  org(lldp.OUIIEEE8023, lldp.IEEE8023SubtypeMACPHY),
+			t:    TLVMACPHY,
+		},
+		{
+			desc: "link aggregation",
+			tlv:  org(lldp.OUIIEEE8021, lldp.IEEE8021SubtypeLinkAggregation),
+			t:    TLVLinkAggregation,
+		},
+		{
+			desc: "VLAN name",
+			tlv:  org(lldp.OUIIEEE8021, lldp.IEEE8021SubtypeVLANName),
+			t:    TLVVLAN,
+		},
+		{
+			desc: "LLDP-MED",
+			tlv:  org(lldp.OUITIA, lldp.TIASubtypeCapabilities),
+			t:    TLVInventory,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		if !tt.t.selected(tt.tlv) {
+			t.Fatalf("expected TLV to be selected by %v", tt.t.Strings())
+		}
+		if (^tt.t).selected(tt.tlv) {
+			t.Fatal("expected TLV not to be selected by all other TLVs")
+		}
+	}
+
+	// TLVs which cannot be configured are always selected.
+	if !TLVs(0).selected(org(lldp.OUI{0x00, 0x00, 0x5e}, 1)) {
+		t.Fatal("expected unknown organizationally specific TLV to be selected")
+	}
+}
+
+func TestPortConfigTTL(t *testing.T) {
+	var tests = []struct {
+		desc string
+		c    PortConfig
+		ttl  time.Duration
+	}{
+		{
+			desc: "defaults",
+			ttl:  120 * time.Second,
+		},
+		{
+			desc: "sub-second",
+			c: PortConfig{
+				TxInterval:     100 * time.Millisecond,
+				HoldMultiplier: 2,
+			},
+			ttl: time.Second,
+		},
+		{
+			desc: "maximum",
+			c: PortConfig{
+				TxInterval:     time.Hour,
+				HoldMultiplier: 100,
+			},
+			ttl: math.MaxUint16 * time.Second,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		if want, got := tt.ttl, tt.c.ttl(); want != got {
+			t.Fatalf("unexpected TTL: %v != %v", want, got)
+		}
+	}
+}
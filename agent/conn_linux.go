@@ -0,0 +1,143 @@
+//go:build linux
+
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"unsafe"
+
+	"github.com/mdlayher/ethernet"
+	"github.com/mdlayher/lldp"
+	"github.com/mdlayher/packet"
+)
+
+// Listen opens a Conn on the named interface using a Linux packet socket.
+// The socket joins each of the LLDP destination multicast groups, so that
+// LLDP frames are received without placing the interface in promiscuous
+// mode.
+func Listen(ifname string) (Conn, error) {
+	ifi, err := net.InterfaceByName(ifname)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := packet.Listen(ifi, packet.Raw, int(lldp.EtherType), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := joinLLDPGroups(c, ifi.Index); err != nil {
+		_ = c.Close()
+		return nil, err
+	}
+
+	return &packetConn{
+		c:   c,
+		ifi: ifi,
+	}, nil
+}
+
+// A packetConn is a Conn backed by a packet socket.
+type packetConn struct {
+	c   *packet.Conn
+	ifi *net.Interface
+}
+
+// ReadFrame implements Conn.
+func (c *packetConn) ReadFrame() (*ethernet.Frame, error) {
+	// LLDP frames are infrequent, so allocate a buffer for each frame
+	// rather than sharing one which would be retained by the Frame.
+	b := make([]byte, c.ifi.MTU+18)
+	n, _, err := c.c.ReadFrom(b)
+	if err != nil {
+		// The packet socket reports a closed file rather than a closed
+		// network connection.
+		if errors.Is(err, os.ErrClosed) {
+			return nil, fmt.Errorf("%w: %v", net.ErrClosed, err)
+		}
+
+		return nil, err
+	}
+
+	f := new(ethernet.Frame)
+	if err := f.UnmarshalBinary(b[:n]); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedFrame, err)
+	}
+
+	return f, nil
+}
+
+// WriteFrame implements Conn.
+func (c *packetConn) WriteFrame(f *ethernet.Frame) error {
+	if f.Source == nil {
+		f.Source = c.ifi.HardwareAddr
+	}
+
+	b, err := f.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	_, err = c.c.WriteTo(b, &packet.Addr{HardwareAddr: f.Destination})
+	return err
+}
+
+// Close implements Conn.
+func (c *packetConn) Close() error {
+	return c.c.Close()
+}
+
+// packetMreq is a struct packet_mreq.
+type packetMreq struct {
+	Ifindex int32
+	Type    uint16
+	Alen    uint16
+	Address [8]byte
+}
+
+// joinLLDPGroups adds the LLDP destination MAC addresses to the multicast
+// filter of an interface for the lifetime of a packet socket.
+func joinLLDPGroups(c *packet.Conn, index int) error {
+	rc, err := c.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	for _, addr := range []net.HardwareAddr{
+		lldp.NearestBridge,
+		lldp.NearestNonTPMRBridge,
+		lldp.NearestCustomerBridge,
+	} {
+		mreq := packetMreq{
+			Ifindex: int32(index),
+			Type:    syscall.PACKET_MR_MULTICAST,
+			Alen:    uint16(len(addr)),
+		}
+		copy(mreq.Address[:], addr)
+
+		var errno syscall.Errno
+		err := rc.Control(func(fd uintptr) {
+			_, _, errno = syscall.Syscall6(
+				syscall.SYS_SETSOCKOPT,
+				fd,
+				syscall.SOL_PACKET,
+				syscall.PACKET_ADD_MEMBERSHIP,
+				uintptr(unsafe.Pointer(&mreq)),
+				unsafe.Sizeof(mreq),
+				0,
+			)
+		})
+		if err != nil {
+			return err
+		}
+		if errno != 0 {
+			return fmt.Errorf("agent: failed to join multicast group %s: %w", addr, errno)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+
+package agent
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// Listen opens a Conn on the named interface using a Linux packet socket.
+// On platforms other than Linux, Listen always returns an error.
+func Listen(ifname string) (Conn, error) {
+	return nil, fmt.Errorf("agent: listen on %q: not implemented on %s", ifname, runtime.GOOS)
+}
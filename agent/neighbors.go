@@ -0,0 +1,271 @@
+package agent
+
+import (
+	"net"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mdlayher/lldp"
+	"github.com/mdlayher/lldp/internal/format"
+)
+
+// A Neighbor is a remote system discovered by receiving LLDP frames on a
+// local interface.
+type Neighbor struct {
+	// Interface specifies the name of the local interface on which the
+	// neighbor was discovered.
+	Interface string
+
+	// Source specifies the source MAC address of the neighbor's most
+	// recent frame.
+	Source net.HardwareAddr
+
+	// Frame specifies the neighbor's most recent frame.
+	Frame *lldp.Frame
+
+	// Unrecognized specifies any TLVs with reserved types carried in the
+	// neighbor's most recent frame, which are omitted from Frame.
+	Unrecognized []*lldp.TLV
+
+	// Discovered specifies when the neighbor was inserted into the table.
+	Discovered time.Time
+
+	// Updated and Expires specify when the neighbor's most recent frame
+	// was received, and when its information will expire.
+	Updated time.Time
+	Expires time.Time
+}
+
+// A Change describes the effect of an update on a NeighborTable.
+type Change int
+
+// List of valid Change values.
+const (
+	// ChangeNone indicates that an existing neighbor was refreshed
+	// without changing its information.
+	ChangeNone Change = iota
+
+	// ChangeInserted indicates that a new neighbor was inserted.
+	ChangeInserted
+
+	// ChangeModified indicates that the information of an existing
+	// neighbor changed.
+	ChangeModified
+
+	// ChangeDeleted indicates that a neighbor was deleted by a shutdown
+	// frame.
+	ChangeDeleted
+
+	// ChangeDropped indicates that a new neighbor was not inserted
+	// because the table was full.
+	ChangeDropped
+)
+
+// neighborKey identifies a neighbor by its local interface and MAC service
+// access point (MSAP) identifier: the combination of its chassis ID and port
+// ID.
+type neighborKey struct {
+	ifname  string
+	chassis string
+	port    string
+}
+
+// newNeighborKey creates a neighborKey for a Frame received on ifname.
+func newNeighborKey(ifname string, f *lldp.Frame) neighborKey {
+	return neighborKey{
+		ifname:  ifname,
+		chassis: format.ChassisIDKey(f.ChassisID),
+		port:    format.PortIDKey(f.PortID),
+	}
+}
+
+// A NeighborTable stores the Neighbors discovered on each local interface.
+// It is safe for concurrent use.
+type NeighborTable struct {
+	mu    sync.Mutex
+	m     map[neighborKey]*Neighbor
+	max   int
+	stats TableStats
+	now   func() time.Time
+}
+
+// NewNeighborTable creates an empty NeighborTable.
+func NewNeighborTable() *NeighborTable {
+	return &NeighborTable{
+		m:   make(map[neighborKey]*Neighbor),
+		now: time.Now,
+	}
+}
+
+// SetMax sets the maximum number of neighbors stored.  Once the limit is
+// reached, new neighbors are dropped until existing neighbors are removed.
+// If n is zero, the number of neighbors is unlimited.  Neighbors already
+// stored are never removed by SetMax.
+func (t *NeighborTable) SetMax(n int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.max = n
+}
+
+// Update stores the information carried in an LLDP frame received on
+// ifname, and reports the resulting Change.  TLVs with reserved types are
+// moved from the stored Frame's optional TLVs to the Neighbor's Unrecognized
+// TLVs.
+//
+// A frame with a TTL of zero is a shutdown frame, and removes its neighbor
+// from the table instead.
+func (t *NeighborTable) Update(ifname string, ef *lldp.EthernetFrame) Change {
+	k := newNeighborKey(ifname, ef.Frame)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	old, ok := t.m[k]
+	if ef.Frame.TTL == 0 {
+		if !ok {
+			return ChangeNone
+		}
+
+		delete(t.m, k)
+		t.stats.Deletes++
+		t.stats.LastChangeTime = now
+		return ChangeDeleted
+	}
+
+	if !ok && t.max > 0 && len(t.m) >= t.max {
+		t.stats.Drops++
+		t.stats.LastChangeTime = now
+		return ChangeDropped
+	}
+
+	f := *ef.Frame
+	var unrecognized []*lldp.TLV
+	f.Optional, unrecognized = splitUnrecognized(f.Optional)
+
+	n := &Neighbor{
+		Interface:    ifname,
+		Source:       ef.Source,
+		Frame:        &f,
+		Unrecognized: unrecognized,
+		Discovered:   now,
+		Updated:      now,
+		Expires:      now.Add(f.TTL),
+	}
+	if ok {
+		n.Discovered = old.Discovered
+	}
+	t.m[k] = n
+
+	switch {
+	case !ok:
+		t.stats.Inserts++
+		t.stats.LastChangeTime = now
+		return ChangeInserted
+	case !reflect.DeepEqual(old.Frame.Optional, n.Frame.Optional) ||
+		!reflect.DeepEqual(old.Unrecognized, n.Unrecognized):
+		t.stats.LastChangeTime = now
+		return ChangeModified
+	default:
+		return ChangeNone
+	}
+}
+
+// splitUnrecognized separates TLVs with reserved types from all others.
+func splitUnrecognized(tt []*lldp.TLV) (recognized, unrecognized []*lldp.TLV) {
+	for _, t := range tt {
+		if t.Unrecognized() {
+			unrecognized = append(unrecognized, t)
+		} else {
+			recognized = append(recognized, t)
+		}
+	}
+
+	return recognized, unrecognized
+}
+
+// Expire removes and returns the Neighbors whose information has expired.
+func (t *NeighborTable) Expire() []*Neighbor {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+
+	var expired []*Neighbor
+	for k, n := range t.m {
+		if !now.Before(n.Expires) {
+			expired = append(expired, n)
+			delete(t.m, k)
+		}
+	}
+
+	if len(expired) > 0 {
+		t.stats.Ageouts += uint64(len(expired))
+		t.stats.LastChangeTime = now
+	}
+
+	sortNeighbors(expired)
+	return expired
+}
+
+// RemoveInterface removes all Neighbors discovered on ifname.  Each removed
+// neighbor is counted as a deletion.
+func (t *NeighborTable) RemoveInterface(ifname string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for k := range t.m {
+		if k.ifname == ifname {
+			delete(t.m, k)
+			t.stats.Deletes++
+			t.stats.LastChangeTime = t.now()
+		}
+	}
+}
+
+// Neighbors returns the Neighbors whose information has not expired, sorted
+// by local interface name and then by chassis ID and port ID.
+func (t *NeighborTable) Neighbors() []*Neighbor {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+
+	nn := make([]*Neighbor, 0, len(t.m))
+	for _, n := range t.m {
+		if now.Before(n.Expires) {
+			nn = append(nn, n)
+		}
+	}
+
+	sortNeighbors(nn)
+	return nn
+}
+
+// Stats returns the statistics of a NeighborTable.
+func (t *NeighborTable) Stats() TableStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.stats
+}
+
+// sortNeighbors sorts Neighbors by their keys.
+func sortNeighbors(nn []*Neighbor) {
+	sort.Slice(nn, func(i, j int) bool {
+		ki := newNeighborKey(nn[i].Interface, nn[i].Frame)
+		kj := newNeighborKey(nn[j].Interface, nn[j].Frame)
+
+		switch {
+		case ki.ifname != kj.ifname:
+			return ki.ifname < kj.ifname
+		case ki.chassis != kj.chassis:
+			return ki.chassis < kj.chassis
+		default:
+			return ki.port < kj.port
+		}
+	})
+}
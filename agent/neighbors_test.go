@@ -0,0 +1,244 @@
+package agent
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/lldp"
+)
+
+func TestNeighborTable(t *testing.T) {
+	now := time.Unix(1000, 0)
+	nt := NewNeighborTable()
+	nt.now = func() time.Time { return now }
+
+	var tests = []struct {
+		desc    string
+		ifname  string
+		f       *lldp.EthernetFrame
+		advance time.Duration
+		change  Change
+		n       int
+		expired int
+	}{
+		{
+			desc:   "new neighbor on eth0",
+			ifname: "eth0",
+			f:      testEthernetFrame("sw1", "1", 120*time.Second),
+			change: ChangeInserted,
+			n:      1,
+		},
+		{
+			desc:   "refresh existing neighbor",
+			ifname: "eth0",
+			f:      testEthernetFrame("sw1", "1", 30*time.Second),
+			n:      1,
+		},
+		{
+			desc:   "same MSAP on another interface",
+			ifname: "eth1",
+			f:      testEthernetFrame("sw1", "1", 120*time.Second),
+			change: ChangeInserted,
+			n:      2,
+		},
+		{
+			desc:   "another port of the same chassis",
+			ifname: "eth1",
+			f:      testEthernetFrame("sw1", "2", 120*time.Second),
+			change: ChangeInserted,
+			n:      3,
+		},
+		{
+			desc:    "eth0 neighbor expires",
+			advance: 30 * time.Second,
+			n:       2,
+			expired: 1,
+		},
+		{
+			desc:   "shutdown frame removes neighbor",
+			ifname: "eth1",
+			f:      testEthernetFrame("sw1", "2", 0),
+			change: ChangeDeleted,
+			n:      1,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		now = now.Add(tt.advance)
+
+		if tt.f != nil {
+			if want, got := tt.change, nt.Update(tt.ifname, tt.f); want != got {
+				t.Fatalf("unexpected change: %v != %v", want, got)
+			}
+		}
+
+		if want, got := tt.expired, len(nt.Expire()); want != got {
+			t.Fatalf("unexpected number of expired neighbors: %d != %d", want, got)
+		}
+		if want, got := tt.n, len(nt.Neighbors()); want != got {
+			t.Fatalf("unexpected number of neighbors: %d != %d", want, got)
+		}
+	}
+
+	nt.RemoveInterface("eth1")
+	if n := len(nt.Neighbors()); n != 0 {
+		t.Fatalf("expected no neighbors after removing eth1, but got %d", n)
+	}
+
+	want := TableStats{
+		LastChangeTime: now,
+		Inserts:        3,
+		Deletes:        2,
+		Ageouts:        1,
+	}
+	if got := nt.Stats(); want != got {
+		t.Fatalf("unexpected stats:\n- want: %+v\n-  got: %+v", want, got)
+	}
+}
+
+func TestNeighborTableMax(t *testing.T) {
+	nt := NewNeighborTable()
+	nt.SetMax(1)
+
+	var tests = []struct {
+		desc   string
+		ifname string
+		f      *lldp.EthernetFrame
+		change Change
+	}{
+		{
+			desc:   "first neighbor inserted",
+			ifname: "eth0",
+			f:      testEthernetFrame("sw1", "1", time.Minute),
+			change: ChangeInserted,
+		},
+		{
+			desc:   "second neighbor dropped",
+			ifname: "eth1",
+			f:      testEthernetFrame("sw2", "1", time.Minute),
+			change: ChangeDropped,
+		},
+		{
+			desc:   "existing neighbor refreshed",
+			ifname: "eth0",
+			f:      testEthernetFrame("sw1", "1", time.Minute),
+			change: ChangeNone,
+		},
+		{
+			desc:   "existing neighbor removed",
+			ifname: "eth0",
+			f:      testEthernetFrame("sw1", "1", 0),
+			change: ChangeDeleted,
+		},
+		{
+			desc:   "second neighbor inserted",
+			ifname: "eth1",
+			f:      testEthernetFrame("sw2", "1", time.Minute),
+			change: ChangeInserted,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		if want, got := tt.change, nt.Update(tt.ifname, tt.f); want != got {
+			t.Fatalf("unexpected change: %v != %v", want, got)
+		}
+	}
+
+	s := nt.Stats()
+	if want, got := [3]uint64{2, 1, 1}, [3]uint64{s.Inserts, s.Deletes, s.Drops}; want != got {
+		t.Fatalf("unexpected inserts, deletes, and drops: %v != %v", want, got)
+	}
+}
+
+func TestNeighborTableUnrecognized(t *testing.T) {
+	nt := NewNeighborTable()
+
+	unknown := &lldp.TLV{Type: 9, Length: 1, Value: []byte{1}}
+	name := &lldp.TLV{Type: lldp.TLVTypeSystemName, Length: 3, Value: []byte("sw1")}
+
+	f := testEthernetFrame("sw1", "1", time.Minute)
+	f.Frame.Optional = []*lldp.TLV{unknown, name}
+
+	if want, got := ChangeInserted, nt.Update("eth0", f); want != got {
+		t.Fatalf("unexpected change: %v != %v", want, got)
+	}
+	if n := len(f.Frame.Optional); n != 2 {
+		t.Fatalf("input frame was modified: %d optional TLVs", n)
+	}
+
+	n := nt.Neighbors()[0]
+	if len(n.Frame.Optional) != 1 || n.Frame.Optional[0] != name {
+		t.Fatalf("unexpected optional TLVs: %v", n.Frame.Optional)
+	}
+	if len(n.Unrecognized) != 1 || n.Unrecognized[0] != unknown {
+		t.Fatalf("unexpected unrecognized TLVs: %v", n.Unrecognized)
+	}
+
+	f.Frame.Optional = []*lldp.TLV{name}
+	if want, got := ChangeModified, nt.Update("eth0", f); want != got {
+		t.Fatalf("unexpected change: %v != %v", want, got)
+	}
+}
+
+func TestNeighborTableNeighborsSorted(t *testing.T) {
+	nt := NewNeighborTable()
+	nt.Update("eth1", testEthernetFrame("sw1", "1", time.Minute))
+	nt.Update("eth0", testEthernetFrame("sw2", "1", time.Minute))
+	nt.Update("eth0", testEthernetFrame("sw1", "2", time.Minute))
+	nt.Update("eth0", testEthernetFrame("sw1", "1", time.Minute))
+
+	want := []string{"eth0 sw1 1", "eth0 sw1 2", "eth0 sw2 1", "eth1 sw1 1"}
+
+	nn := nt.Neighbors()
+	if len(nn) != len(want) {
+		t.Fatalf("unexpected number of neighbors: %d != %d", len(want), len(nn))
+	}
+
+	for i, n := range nn {
+		got := n.Interface + " " + string(n.Frame.ChassisID.ID) + " " + string(n.Frame.PortID.ID)
+		if want[i] != got {
+			t.Fatalf("unexpected neighbor %d: %q != %q", i, want[i], got)
+		}
+	}
+}
+
+// testEthernetFrame produces an EthernetFrame carrying a Frame with the input
+// chassis ID, port ID, and TTL.
+func testEthernetFrame(chassis, port string, ttl time.Duration) *lldp.EthernetFrame {
+	return &lldp.EthernetFrame{
+		Destination: lldp.NearestBridge,
+		Source:      net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad},
+		Frame: &lldp.Frame{
+			ChassisID: &lldp.ChassisID{
+				Subtype: lldp.ChassisIDSubtypeLocallyAssigned,
+				ID:      []byte(chassis),
+			},
+			PortID: &lldp.PortID{
+				Subtype: lldp.PortIDSubtypeInterfaceName,
+				ID:      []byte(port),
+			},
+			TTL: ttl,
+		},
+	}
+}
+
+func TestNeighborTableDiscovered(t *testing.T) {
+	now := time.Unix(1000, 0)
+	nt := NewNeighborTable()
+	nt.now = func() time.Time { return now }
+
+	start := now
+	nt.Update("eth0", testEthernetFrame("sw1", "1", time.Minute))
+	now = now.Add(30 * time.Second)
+	nt.Update("eth0", testEthernetFrame("sw1", "1", time.Minute))
+
+	n := nt.Neighbors()[0]
+	if !n.Discovered.Equal(start) || !n.Updated.Equal(now) {
+		t.Fatalf("unexpected discovered and updated times: %v, %v", n.Discovered, n.Updated)
+	}
+}
@@ -0,0 +1,84 @@
+package agent
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// PortStats contains the statistics of a single port, as defined by the
+// lldpV2StatsTxPortTable and lldpV2StatsRxPortTable of the LLDP-V2-MIB.
+type PortStats struct {
+	// FramesOutTotal specifies the number of frames transmitted.
+	FramesOutTotal uint64 `json:"frames_out_total"`
+
+	// FramesInTotal specifies the number of valid frames received.
+	FramesInTotal uint64 `json:"frames_in_total"`
+
+	// FramesDiscardedTotal specifies the number of received frames which
+	// were discarded for any reason.
+	FramesDiscardedTotal uint64 `json:"frames_discarded_total"`
+
+	// FramesInErrorsTotal specifies the number of received frames which
+	// were discarded because they could not be decoded.
+	FramesInErrorsTotal uint64 `json:"frames_in_errors_total"`
+
+	// TLVsDiscardedTotal specifies the number of malformed or duplicate
+	// optional TLVs discarded from valid frames.
+	TLVsDiscardedTotal uint64 `json:"tlvs_discarded_total"`
+
+	// TLVsUnrecognizedTotal specifies the number of TLVs with reserved
+	// types received in valid frames.
+	TLVsUnrecognizedTotal uint64 `json:"tlvs_unrecognized_total"`
+
+	// AgeoutsTotal specifies the number of neighbors discovered on the
+	// port whose information expired.
+	AgeoutsTotal uint64 `json:"ageouts_total"`
+}
+
+// portCounters are the counters backing PortStats, updated concurrently by
+// a port's transmit and receive goroutines.
+type portCounters struct {
+	framesOutTotal        atomic.Uint64
+	framesInTotal         atomic.Uint64
+	framesDiscardedTotal  atomic.Uint64
+	framesInErrorsTotal   atomic.Uint64
+	tlvsDiscardedTotal    atomic.Uint64
+	tlvsUnrecognizedTotal atomic.Uint64
+	ageoutsTotal          atomic.Uint64
+}
+
+// snapshot returns the current values of the counters as a PortStats.
+func (c *portCounters) snapshot() PortStats {
+	return PortStats{
+		FramesOutTotal:        c.framesOutTotal.Load(),
+		FramesInTotal:         c.framesInTotal.Load(),
+		FramesDiscardedTotal:  c.framesDiscardedTotal.Load(),
+		FramesInErrorsTotal:   c.framesInErrorsTotal.Load(),
+		TLVsDiscardedTotal:    c.tlvsDiscardedTotal.Load(),
+		TLVsUnrecognizedTotal: c.tlvsUnrecognizedTotal.Load(),
+		AgeoutsTotal:          c.ageoutsTotal.Load(),
+	}
+}
+
+// TableStats contains the statistics of a NeighborTable, as defined by the
+// lldpV2Statistics group of the LLDP-V2-MIB.
+type TableStats struct {
+	// LastChangeTime specifies when a neighbor was last inserted,
+	// modified, deleted, dropped, or aged out.
+	LastChangeTime time.Time `json:"last_change_time"`
+
+	// Inserts specifies the number of neighbors inserted.
+	Inserts uint64 `json:"inserts"`
+
+	// Deletes specifies the number of neighbors deleted, by receiving
+	// a shutdown frame or by reconfiguration.
+	Deletes uint64 `json:"deletes"`
+
+	// Drops specifies the number of neighbors which could not be inserted
+	// because the table was full.
+	Drops uint64 `json:"drops"`
+
+	// Ageouts specifies the number of neighbors whose information
+	// expired.
+	Ageouts uint64 `json:"ageouts"`
+}
@@ -0,0 +1,395 @@
+package agentx
+
+import (
+	"encoding/asn1"
+	"sort"
+	"time"
+
+	"github.com/mdlayher/lldp"
+	"github.com/mdlayher/lldp/agent"
+)
+
+// Object identifiers from the LLDP-V2-MIB, defined in IEEE 802.1AB-2016,
+// clause 11.
+var (
+	lldpV2MIB             = oid{1, 3, 111, 2, 802, 1, 1, 13}
+	lldpV2RemTablesChange = lldpV2MIB.append(0, 0, 1)
+	lldpV2Statistics      = lldpV2MIB.append(1, 2)
+	lldpV2LocalSystemData = lldpV2MIB.append(1, 3)
+	lldpV2RemTable        = lldpV2MIB.append(1, 4, 1)
+	lldpV2RemManAddrTable = lldpV2MIB.append(1, 4, 2)
+
+	lldpV2StatsRemTablesInserts = lldpV2Statistics.append(2, 0)
+	lldpV2StatsRemTablesDeletes = lldpV2Statistics.append(3, 0)
+	lldpV2StatsRemTablesDrops   = lldpV2Statistics.append(4, 0)
+	lldpV2StatsRemTablesAgeouts = lldpV2Statistics.append(5, 0)
+
+	// subtrees are the subtrees registered with the master agent.
+	subtrees = []oid{
+		lldpV2Statistics,
+		lldpV2LocalSystemData,
+		lldpV2RemTable,
+		lldpV2RemManAddrTable,
+	}
+)
+
+// Object identifiers from SNMPv2-MIB, used in notifications.
+var (
+	sysUpTime   = oid{1, 3, 6, 1, 2, 1, 1, 3, 0}
+	snmpTrapOID = oid{1, 3, 6, 1, 6, 3, 1, 1, 4, 1, 0}
+)
+
+// destNearestBridge is the lldpV2DestAddressTableIndex of the nearest bridge
+// group address, the only destination address used by package agent.
+const destNearestBridge = 1
+
+// A view is a sorted snapshot of the objects served by a Subagent.
+type view []varBind
+
+// A snapshot contains the state from which a view is built.
+type snapshot struct {
+	local     map[string]*lldp.Frame
+	stats     map[string]agent.PortStats
+	table     agent.TableStats
+	neighbors []*agent.Neighbor
+
+	// ifIndex returns the interface index of a named interface.
+	ifIndex func(ifname string) (int, error)
+
+	// remIndex returns the lldpV2RemIndex of a neighbor.
+	remIndex func(n *agent.Neighbor) uint32
+
+	// start is the time from which TimeTicks are measured.
+	start time.Time
+}
+
+// newView builds a view from a snapshot.
+func newView(s snapshot) view {
+	var v view
+	add := func(t valueType, o oid, value any) {
+		v = append(v, varBind{Type: t, Name: o, Value: value})
+	}
+
+	addStatistics(s, add)
+	addLocal(s, add)
+	addRemote(s, add)
+
+	sort.Slice(v, func(i, j int) bool {
+		return v[i].Name.compare(v[j].Name) < 0
+	})
+
+	return v
+}
+
+// addStatistics adds the lldpV2Statistics objects.
+func addStatistics(s snapshot, add func(valueType, oid, any)) {
+	add(typeTimeTicks, lldpV2Statistics.append(1, 0), timeTicks(s.start, s.table.LastChangeTime))
+	add(typeGauge32, lldpV2StatsRemTablesInserts, uint32(s.table.Inserts))
+	add(typeGauge32, lldpV2StatsRemTablesDeletes, uint32(s.table.Deletes))
+	add(typeGauge32, lldpV2StatsRemTablesDrops, uint32(s.table.Drops))
+	add(typeGauge32, lldpV2StatsRemTablesAgeouts, uint32(s.table.Ageouts))
+
+	for ifname, st := range s.stats {
+		ifi, err := s.ifIndex(ifname)
+		if err != nil {
+			continue
+		}
+
+		// lldpV2StatsTxPortTable, indexed by interface and destination.
+		tx := func(column uint32) oid {
+			return lldpV2Statistics.append(6, 1, column, uint32(ifi), destNearestBridge)
+		}
+		add(typeCounter32, tx(3), uint32(st.FramesOutTotal))
+		add(typeCounter32, tx(4), uint32(0))
+
+		// lldpV2StatsRxPortTable, indexed by interface and destination.
+		rx := func(column uint32) oid {
+			return lldpV2Statistics.append(7, 1, column, uint32(ifi), destNearestBridge)
+		}
+		add(typeCounter32, rx(3), uint32(st.FramesDiscardedTotal))
+		add(typeCounter32, rx(4), uint32(st.FramesInErrorsTotal))
+		add(typeCounter32, rx(5), uint32(st.FramesInTotal))
+		add(typeCounter32, rx(6), uint32(st.TLVsDiscardedTotal))
+		add(typeCounter32, rx(7), uint32(st.TLVsUnrecognizedTotal))
+		add(typeCounter32, rx(8), uint32(st.AgeoutsTotal))
+	}
+}
+
+// addLocal adds the lldpV2LocalSystemData objects.
+func addLocal(s snapshot, add func(valueType, oid, any)) {
+	if len(s.local) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(s.local))
+	for ifname := range s.local {
+		names = append(names, ifname)
+	}
+	sort.Strings(names)
+
+	// The chassis is the same on every port, so describe it using the
+	// first port's frame.
+	f := s.local[names[0]]
+	o := frameObjects(f)
+
+	scalar := func(subid uint32) oid {
+		return lldpV2LocalSystemData.append(subid, 0)
+	}
+	add(typeInteger, scalar(1), int32(f.ChassisID.Subtype))
+	add(typeOctetString, scalar(2), f.ChassisID.ID)
+	add(typeOctetString, scalar(3), o.sysName)
+	add(typeOctetString, scalar(4), o.sysDesc)
+	add(typeOctetString, scalar(5), capabilityBits(o.caps.System))
+	add(typeOctetString, scalar(6), capabilityBits(o.caps.Enabled))
+
+	seen := make(map[string]bool)
+	for _, ifname := range names {
+		f := s.local[ifname]
+		o := frameObjects(f)
+
+		ifi, err := s.ifIndex(ifname)
+		if err != nil {
+			continue
+		}
+
+		// lldpV2LocPortTable, indexed by interface.
+		port := func(column uint32) oid {
+			return lldpV2LocalSystemData.append(7, 1, column, uint32(ifi))
+		}
+		add(typeInteger, port(2), int32(f.PortID.Subtype))
+		add(typeOctetString, port(3), f.PortID.ID)
+		add(typeOctetString, port(4), o.portDesc)
+
+		// lldpV2LocManAddrTable, indexed by address family and address.
+		// Addresses advertised on several ports appear once.
+		for _, m := range o.addrs {
+			index := manAddrIndex(m)
+			if seen[index.String()] {
+				continue
+			}
+			seen[index.String()] = true
+
+			addr := func(column uint32) oid {
+				return lldpV2LocalSystemData.append(8, 1, column).append(index...)
+			}
+			add(typeInteger, addr(3), int32(len(m.Address)+1))
+			add(typeInteger, addr(4), int32(m.InterfaceNumbering))
+			add(typeInteger, addr(5), int32(m.InterfaceNumber))
+			add(typeOID, addr(6), manAddrOID(m))
+		}
+	}
+}
+
+// addRemote adds the lldpV2RemTable and lldpV2RemManAddrTable objects.
+func addRemote(s snapshot, add func(valueType, oid, any)) {
+	for _, n := range s.neighbors {
+		ifi, err := s.ifIndex(n.Interface)
+		if err != nil {
+			continue
+		}
+
+		// Both tables are indexed by a time filter, the local interface
+		// and destination, and the neighbor's index.  The time filter is
+		// always zero, so that every neighbor is visible when walking the
+		// tables.
+		index := oid{0, uint32(ifi), destNearestBridge, s.remIndex(n)}
+		o := frameObjects(n.Frame)
+
+		rem := func(column uint32) oid {
+			return lldpV2RemTable.append(1, column).append(index...)
+		}
+		add(typeInteger, rem(5), int32(n.Frame.ChassisID.Subtype))
+		add(typeOctetString, rem(6), n.Frame.ChassisID.ID)
+		add(typeInteger, rem(7), int32(n.Frame.PortID.Subtype))
+		add(typeOctetString, rem(8), n.Frame.PortID.ID)
+		add(typeOctetString, rem(9), o.portDesc)
+		add(typeOctetString, rem(10), o.sysName)
+		add(typeOctetString, rem(11), o.sysDesc)
+		add(typeOctetString, rem(12), capabilityBits(o.caps.System))
+		add(typeOctetString, rem(13), capabilityBits(o.caps.Enabled))
+		add(typeInteger, rem(14), truthValue(false))
+		add(typeInteger, rem(15), truthValue(false))
+
+		for _, m := range o.addrs {
+			addr := func(column uint32) oid {
+				return lldpV2RemManAddrTable.append(1, column).append(index...).append(manAddrIndex(m)...)
+			}
+			add(typeInteger, addr(3), int32(m.InterfaceNumbering))
+			add(typeInteger, addr(4), int32(m.InterfaceNumber))
+			add(typeOID, addr(5), manAddrOID(m))
+		}
+	}
+}
+
+// get returns the object named o, or a varBind indicating that no such
+// object exists.
+func (v view) get(o oid) varBind {
+	i := sort.Search(len(v), func(i int) bool {
+		return v[i].Name.compare(o) >= 0
+	})
+	if i < len(v) && v[i].Name.compare(o) == 0 {
+		return v[i]
+	}
+
+	for _, s := range subtrees {
+		if o.hasPrefix(s) {
+			return varBind{Type: typeNoSuchInstance, Name: o}
+		}
+	}
+
+	return varBind{Type: typeNoSuchObject, Name: o}
+}
+
+// next returns the first object within a searchRange, or a varBind
+// indicating the end of the MIB view.
+func (v view) next(r searchRange) varBind {
+	i := sort.Search(len(v), func(i int) bool {
+		c := v[i].Name.compare(r.Start)
+		return c > 0 || (c == 0 && r.Include)
+	})
+	if i < len(v) && (len(r.End) == 0 || v[i].Name.compare(r.End) < 0) {
+		return v[i]
+	}
+
+	return varBind{Type: typeEndOfMIBView, Name: r.Start}
+}
+
+// bulk returns the objects requested by a getBulkPDU, as defined in RFC 2741,
+// section 7.2.3.3.
+func (v view) bulk(p *getBulkPDU) []varBind {
+	n := int(p.NonRepeaters)
+	if n > len(p.Ranges) {
+		n = len(p.Ranges)
+	}
+
+	var vbs []varBind
+	for _, r := range p.Ranges[:n] {
+		vbs = append(vbs, v.next(r))
+	}
+
+	repeaters := append([]searchRange(nil), p.Ranges[n:]...)
+	for i := 0; i < int(p.MaxRepetitions) && len(repeaters) > 0; i++ {
+		done := true
+		for j, r := range repeaters {
+			vb := v.next(r)
+			vbs = append(vbs, vb)
+
+			if vb.Type != typeEndOfMIBView {
+				done = false
+				repeaters[j] = searchRange{Start: vb.Name, End: r.End}
+			}
+		}
+		if done {
+			break
+		}
+	}
+
+	return vbs
+}
+
+// objects are the values of a Frame's optional TLVs which appear in the MIB.
+type objects struct {
+	portDesc, sysName, sysDesc []byte
+	caps                       lldp.SystemCapabilities
+	addrs                      []*lldp.ManagementAddress
+}
+
+// frameObjects extracts the objects from a Frame's optional TLVs.  Absent
+// strings are reported as empty strings.
+func frameObjects(f *lldp.Frame) objects {
+	o := objects{
+		portDesc: []byte{},
+		sysName:  []byte{},
+		sysDesc:  []byte{},
+	}
+
+	for _, t := range f.Optional {
+		switch t.Type {
+		case lldp.TLVTypePortDescription:
+			o.portDesc = t.Value
+		case lldp.TLVTypeSystemName:
+			o.sysName = t.Value
+		case lldp.TLVTypeSystemDescription:
+			o.sysDesc = t.Value
+		case lldp.TLVTypeSystemCapabilities:
+			_ = o.caps.UnmarshalBinary(t.Value)
+		case lldp.TLVTypeManagementAddress:
+			m := new(lldp.ManagementAddress)
+			if err := m.UnmarshalBinary(t.Value); err == nil {
+				o.addrs = append(o.addrs, m)
+			}
+		}
+	}
+
+	return o
+}
+
+// capabilityBits encodes a Capability bit mask as an SNMP BITS value, in
+// which the first bit is the most significant bit of the first octet.
+func capabilityBits(c lldp.Capability) []byte {
+	b := make([]byte, 2)
+	for i := 0; i < 16; i++ {
+		if c&(1<<i) != 0 {
+			b[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+
+	return b
+}
+
+// manAddrIndex returns the table index of a management address: its address
+// family followed by the length and octets of the address.
+func manAddrIndex(m *lldp.ManagementAddress) oid {
+	o := oid{uint32(m.Family), uint32(len(m.Address))}
+	for _, b := range m.Address {
+		o = append(o, uint32(b))
+	}
+
+	return o
+}
+
+// manAddrOID decodes the object identifier of a management address, or
+// returns zeroDotZero if it is absent or malformed.
+func manAddrOID(m *lldp.ManagementAddress) oid {
+	zeroDotZero := oid{0, 0}
+	if len(m.OID) == 0 {
+		return zeroDotZero
+	}
+
+	// Accept both a complete BER encoding and its contents alone.
+	b := m.OID
+	if b[0] != 0x06 {
+		b = append([]byte{0x06, byte(len(b))}, b...)
+	}
+
+	var id asn1.ObjectIdentifier
+	if rest, err := asn1.Unmarshal(b, &id); err != nil || len(rest) > 0 {
+		return zeroDotZero
+	}
+
+	o := make(oid, 0, len(id))
+	for _, v := range id {
+		o = append(o, uint32(v))
+	}
+
+	return o
+}
+
+// truthValue encodes a SNMPv2-TC TruthValue.
+func truthValue(b bool) int32 {
+	if b {
+		return 1
+	}
+
+	return 2
+}
+
+// timeTicks returns the number of hundredths of a second between start and
+// t, or zero if t is before start.
+func timeTicks(start, t time.Time) uint32 {
+	if t.Before(start) {
+		return 0
+	}
+
+	return uint32(t.Sub(start) / (10 * time.Millisecond))
+}
@@ -0,0 +1,285 @@
+package agentx
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/lldp"
+	"github.com/mdlayher/lldp/agent"
+)
+
+func TestViewGet(t *testing.T) {
+	v := newView(testSnapshot(t))
+
+	// Neighbor index: time filter, ifIndex 2, nearest bridge, remIndex 1.
+	rem := func(column uint32) oid {
+		return lldpV2RemTable.append(1, column, 0, 2, 1, 1)
+	}
+
+	var tests = []struct {
+		desc string
+		o    oid
+		vb   varBind
+	}{
+		{
+			desc: "local chassis ID",
+			o:    lldpV2LocalSystemData.append(2, 0),
+			vb:   varBind{Type: typeOctetString, Value: []byte("host1")},
+		},
+		{
+			desc: "local system capabilities enabled",
+			o:    lldpV2LocalSystemData.append(6, 0),
+			vb:   varBind{Type: typeOctetString, Value: []byte{0x01, 0x00}},
+		},
+		{
+			desc: "local port ID",
+			o:    lldpV2LocalSystemData.append(7, 1, 3, 2),
+			vb:   varBind{Type: typeOctetString, Value: []byte("eth0")},
+		},
+		{
+			desc: "local management address interface",
+			o:    lldpV2LocalSystemData.append(8, 1, 5, 1, 4, 192, 0, 2, 1),
+			vb:   varBind{Type: typeInteger, Value: int32(2)},
+		},
+		{
+			desc: "local management address OID",
+			o:    lldpV2LocalSystemData.append(8, 1, 6, 1, 4, 192, 0, 2, 1),
+			vb:   varBind{Type: typeOID, Value: oid{1, 3, 6, 1, 2, 1, 2, 2, 1, 1}},
+		},
+		{
+			desc: "table inserts",
+			o:    lldpV2StatsRemTablesInserts,
+			vb:   varBind{Type: typeGauge32, Value: uint32(3)},
+		},
+		{
+			desc: "table last change",
+			o:    lldpV2Statistics.append(1, 0),
+			vb:   varBind{Type: typeTimeTicks, Value: uint32(1000)},
+		},
+		{
+			desc: "rx frames",
+			o:    lldpV2Statistics.append(7, 1, 5, 2, 1),
+			vb:   varBind{Type: typeCounter32, Value: uint32(8)},
+		},
+		{
+			desc: "tx frames",
+			o:    lldpV2Statistics.append(6, 1, 3, 2, 1),
+			vb:   varBind{Type: typeCounter32, Value: uint32(10)},
+		},
+		{
+			desc: "remote chassis ID subtype",
+			o:    rem(5),
+			vb:   varBind{Type: typeInteger, Value: int32(lldp.ChassisIDSubtypeLocallyAssigned)},
+		},
+		{
+			desc: "remote system name",
+			o:    rem(10),
+			vb:   varBind{Type: typeOctetString, Value: []byte("switch1")},
+		},
+		{
+			desc: "remote port description absent",
+			o:    rem(9),
+			vb:   varBind{Type: typeOctetString, Value: []byte{}},
+		},
+		{
+			desc: "remote system capabilities supported",
+			o:    rem(12),
+			vb:   varBind{Type: typeOctetString, Value: []byte{0x28, 0x00}},
+		},
+		{
+			desc: "remote management address OID",
+			o:    lldpV2RemManAddrTable.append(1, 5, 0, 2, 1, 1, 1, 4, 192, 0, 2, 2),
+			vb:   varBind{Type: typeOID, Value: oid{0, 0}},
+		},
+		{
+			desc: "no such instance",
+			o:    rem(10).append(1),
+			vb:   varBind{Type: typeNoSuchInstance},
+		},
+		{
+			desc: "no such object",
+			o:    sysUpTime,
+			vb:   varBind{Type: typeNoSuchObject},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		tt.vb.Name = tt.o
+		if want, got := tt.vb, v.get(tt.o); !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected varbind:\n- want: %+v\n-  got: %+v", want, got)
+		}
+	}
+}
+
+func TestViewNext(t *testing.T) {
+	v := newView(testSnapshot(t))
+
+	var tests = []struct {
+		desc string
+		r    searchRange
+		o    oid
+	}{
+		{
+			desc: "first object",
+			r:    searchRange{Start: lldpV2MIB},
+			o:    lldpV2Statistics.append(1, 0),
+		},
+		{
+			desc: "include start",
+			r:    searchRange{Start: lldpV2StatsRemTablesDrops, Include: true},
+			o:    lldpV2StatsRemTablesDrops,
+		},
+		{
+			desc: "exclude start",
+			r:    searchRange{Start: lldpV2StatsRemTablesDrops},
+			o:    lldpV2StatsRemTablesAgeouts,
+		},
+		{
+			desc: "first column of remote table",
+			r:    searchRange{Start: lldpV2RemTable},
+			o:    lldpV2RemTable.append(1, 5, 0, 2, 1, 1),
+		},
+		{
+			desc: "next column of remote table",
+			r:    searchRange{Start: lldpV2RemTable.append(1, 5, 0, 2, 1, 1)},
+			o:    lldpV2RemTable.append(1, 6, 0, 2, 1, 1),
+		},
+		{
+			desc: "end of range",
+			r: searchRange{
+				Start: lldpV2RemTable.append(1, 15, 0, 2, 1, 1),
+				End:   lldpV2RemManAddrTable,
+			},
+		},
+		{
+			desc: "end of view",
+			r:    searchRange{Start: lldpV2RemManAddrTable.append(2)},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		vb := v.next(tt.r)
+		if tt.o == nil {
+			if vb.Type != typeEndOfMIBView || vb.Name.compare(tt.r.Start) != 0 {
+				t.Fatalf("expected end of MIB view, but got: %+v", vb)
+			}
+
+			continue
+		}
+
+		if want, got := tt.o, vb.Name; want.compare(got) != 0 {
+			t.Fatalf("unexpected next object: %s != %s", want, got)
+		}
+	}
+}
+
+func TestViewBulk(t *testing.T) {
+	v := newView(testSnapshot(t))
+
+	vbs := v.bulk(&getBulkPDU{
+		NonRepeaters:   1,
+		MaxRepetitions: 3,
+		Ranges: []searchRange{
+			{Start: lldpV2Statistics},
+			{Start: lldpV2StatsRemTablesInserts},
+			{Start: lldpV2RemManAddrTable.append(1, 5)},
+		},
+	})
+
+	want := []string{
+		lldpV2Statistics.append(1, 0).String(),
+		lldpV2StatsRemTablesDeletes.String(),
+		lldpV2RemManAddrTable.append(1, 5, 0, 2, 1, 1, 1, 4, 192, 0, 2, 2).String(),
+		lldpV2StatsRemTablesDrops.String(),
+		"end " + lldpV2RemManAddrTable.append(1, 5, 0, 2, 1, 1, 1, 4, 192, 0, 2, 2).String(),
+		lldpV2StatsRemTablesAgeouts.String(),
+		"end " + lldpV2RemManAddrTable.append(1, 5, 0, 2, 1, 1, 1, 4, 192, 0, 2, 2).String(),
+	}
+
+	got := make([]string, 0, len(vbs))
+	for _, vb := range vbs {
+		s := vb.Name.String()
+		if vb.Type == typeEndOfMIBView {
+			s = "end " + s
+		}
+		got = append(got, s)
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected varbinds:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+// testSnapshot returns a snapshot of a host with one interface, eth0, and one
+// neighbor on that interface.
+func testSnapshot(t *testing.T) snapshot {
+	t.Helper()
+
+	start := time.Unix(1000, 0)
+
+	local, err := lldp.NewFrameBuilder().
+		ChassisLocal("host1").
+		PortName("eth0").
+		TTL(2*time.Minute).
+		SystemName("host1").
+		SystemCapabilities(lldp.CapabilityStationOnly, lldp.CapabilityStationOnly).
+		ManagementAddress(&lldp.ManagementAddress{
+			Family:             lldp.AddressFamilyIPv4,
+			Address:            []byte{192, 0, 2, 1},
+			InterfaceNumbering: lldp.InterfaceNumberingIfIndex,
+			InterfaceNumber:    2,
+			// 1.3.6.1.2.1.2.2.1.1, ifIndex.
+			OID: []byte{0x06, 0x09, 0x2b, 0x06, 0x01, 0x02, 0x01, 0x02, 0x02, 0x01, 0x01},
+		}).
+		Frame()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	remote, err := lldp.NewFrameBuilder().
+		ChassisLocal("sw1").
+		PortName("Ethernet1").
+		TTL(2*time.Minute).
+		SystemName("switch1").
+		SystemCapabilities(lldp.CapabilityBridge|lldp.CapabilityRouter, lldp.CapabilityBridge).
+		ManagementAddress(&lldp.ManagementAddress{
+			Family:             lldp.AddressFamilyIPv4,
+			Address:            []byte{192, 0, 2, 2},
+			InterfaceNumbering: lldp.InterfaceNumberingUnknown,
+		}).
+		Frame()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return snapshot{
+		local: map[string]*lldp.Frame{"eth0": local},
+		stats: map[string]agent.PortStats{
+			"eth0": {FramesOutTotal: 10, FramesInTotal: 8},
+		},
+		table: agent.TableStats{
+			LastChangeTime: start.Add(10 * time.Second),
+			Inserts:        3,
+			Deletes:        2,
+		},
+		neighbors: []*agent.Neighbor{{
+			Interface: "eth0",
+			Frame:     remote,
+		}},
+		ifIndex: func(ifname string) (int, error) {
+			if ifname != "eth0" {
+				return 0, fmt.Errorf("unknown interface %q", ifname)
+			}
+
+			return 2, nil
+		},
+		remIndex: func(*agent.Neighbor) uint32 { return 1 },
+		start:    start,
+	}
+}
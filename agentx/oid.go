@@ -0,0 +1,75 @@
+package agentx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// An oid is an SNMP object identifier.
+type oid []uint32
+
+// parseOID parses an object identifier in dotted decimal form.
+func parseOID(s string) (oid, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	ss := strings.Split(strings.TrimPrefix(s, "."), ".")
+	o := make(oid, 0, len(ss))
+	for _, s := range ss {
+		v, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("agentx: invalid object identifier %q: %w", s, err)
+		}
+
+		o = append(o, uint32(v))
+	}
+
+	return o, nil
+}
+
+// String returns the dotted decimal form of an oid.
+func (o oid) String() string {
+	ss := make([]string, 0, len(o))
+	for _, v := range o {
+		ss = append(ss, strconv.FormatUint(uint64(v), 10))
+	}
+
+	return strings.Join(ss, ".")
+}
+
+// append returns a copy of o with subids appended, so that oids which share a
+// prefix never share storage.
+func (o oid) append(subids ...uint32) oid {
+	out := make(oid, 0, len(o)+len(subids))
+	out = append(out, o...)
+	return append(out, subids...)
+}
+
+// compare returns -1, 0, or 1 if o sorts before, equal to, or after p in
+// lexicographic order.
+func (o oid) compare(p oid) int {
+	for i := 0; i < len(o) && i < len(p); i++ {
+		switch {
+		case o[i] < p[i]:
+			return -1
+		case o[i] > p[i]:
+			return 1
+		}
+	}
+
+	switch {
+	case len(o) < len(p):
+		return -1
+	case len(o) > len(p):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// hasPrefix reports whether o is equal to or contained in the subtree p.
+func (o oid) hasPrefix(p oid) bool {
+	return len(o) >= len(p) && o[:len(p)].compare(p) == 0
+}
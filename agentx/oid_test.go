@@ -0,0 +1,112 @@
+package agentx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseOID(t *testing.T) {
+	var tests = []struct {
+		desc string
+		s    string
+		o    oid
+		ok   bool
+	}{
+		{
+			desc: "empty",
+			ok:   true,
+		},
+		{
+			desc: "bad subid",
+			s:    "1.3.foo",
+		},
+		{
+			desc: "subid too large",
+			s:    "1.4294967296",
+		},
+		{
+			desc: "OK",
+			s:    "1.3.111.2.802.1.1.13",
+			o:    oid{1, 3, 111, 2, 802, 1, 1, 13},
+			ok:   true,
+		},
+		{
+			desc: "OK, leading dot",
+			s:    ".1.3.6.1",
+			o:    oid{1, 3, 6, 1},
+			ok:   true,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		o, err := parseOID(tt.s)
+		if err != nil {
+			if tt.ok {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			continue
+		}
+		if !tt.ok {
+			t.Fatal("expected an error, but none occurred")
+		}
+
+		if want, got := tt.o, o; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected oid: %v != %v", want, got)
+		}
+		if want, got := tt.s, o.String(); tt.s != "" && tt.s[0] != '.' && want != got {
+			t.Fatalf("unexpected string: %q != %q", want, got)
+		}
+	}
+}
+
+func TestOIDCompare(t *testing.T) {
+	var tests = []struct {
+		desc string
+		a, b oid
+		c    int
+		p    bool
+	}{
+		{
+			desc: "equal",
+			a:    oid{1, 3, 6},
+			b:    oid{1, 3, 6},
+			p:    true,
+		},
+		{
+			desc: "less",
+			a:    oid{1, 3, 5, 9},
+			b:    oid{1, 3, 6},
+			c:    -1,
+		},
+		{
+			desc: "greater",
+			a:    oid{1, 3, 7},
+			b:    oid{1, 3, 6, 1},
+			c:    1,
+		},
+		{
+			desc: "prefix sorts first",
+			a:    oid{1, 3, 6, 1},
+			b:    oid{1, 3, 6},
+			c:    1,
+			p:    true,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		if want, got := tt.c, tt.a.compare(tt.b); want != got {
+			t.Fatalf("unexpected comparison: %d != %d", want, got)
+		}
+		if want, got := -tt.c, tt.b.compare(tt.a); want != got {
+			t.Fatalf("unexpected reverse comparison: %d != %d", want, got)
+		}
+		if want, got := tt.p, tt.a.hasPrefix(tt.b); want != got {
+			t.Fatalf("unexpected prefix result: %v != %v", want, got)
+		}
+	}
+}
@@ -0,0 +1,525 @@
+package agentx
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var (
+	// errInvalidPDU is returned when a PDU is malformed.
+	errInvalidPDU = errors.New("agentx: invalid PDU")
+
+	// errUnsupportedVersion is returned when a PDU does not use version 1
+	// of the AgentX protocol.
+	errUnsupportedVersion = errors.New("agentx: unsupported protocol version")
+)
+
+// A pduType is the type of an AgentX PDU, as defined in RFC 2741, section
+// 6.1.
+type pduType uint8
+
+// List of pduType values used by this package.
+const (
+	pduOpen       pduType = 1
+	pduClose      pduType = 2
+	pduRegister   pduType = 3
+	pduGet        pduType = 5
+	pduGetNext    pduType = 6
+	pduGetBulk    pduType = 7
+	pduTestSet    pduType = 8
+	pduCommitSet  pduType = 9
+	pduUndoSet    pduType = 10
+	pduCleanupSet pduType = 11
+	pduNotify     pduType = 12
+	pduPing       pduType = 13
+	pduResponse   pduType = 18
+)
+
+// Flags carried in a PDU header.
+const (
+	flagNonDefaultContext = 1 << 3
+	flagNetworkByteOrder  = 1 << 4
+)
+
+// Errors carried in a response PDU, as defined in RFC 2741, section 6.2.16.
+const (
+	errorNone            = 0
+	errorNotWritable     = 17
+	errorParse           = 266
+	errorProcessingError = 268
+)
+
+// reasonShutdown is the reason carried in a close PDU sent when a Subagent
+// stops.
+const reasonShutdown = 5
+
+const (
+	// version is the AgentX protocol version.
+	version = 1
+
+	// headerLen is the length of a PDU header.
+	headerLen = 20
+
+	// payloadMax is the maximum accepted payload length.
+	payloadMax = 1 << 20
+)
+
+// A header is the header of an AgentX PDU.
+type header struct {
+	Type          pduType
+	Flags         uint8
+	SessionID     uint32
+	TransactionID uint32
+	PacketID      uint32
+}
+
+// A pdu is the payload of an AgentX PDU.
+type pdu interface {
+	marshal(e *encoder)
+	unmarshal(d *decoder) error
+}
+
+// writePDU writes a PDU to w, in network byte order.
+func writePDU(w io.Writer, h header, p pdu) error {
+	e := new(encoder)
+	p.marshal(e)
+
+	b := make([]byte, headerLen, headerLen+len(e.b))
+	b[0] = version
+	b[1] = uint8(h.Type)
+	b[2] = h.Flags | flagNetworkByteOrder
+	binary.BigEndian.PutUint32(b[4:8], h.SessionID)
+	binary.BigEndian.PutUint32(b[8:12], h.TransactionID)
+	binary.BigEndian.PutUint32(b[12:16], h.PacketID)
+	binary.BigEndian.PutUint32(b[16:20], uint32(len(e.b)))
+
+	_, err := w.Write(append(b, e.b...))
+	return err
+}
+
+// readPDU reads a PDU header and its payload from r.  The payload is returned
+// as a decoder, which must be used to unmarshal the PDU of the type indicated
+// by the header.
+func readPDU(r io.Reader) (header, *decoder, error) {
+	b := make([]byte, headerLen)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return header{}, nil, err
+	}
+	if b[0] != version {
+		return header{}, nil, errUnsupportedVersion
+	}
+
+	var order binary.ByteOrder = binary.LittleEndian
+	if b[2]&flagNetworkByteOrder != 0 {
+		order = binary.BigEndian
+	}
+
+	h := header{
+		Type:          pduType(b[1]),
+		Flags:         b[2],
+		SessionID:     order.Uint32(b[4:8]),
+		TransactionID: order.Uint32(b[8:12]),
+		PacketID:      order.Uint32(b[12:16]),
+	}
+
+	n := order.Uint32(b[16:20])
+	if n%4 != 0 || n > payloadMax {
+		return header{}, nil, errInvalidPDU
+	}
+
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return header{}, nil, err
+	}
+
+	d := &decoder{b: payload, order: order}
+	if h.Flags&flagNonDefaultContext != 0 {
+		// Only the default context is supported, so the context is
+		// discarded.
+		_ = d.octets()
+	}
+
+	return h, d, d.err
+}
+
+// An openPDU establishes a session with a master agent.
+type openPDU struct {
+	Timeout     uint8
+	ID          oid
+	Description string
+}
+
+func (p *openPDU) marshal(e *encoder) {
+	e.u8(p.Timeout)
+	e.pad(3)
+	e.oid(p.ID, false)
+	e.octets([]byte(p.Description))
+}
+
+func (p *openPDU) unmarshal(d *decoder) error {
+	p.Timeout = d.u8()
+	d.skip(3)
+	p.ID, _ = d.oid()
+	p.Description = string(d.octets())
+	return d.done()
+}
+
+// A closePDU terminates a session.
+type closePDU struct {
+	Reason uint8
+}
+
+func (p *closePDU) marshal(e *encoder) {
+	e.u8(p.Reason)
+	e.pad(3)
+}
+
+func (p *closePDU) unmarshal(d *decoder) error {
+	p.Reason = d.u8()
+	d.skip(3)
+	return d.done()
+}
+
+// A registerPDU registers a subtree with a master agent.
+type registerPDU struct {
+	Timeout  uint8
+	Priority uint8
+	Subtree  oid
+}
+
+func (p *registerPDU) marshal(e *encoder) {
+	e.u8(p.Timeout)
+	e.u8(p.Priority)
+	// Ranges of subtrees are not registered, so range_subid is zero.
+	e.u8(0)
+	e.pad(1)
+	e.oid(p.Subtree, false)
+}
+
+func (p *registerPDU) unmarshal(d *decoder) error {
+	p.Timeout = d.u8()
+	p.Priority = d.u8()
+	if d.u8() != 0 {
+		return errInvalidPDU
+	}
+	d.skip(1)
+	p.Subtree, _ = d.oid()
+	return d.done()
+}
+
+// A searchRange is a range of object identifiers requested by a master
+// agent.  If Include is set, Start itself may be returned.  An empty End
+// indicates no upper bound.
+type searchRange struct {
+	Start   oid
+	Include bool
+	End     oid
+}
+
+// A getPDU requests the values of a list of objects, or of the objects
+// which follow them, depending on the PDU type.
+type getPDU struct {
+	Ranges []searchRange
+}
+
+func (p *getPDU) marshal(e *encoder) {
+	for _, r := range p.Ranges {
+		e.searchRange(r)
+	}
+}
+
+func (p *getPDU) unmarshal(d *decoder) error {
+	for d.err == nil && len(d.b) > 0 {
+		p.Ranges = append(p.Ranges, d.searchRange())
+	}
+	return d.done()
+}
+
+// A getBulkPDU requests the values of the objects which follow a list of
+// objects, repeatedly.
+type getBulkPDU struct {
+	NonRepeaters   uint16
+	MaxRepetitions uint16
+	Ranges         []searchRange
+}
+
+func (p *getBulkPDU) marshal(e *encoder) {
+	e.u16(p.NonRepeaters)
+	e.u16(p.MaxRepetitions)
+	for _, r := range p.Ranges {
+		e.searchRange(r)
+	}
+}
+
+func (p *getBulkPDU) unmarshal(d *decoder) error {
+	p.NonRepeaters = d.u16()
+	p.MaxRepetitions = d.u16()
+	for d.err == nil && len(d.b) > 0 {
+		p.Ranges = append(p.Ranges, d.searchRange())
+	}
+	return d.done()
+}
+
+// A notifyPDU sends a notification.
+type notifyPDU struct {
+	VarBinds []varBind
+}
+
+func (p *notifyPDU) marshal(e *encoder) {
+	for _, vb := range p.VarBinds {
+		e.varBind(vb)
+	}
+}
+
+func (p *notifyPDU) unmarshal(d *decoder) error {
+	for d.err == nil && len(d.b) > 0 {
+		p.VarBinds = append(p.VarBinds, d.varBind())
+	}
+	return d.done()
+}
+
+// A responsePDU is the response to any other PDU.
+type responsePDU struct {
+	SysUpTime uint32
+	Error     uint16
+	Index     uint16
+	VarBinds  []varBind
+}
+
+func (p *responsePDU) marshal(e *encoder) {
+	e.u32(p.SysUpTime)
+	e.u16(p.Error)
+	e.u16(p.Index)
+	for _, vb := range p.VarBinds {
+		e.varBind(vb)
+	}
+}
+
+func (p *responsePDU) unmarshal(d *decoder) error {
+	p.SysUpTime = d.u32()
+	p.Error = d.u16()
+	p.Index = d.u16()
+	for d.err == nil && len(d.b) > 0 {
+		p.VarBinds = append(p.VarBinds, d.varBind())
+	}
+	return d.done()
+}
+
+// An emptyPDU is a PDU with no payload, such as a ping.
+type emptyPDU struct{}
+
+func (*emptyPDU) marshal(*encoder)           {}
+func (*emptyPDU) unmarshal(d *decoder) error { return d.done() }
+
+// A valueType is the type of the value in a varBind.
+type valueType uint16
+
+// List of valid valueType values.
+const (
+	typeInteger        valueType = 2
+	typeOctetString    valueType = 4
+	typeNull           valueType = 5
+	typeOID            valueType = 6
+	typeIPAddress      valueType = 64
+	typeCounter32      valueType = 65
+	typeGauge32        valueType = 66
+	typeTimeTicks      valueType = 67
+	typeOpaque         valueType = 68
+	typeCounter64      valueType = 70
+	typeNoSuchObject   valueType = 128
+	typeNoSuchInstance valueType = 129
+	typeEndOfMIBView   valueType = 130
+)
+
+// A varBind binds a value to an object identifier.  The Go type of Value
+// depends on Type:
+//   - typeInteger: int32
+//   - typeOctetString, typeIPAddress, typeOpaque: []byte
+//   - typeOID: oid
+//   - typeCounter32, typeGauge32, typeTimeTicks: uint32
+//   - typeCounter64: uint64
+//   - all others: nil
+type varBind struct {
+	Type  valueType
+	Name  oid
+	Value any
+}
+
+// An encoder appends the network byte order encoding of AgentX data types to
+// a buffer.
+type encoder struct {
+	b []byte
+}
+
+func (e *encoder) u8(v uint8)   { e.b = append(e.b, v) }
+func (e *encoder) u16(v uint16) { e.b = binary.BigEndian.AppendUint16(e.b, v) }
+func (e *encoder) u32(v uint32) { e.b = binary.BigEndian.AppendUint32(e.b, v) }
+func (e *encoder) u64(v uint64) { e.b = binary.BigEndian.AppendUint64(e.b, v) }
+func (e *encoder) pad(n int)    { e.b = append(e.b, make([]byte, n)...) }
+
+// oid encodes an object identifier, using the compact prefix form for
+// identifiers beneath 1.3.6.1.
+func (e *encoder) oid(o oid, include bool) {
+	var prefix uint8
+	if len(o) > 4 && o[:4].compare(oid{1, 3, 6, 1}) == 0 && o[4] > 0 && o[4] < 256 {
+		prefix = uint8(o[4])
+		o = o[5:]
+	}
+
+	e.u8(uint8(len(o)))
+	e.u8(prefix)
+	if include {
+		e.u8(1)
+	} else {
+		e.u8(0)
+	}
+	e.pad(1)
+
+	for _, v := range o {
+		e.u32(v)
+	}
+}
+
+// octets encodes an octet string, padded to a multiple of four bytes.
+func (e *encoder) octets(b []byte) {
+	e.u32(uint32(len(b)))
+	e.b = append(e.b, b...)
+	if n := len(b) % 4; n != 0 {
+		e.pad(4 - n)
+	}
+}
+
+func (e *encoder) searchRange(r searchRange) {
+	e.oid(r.Start, r.Include)
+	e.oid(r.End, false)
+}
+
+func (e *encoder) varBind(vb varBind) {
+	e.u16(uint16(vb.Type))
+	e.pad(2)
+	e.oid(vb.Name, false)
+
+	switch vb.Type {
+	case typeInteger:
+		e.u32(uint32(vb.Value.(int32)))
+	case typeOctetString, typeIPAddress, typeOpaque:
+		e.octets(vb.Value.([]byte))
+	case typeOID:
+		e.oid(vb.Value.(oid), false)
+	case typeCounter32, typeGauge32, typeTimeTicks:
+		e.u32(vb.Value.(uint32))
+	case typeCounter64:
+		e.u64(vb.Value.(uint64))
+	}
+}
+
+// A decoder decodes AgentX data types from a PDU payload.  After the first
+// error, all further reads return zero values.
+type decoder struct {
+	b     []byte
+	order binary.ByteOrder
+	err   error
+}
+
+// next returns the next n bytes of the payload.
+func (d *decoder) next(n int) []byte {
+	if d.err != nil {
+		return make([]byte, n)
+	}
+	if len(d.b) < n {
+		d.err = errInvalidPDU
+		d.b = nil
+		return make([]byte, n)
+	}
+
+	b := d.b[:n]
+	d.b = d.b[n:]
+	return b
+}
+
+func (d *decoder) u8() uint8   { return d.next(1)[0] }
+func (d *decoder) u16() uint16 { return d.order.Uint16(d.next(2)) }
+func (d *decoder) u32() uint32 { return d.order.Uint32(d.next(4)) }
+func (d *decoder) u64() uint64 { return d.order.Uint64(d.next(8)) }
+func (d *decoder) skip(n int)  { _ = d.next(n) }
+
+// done returns any error which occurred while decoding, or an error if
+// trailing bytes remain.
+func (d *decoder) done() error {
+	if d.err == nil && len(d.b) > 0 {
+		d.err = fmt.Errorf("%w: %d trailing bytes", errInvalidPDU, len(d.b))
+	}
+
+	return d.err
+}
+
+// oid decodes an object identifier and its include field.
+func (d *decoder) oid() (oid, bool) {
+	n := int(d.u8())
+	prefix := d.u8()
+	include := d.u8() == 1
+	d.skip(1)
+
+	if n > 128 {
+		d.err = errInvalidPDU
+		return nil, false
+	}
+
+	var o oid
+	if prefix != 0 {
+		o = oid{1, 3, 6, 1, uint32(prefix)}
+	}
+	for i := 0; i < n; i++ {
+		o = append(o, d.u32())
+	}
+
+	return o, include
+}
+
+// octets decodes an octet string and its padding.
+func (d *decoder) octets() []byte {
+	n := d.u32()
+	if n > uint32(len(d.b)) {
+		d.err = errInvalidPDU
+		return nil
+	}
+
+	b := d.next(int(n))
+	if r := n % 4; r != 0 {
+		d.skip(int(4 - r))
+	}
+
+	return b
+}
+
+func (d *decoder) searchRange() searchRange {
+	start, include := d.oid()
+	end, _ := d.oid()
+	return searchRange{Start: start, Include: include, End: end}
+}
+
+func (d *decoder) varBind() varBind {
+	vb := varBind{Type: valueType(d.u16())}
+	d.skip(2)
+	vb.Name, _ = d.oid()
+
+	switch vb.Type {
+	case typeInteger:
+		vb.Value = int32(d.u32())
+	case typeOctetString, typeIPAddress, typeOpaque:
+		vb.Value = d.octets()
+	case typeOID:
+		vb.Value, _ = d.oid()
+	case typeCounter32, typeGauge32, typeTimeTicks:
+		vb.Value = d.u32()
+	case typeCounter64:
+		vb.Value = d.u64()
+	case typeNull, typeNoSuchObject, typeNoSuchInstance, typeEndOfMIBView:
+	default:
+		d.err = fmt.Errorf("%w: unknown value type %d", errInvalidPDU, vb.Type)
+	}
+
+	return vb
+}
@@ -0,0 +1,269 @@
+package agentx
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestPDURoundTrip(t *testing.T) {
+	var tests = []struct {
+		desc string
+		h    header
+		in   pdu
+		out  pdu
+	}{
+		{
+			desc: "open",
+			h:    header{Type: pduOpen, PacketID: 1},
+			in: &openPDU{
+				Timeout:     10,
+				ID:          lldpV2MIB,
+				Description: "hello",
+			},
+			out: new(openPDU),
+		},
+		{
+			desc: "close",
+			h:    header{Type: pduClose, SessionID: 1, PacketID: 2},
+			in:   &closePDU{Reason: reasonShutdown},
+			out:  new(closePDU),
+		},
+		{
+			desc: "register",
+			h:    header{Type: pduRegister, SessionID: 1, PacketID: 3},
+			in: &registerPDU{
+				Priority: 127,
+				Subtree:  lldpV2RemTable,
+			},
+			out: new(registerPDU),
+		},
+		{
+			desc: "get next",
+			h:    header{Type: pduGetNext, SessionID: 1, TransactionID: 4, PacketID: 4},
+			in: &getPDU{Ranges: []searchRange{
+				{Start: lldpV2RemTable, Include: true, End: lldpV2RemManAddrTable},
+				{Start: sysUpTime},
+			}},
+			out: new(getPDU),
+		},
+		{
+			desc: "get bulk",
+			h:    header{Type: pduGetBulk, SessionID: 1, TransactionID: 5, PacketID: 5},
+			in: &getBulkPDU{
+				NonRepeaters:   1,
+				MaxRepetitions: 10,
+				Ranges: []searchRange{
+					{Start: sysUpTime},
+					{Start: lldpV2RemTable},
+				},
+			},
+			out: new(getBulkPDU),
+		},
+		{
+			desc: "response, all value types",
+			h:    header{Type: pduResponse, SessionID: 1, PacketID: 6},
+			in: &responsePDU{
+				SysUpTime: 100,
+				VarBinds: []varBind{
+					{Type: typeInteger, Name: oid{1, 1}, Value: int32(-1)},
+					{Type: typeOctetString, Name: oid{1, 2}, Value: []byte("abcde")},
+					{Type: typeNull, Name: oid{1, 3}},
+					{Type: typeOID, Name: oid{1, 4}, Value: snmpTrapOID},
+					{Type: typeIPAddress, Name: oid{1, 5}, Value: []byte{192, 0, 2, 1}},
+					{Type: typeCounter32, Name: oid{1, 6}, Value: uint32(1)},
+					{Type: typeGauge32, Name: oid{1, 7}, Value: uint32(2)},
+					{Type: typeTimeTicks, Name: oid{1, 8}, Value: uint32(3)},
+					{Type: typeOpaque, Name: oid{1, 9}, Value: []byte{}},
+					{Type: typeCounter64, Name: oid{1, 10}, Value: uint64(1 << 40)},
+					{Type: typeNoSuchObject, Name: oid{1, 11}},
+					{Type: typeNoSuchInstance, Name: oid{1, 12}},
+					{Type: typeEndOfMIBView, Name: oid{1, 13}},
+				},
+			},
+			out: new(responsePDU),
+		},
+		{
+			desc: "notify",
+			h:    header{Type: pduNotify, SessionID: 1, PacketID: 7},
+			in: &notifyPDU{VarBinds: []varBind{
+				{Type: typeTimeTicks, Name: sysUpTime, Value: uint32(1)},
+				{Type: typeOID, Name: snmpTrapOID, Value: lldpV2RemTablesChange},
+			}},
+			out: new(notifyPDU),
+		},
+		{
+			desc: "ping",
+			h:    header{Type: pduPing, SessionID: 1, PacketID: 8},
+			in:   new(emptyPDU),
+			out:  new(emptyPDU),
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		var buf bytes.Buffer
+		if err := writePDU(&buf, tt.h, tt.in); err != nil {
+			t.Fatal(err)
+		}
+		if n := buf.Len(); n%4 != 0 {
+			t.Fatalf("PDU length %d is not a multiple of 4", n)
+		}
+
+		h, d, err := readPDU(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := tt.out.unmarshal(d); err != nil {
+			t.Fatal(err)
+		}
+
+		want := tt.h
+		want.Flags |= flagNetworkByteOrder
+		if got := h; want != got {
+			t.Fatalf("unexpected header:\n- want: %+v\n-  got: %+v", want, got)
+		}
+		if want, got := tt.in, tt.out; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected PDU:\n- want: %+v\n-  got: %+v", want, got)
+		}
+	}
+}
+
+func TestReadPDU(t *testing.T) {
+	var tests = []struct {
+		desc string
+		b    []byte
+		err  error
+		h    header
+		r    []searchRange
+	}{
+		{
+			desc: "short header",
+			b:    []byte{1, 5, 0, 0},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "bad version",
+			b:    append([]byte{2, 5}, make([]byte, 18)...),
+			err:  errUnsupportedVersion,
+		},
+		{
+			desc: "payload not a multiple of 4",
+			b: []byte{
+				1, 5, 0, 0,
+				0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+				2, 0, 0, 0,
+				0, 0,
+			},
+			err: errInvalidPDU,
+		},
+		{
+			desc: "little endian get with prefix and context",
+			b: []byte{
+				1, 5, flagNonDefaultContext, 0,
+				1, 0, 0, 0,
+				2, 0, 0, 0,
+				3, 0, 0, 0,
+				24, 0, 0, 0,
+				// Context "ctx".
+				3, 0, 0, 0, 'c', 't', 'x', 0,
+				// Start 1.3.6.1.2.1.1, included.
+				2, 2, 1, 0,
+				1, 0, 0, 0,
+				1, 0, 0, 0,
+				// Empty end.
+				0, 0, 0, 0,
+			},
+			h: header{
+				Type:          pduGet,
+				Flags:         flagNonDefaultContext,
+				SessionID:     1,
+				TransactionID: 2,
+				PacketID:      3,
+			},
+			r: []searchRange{{
+				Start:   oid{1, 3, 6, 1, 2, 1, 1},
+				Include: true,
+			}},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		h, d, err := readPDU(bytes.NewReader(tt.b))
+		if tt.err != nil {
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("unexpected error: %v != %v", tt.err, err)
+			}
+
+			continue
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var p getPDU
+		if err := p.unmarshal(d); err != nil {
+			t.Fatal(err)
+		}
+
+		if want, got := tt.h, h; want != got {
+			t.Fatalf("unexpected header:\n- want: %+v\n-  got: %+v", want, got)
+		}
+		if want, got := tt.r, p.Ranges; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected ranges:\n- want: %+v\n-  got: %+v", want, got)
+		}
+	}
+}
+
+func TestPDUUnmarshalErrors(t *testing.T) {
+	var tests = []struct {
+		desc string
+		b    []byte
+		p    pdu
+	}{
+		{
+			desc: "short close",
+			b:    []byte{5},
+			p:    new(closePDU),
+		},
+		{
+			desc: "trailing bytes",
+			b:    []byte{5, 0, 0, 0, 0, 0, 0, 0},
+			p:    new(closePDU),
+		},
+		{
+			desc: "register with range",
+			b:    []byte{0, 127, 1, 0, 0, 0, 0, 0},
+			p:    new(registerPDU),
+		},
+		{
+			desc: "octet string too long",
+			b:    []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 8, 'a', 0, 0, 0},
+			p:    new(openPDU),
+		},
+		{
+			desc: "unknown value type",
+			b: []byte{
+				0, 0, 0, 0, 0, 0, 0, 0,
+				0, 99, 0, 0,
+				0, 0, 0, 0,
+			},
+			p: new(responsePDU),
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		d := &decoder{b: tt.b, order: binary.BigEndian}
+		if err := tt.p.unmarshal(d); !errors.Is(err, errInvalidPDU) {
+			t.Fatalf("expected invalid PDU error, but got: %v", err)
+		}
+	}
+}
@@ -0,0 +1,418 @@
+// Package agentx implements an AgentX (RFC 2741) subagent which exposes the
+// LLDP-V2-MIB to an SNMP master agent, using the state of an LLDP agent.
+package agentx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/mdlayher/lldp"
+	"github.com/mdlayher/lldp/agent"
+)
+
+// DefaultNotificationInterval is the default minimum interval between
+// lldpV2RemTablesChange notifications, which is the default value of
+// lldpV2NotificationInterval.
+const DefaultNotificationInterval = 30 * time.Second
+
+// defaultTimeout is the default time to wait for the master agent to respond.
+const defaultTimeout = 5 * time.Second
+
+// ErrClosed is returned by Subagent.Serve when the master agent closes the
+// session.
+var ErrClosed = errors.New("agentx: session closed by master agent")
+
+// A Source provides the state exposed by a Subagent.  It is implemented by
+// *agent.Agent.
+type Source interface {
+	Neighbors() *agent.NeighborTable
+	LocalFrames() map[string]*lldp.Frame
+	Stats() map[string]agent.PortStats
+}
+
+var _ Source = &agent.Agent{}
+
+// A Subagent is an AgentX subagent which registers the lldpV2Statistics,
+// lldpV2LocalSystemData, lldpV2RemTable, and lldpV2RemManAddrTable subtrees
+// of the LLDP-V2-MIB, and serves them from a Source.  The MIB is read-only.
+type Subagent struct {
+	// Source specifies the source of the objects served.
+	Source Source
+
+	// IfIndex optionally specifies a function which returns the index of
+	// the named interface.  If nil, the index is retrieved from the
+	// operating system.
+	IfIndex func(ifname string) (int, error)
+
+	// NotificationInterval optionally specifies the minimum interval
+	// between lldpV2RemTablesChange notifications, which are sent when the
+	// neighbor table changes.  If zero, DefaultNotificationInterval is
+	// used.
+	NotificationInterval time.Duration
+
+	// Timeout optionally specifies how long to wait for the master agent
+	// to respond to each request.  If zero, a default of 5 seconds is
+	// used.
+	Timeout time.Duration
+}
+
+// Serve opens an AgentX session over c, registers the LLDP-V2-MIB subtrees,
+// and serves requests from the master agent until ctx is canceled or the
+// session fails.  When ctx is canceled, the session is closed and Serve
+// returns nil.  c is closed when Serve returns.
+func (s *Subagent) Serve(ctx context.Context, c net.Conn) error {
+	defer c.Close()
+
+	ss := &session{
+		sa:        s,
+		c:         c,
+		start:     time.Now(),
+		responses: make(map[uint32]chan response),
+		remIndex:  make(map[string]uint32),
+		done:      make(chan struct{}),
+	}
+
+	readErr := make(chan error, 1)
+	go func() {
+		defer close(ss.done)
+		readErr <- ss.read()
+	}()
+
+	if err := ss.open(); err != nil {
+		// Prefer the error which caused the session to fail, if any.
+		select {
+		case <-ss.done:
+			return <-readErr
+		default:
+			return err
+		}
+	}
+
+	interval := s.NotificationInterval
+	if interval == 0 {
+		interval = DefaultNotificationInterval
+	}
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	last := s.Source.Neighbors().Stats().LastChangeTime
+	for {
+		select {
+		case <-ctx.Done():
+			// Best effort: the session is terminated regardless of the
+			// master agent's response.
+			_, _ = ss.request(pduClose, &closePDU{Reason: reasonShutdown})
+			return nil
+		case err := <-readErr:
+			return err
+		case <-t.C:
+			ts := s.Source.Neighbors().Stats()
+			if ts.LastChangeTime.Equal(last) {
+				continue
+			}
+			last = ts.LastChangeTime
+
+			if err := ss.notify(ts); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// A session is an open AgentX session.
+type session struct {
+	sa    *Subagent
+	c     net.Conn
+	start time.Time
+
+	// wmu serializes writes to c.
+	wmu sync.Mutex
+
+	// mu guards the fields below.
+	mu        sync.Mutex
+	id        uint32
+	packetID  uint32
+	responses map[uint32]chan response
+
+	// remIndex and nextIndex assign a stable lldpV2RemIndex to each
+	// neighbor.  They are only used by the read goroutine.
+	remIndex  map[string]uint32
+	nextIndex uint32
+
+	// done is closed when the read goroutine exits.
+	done chan struct{}
+}
+
+// A response is a response PDU and the header which carried it.
+type response struct {
+	h   header
+	res *responsePDU
+}
+
+// open opens the session and registers each subtree.
+func (s *session) open() error {
+	r, err := s.exchange(pduOpen, &openPDU{
+		ID:          lldpV2MIB,
+		Description: "LLDP-V2-MIB subagent",
+	})
+	if err != nil {
+		return err
+	}
+	if r.res.Error != errorNone {
+		return fmt.Errorf("agentx: failed to open session: error %d", r.res.Error)
+	}
+
+	// The master agent assigns the session ID in its response.
+	s.mu.Lock()
+	s.id = r.h.SessionID
+	s.mu.Unlock()
+
+	for _, st := range subtrees {
+		res, err := s.request(pduRegister, &registerPDU{
+			Priority: 127,
+			Subtree:  st,
+		})
+		if err != nil {
+			return err
+		}
+		if res.Error != errorNone {
+			return fmt.Errorf("agentx: failed to register %s: error %d", st, res.Error)
+		}
+	}
+
+	return nil
+}
+
+// notify sends an lldpV2RemTablesChange notification.
+func (s *session) notify(ts agent.TableStats) error {
+	res, err := s.request(pduNotify, &notifyPDU{VarBinds: []varBind{
+		{Type: typeTimeTicks, Name: sysUpTime, Value: s.uptime()},
+		{Type: typeOID, Name: snmpTrapOID, Value: lldpV2RemTablesChange},
+		{Type: typeGauge32, Name: lldpV2StatsRemTablesInserts, Value: uint32(ts.Inserts)},
+		{Type: typeGauge32, Name: lldpV2StatsRemTablesDeletes, Value: uint32(ts.Deletes)},
+		{Type: typeGauge32, Name: lldpV2StatsRemTablesDrops, Value: uint32(ts.Drops)},
+		{Type: typeGauge32, Name: lldpV2StatsRemTablesAgeouts, Value: uint32(ts.Ageouts)},
+	}})
+	if err != nil {
+		return err
+	}
+	if res.Error != errorNone {
+		return fmt.Errorf("agentx: failed to send notification: error %d", res.Error)
+	}
+
+	return nil
+}
+
+// request sends a PDU to the master agent and waits for its response.
+func (s *session) request(typ pduType, p pdu) (*responsePDU, error) {
+	r, err := s.exchange(typ, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.res, nil
+}
+
+// exchange sends a PDU to the master agent and waits for its response,
+// including the response's header.
+func (s *session) exchange(typ pduType, p pdu) (*response, error) {
+	ch := make(chan response, 1)
+
+	s.mu.Lock()
+	s.packetID++
+	h := header{
+		Type:      typ,
+		SessionID: s.id,
+		PacketID:  s.packetID,
+	}
+	s.responses[h.PacketID] = ch
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.responses, h.PacketID)
+		s.mu.Unlock()
+	}()
+
+	if err := s.write(h, p); err != nil {
+		return nil, err
+	}
+
+	timeout := s.sa.Timeout
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+
+	select {
+	case r := <-ch:
+		return &r, nil
+	case <-s.done:
+		// The response may have arrived just before the session failed.
+		select {
+		case r := <-ch:
+			return &r, nil
+		default:
+			return nil, errors.New("agentx: session failed while waiting for response")
+		}
+	case <-t.C:
+		return nil, fmt.Errorf("agentx: timed out waiting for response to PDU type %d", typ)
+	}
+}
+
+// write writes a PDU to the master agent.
+func (s *session) write(h header, p pdu) error {
+	s.wmu.Lock()
+	defer s.wmu.Unlock()
+
+	return writePDU(s.c, h, p)
+}
+
+// read reads PDUs from the master agent until the session fails, delivering
+// responses to pending requests and answering requests.
+func (s *session) read() error {
+	for {
+		h, d, err := readPDU(s.c)
+		if err != nil {
+			return err
+		}
+
+		if h.Type == pduResponse {
+			res := new(responsePDU)
+			if err := res.unmarshal(d); err != nil {
+				return err
+			}
+
+			s.mu.Lock()
+			ch, ok := s.responses[h.PacketID]
+			s.mu.Unlock()
+
+			if ok {
+				ch <- response{h: h, res: res}
+			}
+			continue
+		}
+
+		if h.Type == pduClose {
+			return ErrClosed
+		}
+
+		res := s.handle(h, d)
+		if res == nil {
+			continue
+		}
+
+		h.Type = pduResponse
+		h.Flags = 0
+		if err := s.write(h, res); err != nil {
+			return err
+		}
+	}
+}
+
+// handle produces the response to a request from the master agent, or nil if
+// no response is sent.
+func (s *session) handle(h header, d *decoder) *responsePDU {
+	res := &responsePDU{SysUpTime: s.uptime()}
+
+	switch h.Type {
+	case pduGet, pduGetNext:
+		var p getPDU
+		if err := p.unmarshal(d); err != nil {
+			res.Error = errorParse
+			return res
+		}
+
+		v := s.view()
+		for _, r := range p.Ranges {
+			if h.Type == pduGet {
+				res.VarBinds = append(res.VarBinds, v.get(r.Start))
+			} else {
+				res.VarBinds = append(res.VarBinds, v.next(r))
+			}
+		}
+	case pduGetBulk:
+		var p getBulkPDU
+		if err := p.unmarshal(d); err != nil {
+			res.Error = errorParse
+			return res
+		}
+
+		res.VarBinds = s.view().bulk(&p)
+	case pduTestSet:
+		res.Error = errorNotWritable
+		res.Index = 1
+	case pduCommitSet, pduUndoSet, pduPing:
+		// No objects are writable, so there is nothing to commit or undo.
+	case pduCleanupSet:
+		return nil
+	default:
+		res.Error = errorProcessingError
+	}
+
+	return res
+}
+
+// view builds a view of the current state of the Source.
+func (s *session) view() view {
+	src := s.sa.Source
+	nn := src.Neighbors().Neighbors()
+
+	// Assign each neighbor an index which is stable for as long as the
+	// neighbor remains in the table, and forget the indices of neighbors
+	// which are gone.
+	indices := make(map[string]uint32, len(nn))
+	for _, n := range nn {
+		k := neighborKey(n)
+		i, ok := s.remIndex[k]
+		if !ok {
+			s.nextIndex++
+			i = s.nextIndex
+		}
+		indices[k] = i
+	}
+	s.remIndex = indices
+
+	ifIndex := s.sa.IfIndex
+	if ifIndex == nil {
+		ifIndex = func(ifname string) (int, error) {
+			ifi, err := net.InterfaceByName(ifname)
+			if err != nil {
+				return 0, err
+			}
+
+			return ifi.Index, nil
+		}
+	}
+
+	return newView(snapshot{
+		local:     src.LocalFrames(),
+		stats:     src.Stats(),
+		table:     src.Neighbors().Stats(),
+		neighbors: nn,
+		ifIndex:   ifIndex,
+		remIndex:  func(n *agent.Neighbor) uint32 { return indices[neighborKey(n)] },
+		start:     s.start,
+	})
+}
+
+// neighborKey identifies a neighbor by its local interface, chassis ID, and
+// port ID.
+func neighborKey(n *agent.Neighbor) string {
+	f := n.Frame
+	return fmt.Sprintf("%s/%d/%x/%d/%x", n.Interface,
+		f.ChassisID.Subtype, f.ChassisID.ID, f.PortID.Subtype, f.PortID.ID)
+}
+
+// uptime returns the time since the session started, in hundredths of a
+// second.
+func (s *session) uptime() uint32 {
+	return timeTicks(s.start, time.Now())
+}
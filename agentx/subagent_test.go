@@ -0,0 +1,271 @@
+package agentx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/lldp"
+	"github.com/mdlayher/lldp/agent"
+)
+
+func TestSubagentServe(t *testing.T) {
+	src := newTestSource(t)
+	m, errc, cancel := serve(t, src)
+
+	h := m.expect(pduOpen, new(openPDU))
+	m.respond(h, 42, errorNone)
+
+	for _, st := range subtrees {
+		var p registerPDU
+		h := m.expect(pduRegister, &p)
+		if want, got := st, p.Subtree; want.compare(got) != 0 {
+			t.Fatalf("unexpected subtree: %s != %s", want, got)
+		}
+		if want, got := uint32(42), h.SessionID; want != got {
+			t.Fatalf("unexpected session ID: %d != %d", want, got)
+		}
+
+		m.respond(h, 42, errorNone)
+	}
+
+	res := m.request(pduGet, &getPDU{Ranges: []searchRange{
+		{Start: lldpV2LocalSystemData.append(3, 0)},
+	}})
+	if vb := res.VarBinds[0]; vb.Type != typeOctetString || !bytes.Equal(vb.Value.([]byte), []byte("host1")) {
+		t.Fatalf("unexpected local system name: %+v", vb)
+	}
+
+	res = m.request(pduGetNext, &getPDU{Ranges: []searchRange{
+		{Start: lldpV2RemTable, End: lldpV2RemManAddrTable},
+	}})
+	if vb := res.VarBinds[0]; vb.Type != typeEndOfMIBView {
+		t.Fatalf("expected an empty remote table, but got: %+v", vb)
+	}
+
+	// Discovering a neighbor triggers a notification.
+	src.nt.Update("eth0", testNeighbor(t))
+
+	var n notifyPDU
+	h = m.expect(pduNotify, &n)
+	if len(n.VarBinds) != 6 {
+		t.Fatalf("unexpected notification varbinds: %+v", n.VarBinds)
+	}
+	if vb := n.VarBinds[1]; vb.Name.compare(snmpTrapOID) != 0 || vb.Value.(oid).compare(lldpV2RemTablesChange) != 0 {
+		t.Fatalf("unexpected notification OID: %+v", vb)
+	}
+	if vb := n.VarBinds[2]; vb.Name.compare(lldpV2StatsRemTablesInserts) != 0 || vb.Value.(uint32) != 1 {
+		t.Fatalf("unexpected notification inserts: %+v", vb)
+	}
+	m.respond(h, 42, errorNone)
+
+	res = m.request(pduGetBulk, &getBulkPDU{
+		MaxRepetitions: 2,
+		Ranges:         []searchRange{{Start: lldpV2RemTable}},
+	})
+	if len(res.VarBinds) != 2 {
+		t.Fatalf("unexpected number of varbinds: %d", len(res.VarBinds))
+	}
+	if want, got := lldpV2RemTable.append(1, 6, 0, 2, 1, 1), res.VarBinds[1].Name; want.compare(got) != 0 {
+		t.Fatalf("unexpected remote chassis ID object: %s != %s", want, got)
+	}
+	if vb := res.VarBinds[1]; !bytes.Equal(vb.Value.([]byte), []byte("sw1")) {
+		t.Fatalf("unexpected remote chassis ID: %+v", vb)
+	}
+
+	if res := m.request(pduTestSet, &getPDU{}); res.Error != errorNotWritable {
+		t.Fatalf("expected not writable error, but got %d", res.Error)
+	}
+	if res := m.request(pduPing, new(emptyPDU)); res.Error != errorNone {
+		t.Fatalf("unexpected ping error: %d", res.Error)
+	}
+
+	cancel()
+
+	var c closePDU
+	h = m.expect(pduClose, &c)
+	if want, got := uint8(reasonShutdown), c.Reason; want != got {
+		t.Fatalf("unexpected close reason: %d != %d", want, got)
+	}
+	m.respond(h, 42, errorNone)
+
+	if err := <-errc; err != nil {
+		t.Fatalf("failed to serve: %v", err)
+	}
+}
+
+func TestSubagentServeClosedByMaster(t *testing.T) {
+	m, errc, _ := serve(t, newTestSource(t))
+
+	h := m.expect(pduOpen, new(openPDU))
+	m.respond(h, 1, errorNone)
+	for range subtrees {
+		h := m.expect(pduRegister, new(registerPDU))
+		m.respond(h, 1, errorNone)
+	}
+
+	if err := writePDU(m.c, header{Type: pduClose, SessionID: 1}, &closePDU{Reason: reasonShutdown}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := <-errc; !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected closed error, but got: %v", err)
+	}
+}
+
+func TestSubagentServeOpenFailed(t *testing.T) {
+	m, errc, _ := serve(t, newTestSource(t))
+
+	h := m.expect(pduOpen, new(openPDU))
+
+	// openFailed.
+	m.respond(h, 0, 256)
+
+	if err := <-errc; err == nil {
+		t.Fatal("expected an error, but none occurred")
+	}
+}
+
+// serve starts a Subagent for src connected to a fakeMaster.  The returned
+// function stops the Subagent.
+func serve(t *testing.T, src Source) (*fakeMaster, <-chan error, func()) {
+	t.Helper()
+
+	mc, sc := net.Pipe()
+	t.Cleanup(func() { _ = mc.Close() })
+	if err := mc.SetDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+
+	sa := &Subagent{
+		Source:               src,
+		IfIndex:              func(string) (int, error) { return 2, nil },
+		NotificationInterval: 10 * time.Millisecond,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	errc := make(chan error, 1)
+	go func() { errc <- sa.Serve(ctx, sc) }()
+
+	return &fakeMaster{t: t, c: mc}, errc, cancel
+}
+
+// A fakeMaster is an AgentX master agent which is driven by a test.
+type fakeMaster struct {
+	t        *testing.T
+	c        net.Conn
+	packetID uint32
+}
+
+// expect reads a PDU of the input type into p.
+func (m *fakeMaster) expect(typ pduType, p pdu) header {
+	m.t.Helper()
+
+	h, d, err := readPDU(m.c)
+	if err != nil {
+		m.t.Fatalf("failed to read PDU: %v", err)
+	}
+	if want, got := typ, h.Type; want != got {
+		m.t.Fatalf("unexpected PDU type: %d != %d", want, got)
+	}
+	if err := p.unmarshal(d); err != nil {
+		m.t.Fatalf("failed to unmarshal PDU: %v", err)
+	}
+
+	return h
+}
+
+// respond responds to the request with header h.
+func (m *fakeMaster) respond(h header, sessionID uint32, code uint16) {
+	m.t.Helper()
+
+	h.Type = pduResponse
+	h.Flags = 0
+	h.SessionID = sessionID
+	if err := writePDU(m.c, h, &responsePDU{Error: code}); err != nil {
+		m.t.Fatalf("failed to write response: %v", err)
+	}
+}
+
+// request sends a request to the subagent and returns its response.
+func (m *fakeMaster) request(typ pduType, p pdu) *responsePDU {
+	m.t.Helper()
+
+	m.packetID++
+	h := header{
+		Type:          typ,
+		SessionID:     42,
+		TransactionID: m.packetID,
+		PacketID:      m.packetID,
+	}
+	if err := writePDU(m.c, h, p); err != nil {
+		m.t.Fatalf("failed to write request: %v", err)
+	}
+
+	res := new(responsePDU)
+	rh := m.expect(pduResponse, res)
+	if want, got := h.PacketID, rh.PacketID; want != got {
+		m.t.Fatalf("unexpected response packet ID: %d != %d", want, got)
+	}
+
+	return res
+}
+
+// A testSource is a Source for a host with one interface, eth0.
+type testSource struct {
+	nt    *agent.NeighborTable
+	local *lldp.Frame
+}
+
+func newTestSource(t *testing.T) *testSource {
+	t.Helper()
+
+	f, err := lldp.NewFrameBuilder().
+		ChassisLocal("host1").
+		PortName("eth0").
+		TTL(2 * time.Minute).
+		SystemName("host1").
+		Frame()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testSource{
+		nt:    agent.NewNeighborTable(),
+		local: f,
+	}
+}
+
+func (s *testSource) Neighbors() *agent.NeighborTable { return s.nt }
+
+func (s *testSource) LocalFrames() map[string]*lldp.Frame {
+	return map[string]*lldp.Frame{"eth0": s.local}
+}
+
+func (s *testSource) Stats() map[string]agent.PortStats {
+	return map[string]agent.PortStats{"eth0": {FramesOutTotal: 1}}
+}
+
+// testNeighbor returns a frame from a neighbor with chassis ID sw1.
+func testNeighbor(t *testing.T) *lldp.EthernetFrame {
+	t.Helper()
+
+	f, err := lldp.NewFrameBuilder().
+		ChassisLocal("sw1").
+		PortName("Ethernet1").
+		TTL(2 * time.Minute).
+		Frame()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &lldp.EthernetFrame{
+		Source: net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad},
+		Frame:  f,
+	}
+}
@@ -0,0 +1,212 @@
+package lldp
+
+import (
+	"errors"
+	"math"
+	"net"
+	"time"
+)
+
+// A FrameBuilder constructs a Frame one TLV at a time, computing TLV lengths
+// automatically and validating each value as it is added.
+//
+// FrameBuilder methods may be chained.  Any problems detected along the way
+// are collected and reported together by Frame or MarshalBinary.
+type FrameBuilder struct {
+	f    Frame
+	errs []error
+}
+
+// NewFrameBuilder creates a new FrameBuilder.
+func NewFrameBuilder() *FrameBuilder {
+	return &FrameBuilder{}
+}
+
+// ChassisID sets the chassis ID of the Frame.
+func (b *FrameBuilder) ChassisID(subtype ChassisIDSubtype, id []byte) *FrameBuilder {
+	if 1+len(id) > TLVLengthMax {
+		b.fail(0, TLVTypeChassisID, ErrorReasonLengthTooLarge)
+		return b
+	}
+
+	b.f.ChassisID = &ChassisID{
+		Subtype: subtype,
+		ID:      id,
+	}
+
+	return b
+}
+
+// ChassisMAC sets the chassis ID of the Frame to a MAC address.
+func (b *FrameBuilder) ChassisMAC(mac net.HardwareAddr) *FrameBuilder {
+	return b.ChassisID(ChassisIDSubtypeMACAddress, mac)
+}
+
+// ChassisLocal sets the chassis ID of the Frame to a locally assigned
+// string.
+func (b *FrameBuilder) ChassisLocal(id string) *FrameBuilder {
+	return b.ChassisID(ChassisIDSubtypeLocallyAssigned, []byte(id))
+}
+
+// PortID sets the port ID of the Frame.
+func (b *FrameBuilder) PortID(subtype PortIDSubtype, id []byte) *FrameBuilder {
+	if 1+len(id) > TLVLengthMax {
+		b.fail(1, TLVTypePortID, ErrorReasonLengthTooLarge)
+		return b
+	}
+
+	b.f.PortID = &PortID{
+		Subtype: subtype,
+		ID:      id,
+	}
+
+	return b
+}
+
+// PortMAC sets the port ID of the Frame to a MAC address.
+func (b *FrameBuilder) PortMAC(mac net.HardwareAddr) *FrameBuilder {
+	return b.PortID(PortIDSubtypeMACAddress, mac)
+}
+
+// PortName sets the port ID of the Frame to an interface name.
+func (b *FrameBuilder) PortName(name string) *FrameBuilder {
+	return b.PortID(PortIDSubtypeInterfaceName, []byte(name))
+}
+
+// TTL sets the time-to-live of the Frame.  The TTL must be no greater than
+// 65535 seconds.
+func (b *FrameBuilder) TTL(d time.Duration) *FrameBuilder {
+	if d < 0 || d/time.Second > math.MaxUint16 {
+		b.fail(2, TLVTypeTTL, ErrorReasonTTLTooLarge)
+		return b
+	}
+
+	b.f.TTL = d
+	return b
+}
+
+// PortDescription adds a port description TLV to the Frame.
+func (b *FrameBuilder) PortDescription(s string) *FrameBuilder {
+	return b.TLV(TLVTypePortDescription, []byte(s))
+}
+
+// SystemName adds a system name TLV to the Frame.
+func (b *FrameBuilder) SystemName(s string) *FrameBuilder {
+	return b.TLV(TLVTypeSystemName, []byte(s))
+}
+
+// SystemDescription adds a system description TLV to the Frame.
+func (b *FrameBuilder) SystemDescription(s string) *FrameBuilder {
+	return b.TLV(TLVTypeSystemDescription, []byte(s))
+}
+
+// SystemCapabilities adds a system capabilities TLV to the Frame.
+func (b *FrameBuilder) SystemCapabilities(system, enabled Capability) *FrameBuilder {
+	v, _ := (&SystemCapabilities{
+		System:  system,
+		Enabled: enabled,
+	}).MarshalBinary()
+
+	return b.TLV(TLVTypeSystemCapabilities, v)
+}
+
+// ManagementAddress adds a management address TLV to the Frame.
+func (b *FrameBuilder) ManagementAddress(m *ManagementAddress) *FrameBuilder {
+	if r := m.check(); r != ErrorReasonUnknown {
+		b.fail(3+len(b.f.Optional), TLVTypeManagementAddress, r)
+		return b
+	}
+
+	v, _ := m.MarshalBinary()
+
+	return b.TLV(TLVTypeManagementAddress, v)
+}
+
+// OrgSpecific adds an organizationally specific TLV to the Frame.
+func (b *FrameBuilder) OrgSpecific(oui OUI, subtype uint8, info []byte) *FrameBuilder {
+	v, _ := (&OrganizationSpecific{
+		OUI:     oui,
+		Subtype: subtype,
+		Info:    info,
+	}).MarshalBinary()
+
+	return b.TLV(TLVTypeOrganizationSpecific, v)
+}
+
+// TLV adds an optional TLV with the input type and value to the Frame.  The
+// type must not be that of a mandatory TLV or of the end of LLDPDU TLV.
+func (b *FrameBuilder) TLV(t TLVType, v []byte) *FrameBuilder {
+	i := 3 + len(b.f.Optional)
+
+	switch {
+	case t < TLVTypePortDescription:
+		b.fail(i, t, ErrorReasonNotOptional)
+		return b
+	case t > TLVTypeMax:
+		b.fail(i, t, ErrorReasonTypeTooLarge)
+		return b
+	case len(v) > TLVLengthMax:
+		b.fail(i, t, ErrorReasonLengthTooLarge)
+		return b
+	}
+
+	b.f.Optional = append(b.f.Optional, &TLV{
+		Type:   t,
+		Length: uint16(len(v)),
+		Value:  v,
+	})
+
+	return b
+}
+
+// Frame returns the Frame built by a FrameBuilder.
+//
+// If any problems were detected while building the Frame, or the chassis ID
+// or port ID were never set, an error is returned which wraps an
+// *EncodeError for each problem.
+func (b *FrameBuilder) Frame() (*Frame, error) {
+	errs := append([]error(nil), b.errs...)
+	if b.f.ChassisID == nil {
+		errs = append(errs, frameEncodeError(ErrorReasonMissingChassisID))
+	}
+	if b.f.PortID == nil {
+		errs = append(errs, frameEncodeError(ErrorReasonMissingPortID))
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	f := b.f
+	f.Optional = make([]*TLV, len(b.f.Optional))
+	copy(f.Optional, b.f.Optional)
+
+	return &f, nil
+}
+
+// MarshalBinary allocates a byte slice and marshals the Frame built by
+// a FrameBuilder into binary form.
+//
+// MarshalBinary returns the same errors as Frame.
+func (b *FrameBuilder) MarshalBinary() ([]byte, error) {
+	f, err := b.Frame()
+	if err != nil {
+		return nil, err
+	}
+
+	return f.MarshalBinary()
+}
+
+// fail records a problem with the TLV which would appear at index i.
+func (b *FrameBuilder) fail(i int, t TLVType, r ErrorReason) {
+	err := ErrInvalidTLV
+	if r == ErrorReasonTTLTooLarge {
+		err = ErrInvalidFrame
+	}
+
+	b.errs = append(b.errs, &EncodeError{
+		Index:  i,
+		Type:   t,
+		Reason: r,
+		Err:    err,
+	})
+}
@@ -0,0 +1,242 @@
+package lldp
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFrameBuilderOK(t *testing.T) {
+	b, err := NewFrameBuilder().
+		ChassisMAC(net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad}).
+		PortName("eth0").
+		TTL(120*time.Second).
+		SystemName("host").
+		OrgSpecific(OUIIEEE8021, 1, []byte{0x00, 0x0a}).
+		MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []byte{
+		0x02, 0x07, 4, 0xde, 0xad, 0xbe, 0xef, 0xde, 0xad,
+		0x04, 0x05, 5, 'e', 't', 'h', '0',
+		0x06, 0x02, 0x00, 0x78,
+		0x0a, 0x04, 'h', 'o', 's', 't',
+		0xfe, 0x06, 0x00, 0x80, 0xc2, 1, 0x00, 0x0a,
+		0x00, 0x00,
+	}
+
+	if !bytes.Equal(want, b) {
+		t.Fatalf("unexpected Frame bytes:\n- want: %v\n-  got: %v", want, b)
+	}
+}
+
+func TestFrameBuilderErrors(t *testing.T) {
+	_, err := NewFrameBuilder().
+		TTL((math.MaxUint16+1)*time.Second).
+		SystemName(string(make([]byte, TLVLengthMax+1))).
+		TLV(TLVTypeMax+1, nil).
+		TLV(TLVTypeEnd, nil).
+		TLV(TLVTypeChassisID, []byte{1}).
+		TLV(TLVTypeTTL, []byte{0, 120}).
+		Frame()
+
+	want := []*EncodeError{
+		{Index: 2, Type: TLVTypeTTL, Reason: ErrorReasonTTLTooLarge, Err: ErrInvalidFrame},
+		{Index: 3, Type: TLVTypeSystemName, Reason: ErrorReasonLengthTooLarge, Err: ErrInvalidTLV},
+		{Index: 3, Type: TLVTypeMax + 1, Reason: ErrorReasonTypeTooLarge, Err: ErrInvalidTLV},
+		{Index: 3, Type: TLVTypeEnd, Reason: ErrorReasonNotOptional, Err: ErrInvalidTLV},
+		{Index: 3, Type: TLVTypeChassisID, Reason: ErrorReasonNotOptional, Err: ErrInvalidTLV},
+		{Index: 3, Type: TLVTypeTTL, Reason: ErrorReasonNotOptional, Err: ErrInvalidTLV},
+		{Index: -1, Reason: ErrorReasonMissingChassisID, Err: ErrInvalidFrame},
+		{Index: -1, Reason: ErrorReasonMissingPortID, Err: ErrInvalidFrame},
+	}
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("expected aggregated error, but got: %#v", err)
+	}
+
+	errs := joined.Unwrap()
+	if want, got := len(want), len(errs); want != got {
+		t.Fatalf("unexpected number of errors: %d != %d: %v", want, got, err)
+	}
+
+	for i, e := range errs {
+		var ee *EncodeError
+		if !errors.As(e, &ee) {
+			t.Fatalf("expected *EncodeError, but got: %#v", e)
+		}
+
+		if *want[i] != *ee {
+			t.Fatalf("unexpected EncodeError:\n- want: %#v\n-  got: %#v", want[i], ee)
+		}
+	}
+
+	if !errors.Is(err, ErrInvalidTLV) || !errors.Is(err, ErrInvalidFrame) {
+		t.Fatalf("aggregated error does not match sentinel errors: %v", err)
+	}
+}
+
+func TestFrameBuilderFrameTwice(t *testing.T) {
+	// Three problems leave spare capacity in the recorded errors, which
+	// must not be shared with the errors returned by Frame.
+	b := NewFrameBuilder().
+		TLV(TLVTypeMax+1, nil).
+		TLV(TLVTypeMax+1, nil).
+		TLV(TLVTypeMax+1, nil).
+		PortName("eth0")
+
+	_, err1 := b.Frame()
+	b.TLV(TLVTypeMax+1, nil)
+	_, err2 := b.Frame()
+
+	for i, tt := range []struct {
+		err error
+		n   int
+	}{
+		{err: err1, n: 4},
+		{err: err2, n: 5},
+	} {
+		errs := tt.err.(interface{ Unwrap() []error }).Unwrap()
+		if want, got := tt.n, len(errs); want != got {
+			t.Fatalf("[%02d] unexpected number of errors: %d != %d: %v", i, want, got, tt.err)
+		}
+
+		var ee *EncodeError
+		if !errors.As(errs[tt.n-1], &ee) || ee.Reason != ErrorReasonMissingChassisID {
+			t.Fatalf("[%02d] unexpected final error: %v", i, errs[tt.n-1])
+		}
+	}
+
+	b = NewFrameBuilder().ChassisLocal("host").PortName("eth0").SystemName("a")
+	f1, err := b.Frame()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b.SystemName("b")
+	f2, err := b.Frame()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := 1, len(f1.Optional); want != got {
+		t.Fatalf("first Frame modified by builder: %d != %d optional TLVs", want, got)
+	}
+	if want, got := 2, len(f2.Optional); want != got {
+		t.Fatalf("unexpected optional TLVs in second Frame: %d != %d", want, got)
+	}
+}
+
+func TestFrameBuilderSystemCapabilitiesManagementAddress(t *testing.T) {
+	f, err := NewFrameBuilder().
+		ChassisLocal("host").
+		PortName("eth0").
+		SystemCapabilities(CapabilityBridge|CapabilityRouter, CapabilityRouter).
+		ManagementAddress(&ManagementAddress{
+			Family:             AddressFamilyIPv4,
+			Address:            []byte{192, 0, 2, 1},
+			InterfaceNumbering: InterfaceNumberingIfIndex,
+			InterfaceNumber:    2,
+		}).
+		Frame()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*TLV{
+		{
+			Type:   TLVTypeSystemCapabilities,
+			Length: 4,
+			Value:  []byte{0x00, 0x14, 0x00, 0x10},
+		},
+		{
+			Type:   TLVTypeManagementAddress,
+			Length: 12,
+			Value: []byte{
+				5, 1, 192, 0, 2, 1,
+				2, 0, 0, 0, 2,
+				0,
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(want, f.Optional) {
+		t.Fatalf("unexpected optional TLVs:\n- want: %v\n-  got: %v", want, f.Optional)
+	}
+}
+
+func TestFrameBuilderManagementAddressErrors(t *testing.T) {
+	var tests = []struct {
+		desc string
+		m    *ManagementAddress
+		r    ErrorReason
+	}{
+		{
+			desc: "no family",
+			m: &ManagementAddress{
+				Address:            []byte{192, 0, 2, 1},
+				InterfaceNumbering: InterfaceNumberingUnknown,
+			},
+			r: ErrorReasonInvalidAddressFamily,
+		},
+		{
+			desc: "no address",
+			m: &ManagementAddress{
+				Family:             AddressFamilyIPv4,
+				InterfaceNumbering: InterfaceNumberingUnknown,
+			},
+			r: ErrorReasonInvalidAddressLength,
+		},
+		{
+			desc: "invalid interface numbering",
+			m: &ManagementAddress{
+				Family:  AddressFamilyIPv4,
+				Address: []byte{192, 0, 2, 1},
+			},
+			r: ErrorReasonInvalidInterfaceNumbering,
+		},
+		{
+			desc: "OID too long",
+			m: &ManagementAddress{
+				Family:             AddressFamilyIPv4,
+				Address:            []byte{192, 0, 2, 1},
+				InterfaceNumbering: InterfaceNumberingUnknown,
+				OID:                make([]byte, 129),
+			},
+			r: ErrorReasonOIDTooLarge,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		_, err := NewFrameBuilder().
+			ChassisLocal("host").
+			PortName("eth0").
+			SystemName("host").
+			ManagementAddress(tt.m).
+			Frame()
+
+		var ee *EncodeError
+		if !errors.As(err, &ee) {
+			t.Fatalf("expected *EncodeError, but got: %#v", err)
+		}
+
+		want := EncodeError{
+			Index:  4,
+			Type:   TLVTypeManagementAddress,
+			Reason: tt.r,
+			Err:    ErrInvalidTLV,
+		}
+		if want != *ee {
+			t.Fatalf("unexpected EncodeError:\n- want: %#v\n-  got: %#v", want, *ee)
+		}
+	}
+}
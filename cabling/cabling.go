@@ -0,0 +1,362 @@
+// Package cabling verifies the LLDP neighbors observed on a system against
+// a cabling plan.
+//
+// A Plan specifies the neighbor expected on each local port.  Verify
+// compares a Plan with the neighbors actually observed, and reports each
+// port whose neighbor is missing, unexpected, or mis-patched.
+package cabling
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/mdlayher/lldp"
+	"github.com/mdlayher/lldp/internal/format"
+	"github.com/mdlayher/lldp/portname"
+	"gopkg.in/yaml.v3"
+)
+
+// A Plan is an intended cabling plan for the ports of a single system.
+type Plan struct {
+	// Links specifies the link expected on each local port, keyed by
+	// local interface name.
+	Links map[string]Link `yaml:"links"`
+
+	// Aliases specifies alternative names for interfaces which are not
+	// recognized by package portname, keyed by the name used in Links, such
+	// as {"eth1": ["enp1s0"]}.  Aliases apply to both local and remote
+	// interface names.
+	Aliases map[string][]string `yaml:"aliases"`
+}
+
+// A Link is the neighbor expected on a local port.  At least one of
+// SystemName and ChassisID must be set, and Port must be set.
+type Link struct {
+	// SystemName specifies the system name of the neighbor.  A neighbor
+	// whose system name is qualified with a domain, such as
+	// "sw1.example.com", matches the unqualified name "sw1".
+	SystemName string `yaml:"system_name"`
+
+	// ChassisID specifies the chassis ID of the neighbor, formatted as
+	// reported by lldpctl: as a MAC address or network address according
+	// to its subtype, or otherwise as text if printable, or in hexadecimal.
+	ChassisID string `yaml:"chassis_id"`
+
+	// Port specifies the neighbor's interface name, which matches either
+	// its port ID or its port description.
+	Port string `yaml:"port"`
+}
+
+// String returns a description of a Link, such as "sw1 port Ethernet1".
+func (l Link) String() string {
+	name := l.SystemName
+	if name == "" {
+		name = l.ChassisID
+	}
+
+	return name + " port " + l.Port
+}
+
+// ErrInvalidPlan is returned when a Plan contains an incomplete Link.
+var ErrInvalidPlan = errors.New("cabling: invalid plan")
+
+// ParsePlan parses a YAML Plan from r, and validates each of its links.
+func ParsePlan(r io.Reader) (*Plan, error) {
+	var p Plan
+	d := yaml.NewDecoder(r)
+	d.KnownFields(true)
+	if err := d.Decode(&p); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("cabling: failed to decode plan: %w", err)
+	}
+
+	for port, l := range p.Links {
+		switch {
+		case l.SystemName == "" && l.ChassisID == "":
+			return nil, fmt.Errorf("%w: port %q: no system_name or chassis_id", ErrInvalidPlan, port)
+		case l.Port == "":
+			return nil, fmt.Errorf("%w: port %q: no remote port", ErrInvalidPlan, port)
+		}
+	}
+
+	return &p, nil
+}
+
+// An Observation is a neighbor observed on a local port.
+type Observation struct {
+	// Port specifies the name of the local interface on which the
+	// neighbor was observed.
+	Port string `json:"port"`
+
+	// SystemName, ChassisID, PortID, and PortDescription specify the
+	// neighbor's identity.  ChassisID and PortID are formatted as
+	// described for Link.ChassisID.
+	SystemName      string `json:"system_name,omitempty"`
+	ChassisID       string `json:"chassis_id"`
+	PortID          string `json:"port_id"`
+	PortDescription string `json:"port_description,omitempty"`
+}
+
+// String returns a description of an Observation, such as
+// "sw1 port Ethernet1".
+func (o Observation) String() string {
+	name := o.SystemName
+	if name == "" {
+		name = o.ChassisID
+	}
+
+	return name + " port " + o.PortID
+}
+
+// NewObservation returns the Observation of a frame received on ifname.
+func NewObservation(ifname string, f *lldp.Frame) Observation {
+	o := Observation{Port: ifname}
+	_, o.ChassisID = format.ChassisID(f.ChassisID)
+	_, o.PortID = format.PortID(f.PortID)
+
+	for _, t := range f.Optional {
+		switch t.Type {
+		case lldp.TLVTypeSystemName:
+			o.SystemName = string(t.Value)
+		case lldp.TLVTypePortDescription:
+			o.PortDescription = string(t.Value)
+		}
+	}
+
+	return o.Normalize()
+}
+
+// Normalize returns o with surrounding white space removed from each of its
+// fields.  NewObservation normalizes its Observations, and Observations
+// built from other sources, such as the neighbors reported by lldpctl,
+// should be normalized the same way before they are passed to Verify.
+func (o Observation) Normalize() Observation {
+	o.Port = strings.TrimSpace(o.Port)
+	o.SystemName = strings.TrimSpace(o.SystemName)
+	o.ChassisID = strings.TrimSpace(o.ChassisID)
+	o.PortID = strings.TrimSpace(o.PortID)
+	o.PortDescription = strings.TrimSpace(o.PortDescription)
+
+	return o
+}
+
+// A FindingType is a kind of discrepancy between a Plan and the observed
+// neighbors.
+type FindingType int
+
+// List of valid FindingType values.
+const (
+	// FindingMissing indicates that no neighbor was observed on a port
+	// which the plan expects to be cabled.
+	FindingMissing FindingType = iota
+
+	// FindingUnexpected indicates that a neighbor was observed on a port
+	// which the plan does not expect to be cabled, or on a port which
+	// already has its expected neighbor.
+	FindingUnexpected
+
+	// FindingMispatched indicates that a neighbor was observed on a port
+	// which the plan expects to be cabled, but it was not the expected
+	// neighbor.
+	FindingMispatched
+)
+
+// findingTypeNames maps FindingType values to their text form.
+var findingTypeNames = map[FindingType]string{
+	FindingMissing:    "missing",
+	FindingUnexpected: "unexpected",
+	FindingMispatched: "mispatched",
+}
+
+// String returns the text form of a FindingType.
+func (t FindingType) String() string {
+	if n, ok := findingTypeNames[t]; ok {
+		return n
+	}
+
+	return fmt.Sprintf("FindingType(%d)", int(t))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (t FindingType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// A Finding is a discrepancy between a Plan and the observed neighbors on
+// a single local port.
+type Finding struct {
+	Type FindingType `json:"type"`
+
+	// Port specifies the local interface name.
+	Port string `json:"port"`
+
+	// Expected and Observed specify the expected link and the observed
+	// neighbor, when present.
+	Expected *Link        `json:"expected,omitempty"`
+	Observed *Observation `json:"observed,omitempty"`
+}
+
+// String returns a description of a Finding.
+func (f Finding) String() string {
+	switch f.Type {
+	case FindingMissing:
+		return fmt.Sprintf("%s: missing: expected %s", f.Port, f.Expected)
+	case FindingUnexpected:
+		return fmt.Sprintf("%s: unexpected: observed %s", f.Port, f.Observed)
+	case FindingMispatched:
+		return fmt.Sprintf("%s: mispatched: expected %s, observed %s", f.Port, f.Expected, f.Observed)
+	default:
+		return fmt.Sprintf("%s: %s", f.Port, f.Type)
+	}
+}
+
+// Verify compares the neighbors observed on a system with a Plan, and
+// returns the findings for each port, sorted by port name.  Verify returns
+// no findings if the observed cabling matches the Plan.
+//
+// Interface names are compared using portname.Equal, so that vendor
+// abbreviations such as "Gi1/0/1" match their full names, and using the
+// Plan's Aliases.  A neighbor matches a Link if its system name or chassis
+// ID matches, whichever the Link specifies, and its port ID or port
+// description matches the Link's port.
+func (p *Plan) Verify(observed []Observation) []Finding {
+	// Group the observations by the local port name used in the plan.
+	byPort := make(map[string][]Observation)
+	for _, o := range observed {
+		port := o.Port
+		for name := range p.Links {
+			if p.sameInterface(name, o.Port) {
+				port = name
+				break
+			}
+		}
+
+		byPort[port] = append(byPort[port], o)
+	}
+
+	var ff []Finding
+	for port, l := range p.Links {
+		l := l
+
+		oo := byPort[port]
+		if len(oo) == 0 {
+			ff = append(ff, Finding{
+				Type:     FindingMissing,
+				Port:     port,
+				Expected: &l,
+			})
+			continue
+		}
+
+		match := -1
+		for i, o := range oo {
+			if p.matches(l, o) {
+				match = i
+				break
+			}
+		}
+
+		for i := range oo {
+			if i == match {
+				continue
+			}
+
+			f := Finding{
+				Type:     FindingUnexpected,
+				Port:     port,
+				Observed: &oo[i],
+			}
+			if match == -1 {
+				f.Type = FindingMispatched
+				f.Expected = &l
+			}
+
+			ff = append(ff, f)
+		}
+	}
+
+	for port, oo := range byPort {
+		if _, ok := p.Links[port]; ok {
+			continue
+		}
+
+		for i := range oo {
+			ff = append(ff, Finding{
+				Type:     FindingUnexpected,
+				Port:     port,
+				Observed: &oo[i],
+			})
+		}
+	}
+
+	sort.SliceStable(ff, func(i, j int) bool {
+		if ff[i].Port != ff[j].Port {
+			return ff[i].Port < ff[j].Port
+		}
+
+		return ff[i].Type < ff[j].Type
+	})
+
+	return ff
+}
+
+// matches reports whether an Observation is the neighbor expected by l.
+func (p *Plan) matches(l Link, o Observation) bool {
+	if l.SystemName != "" && !sameSystem(l.SystemName, o.SystemName) {
+		return false
+	}
+	if l.ChassisID != "" && !sameChassis(l.ChassisID, o.ChassisID) {
+		return false
+	}
+
+	return p.sameInterface(l.Port, o.PortID) ||
+		(o.PortDescription != "" && p.sameInterface(l.Port, o.PortDescription))
+}
+
+// sameInterface reports whether a and b name the same interface, where a
+// may be a name used in the Plan which has aliases.
+func (p *Plan) sameInterface(a, b string) bool {
+	if portname.Equal(a, b) {
+		return true
+	}
+
+	for _, alias := range p.Aliases[a] {
+		if portname.Equal(alias, b) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sameSystem reports whether the observed system name matches the expected
+// name, allowing the observed name to be qualified with a domain.
+func sameSystem(want, got string) bool {
+	if strings.EqualFold(want, got) {
+		return true
+	}
+
+	return len(got) > len(want) && got[len(want)] == '.' && strings.EqualFold(want, got[:len(want)])
+}
+
+// sameChassis reports whether two chassis IDs are the same, comparing MAC
+// addresses in any of the notations accepted by net.ParseMAC.
+func sameChassis(want, got string) bool {
+	if strings.EqualFold(want, got) {
+		return true
+	}
+
+	a, err := net.ParseMAC(want)
+	if err != nil {
+		return false
+	}
+	b, err := net.ParseMAC(got)
+	if err != nil {
+		return false
+	}
+
+	return a.String() == b.String()
+}
@@ -0,0 +1,187 @@
+package cabling
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/lldp"
+)
+
+func TestParsePlan(t *testing.T) {
+	var tests = []struct {
+		desc string
+		s    string
+		p    *Plan
+		err  error
+	}{
+		{
+			desc: "empty",
+			p:    &Plan{},
+		},
+		{
+			desc: "OK",
+			s: `links:
+  eth0:
+    system_name: sw1
+    port: Ethernet1
+  eth1:
+    chassis_id: de:ad:be:ef:de:ad
+    port: Ethernet2
+aliases:
+  Ethernet1:
+    - Eth1
+`,
+			p: &Plan{
+				Links: map[string]Link{
+					"eth0": {SystemName: "sw1", Port: "Ethernet1"},
+					"eth1": {ChassisID: "de:ad:be:ef:de:ad", Port: "Ethernet2"},
+				},
+				Aliases: map[string][]string{"Ethernet1": {"Eth1"}},
+			},
+		},
+		{
+			desc: "no system",
+			s:    "links:\n  eth0:\n    port: Ethernet1\n",
+			err:  ErrInvalidPlan,
+		},
+		{
+			desc: "no port",
+			s:    "links:\n  eth0:\n    system_name: sw1\n",
+			err:  ErrInvalidPlan,
+		},
+		{
+			desc: "unknown field",
+			s:    "links:\n  eth0:\n    system: sw1\n    port: Ethernet1\n",
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		p, err := ParsePlan(strings.NewReader(tt.s))
+		if tt.p == nil {
+			if err == nil {
+				t.Fatal("expected an error, but none occurred")
+			}
+			if tt.err != nil && !errors.Is(err, tt.err) {
+				t.Fatalf("unexpected error: %v != %v", tt.err, err)
+			}
+
+			continue
+		}
+		if err != nil {
+			t.Fatalf("failed to parse plan: %v", err)
+		}
+
+		if want, got := tt.p, p; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected plan:\n- want: %+v\n-  got: %+v", want, got)
+		}
+	}
+}
+
+func TestPlanVerify(t *testing.T) {
+	p := &Plan{
+		Links: map[string]Link{
+			"eth0": {SystemName: "sw1", Port: "Ethernet1"},
+			"eth1": {ChassisID: "de:ad:be:ef:de:ad", Port: "Ethernet2"},
+		},
+		Aliases: map[string][]string{
+			"eth1": {"enp1s0"},
+		},
+	}
+
+	sw1 := Observation{
+		Port:       "eth0",
+		SystemName: "sw1.example.com",
+		ChassisID:  "sw1",
+		// Abbreviated.
+		PortID: "Et1",
+	}
+	sw2 := Observation{
+		Port:            "enp1s0",
+		SystemName:      "sw2",
+		ChassisID:       "dead.beef.dead",
+		PortID:          "1002",
+		PortDescription: "ethernet2",
+	}
+	sw3 := Observation{
+		Port:       "eth0",
+		SystemName: "sw3",
+		ChassisID:  "sw3",
+		PortID:     "Ethernet1",
+	}
+
+	var tests = []struct {
+		desc string
+		oo   []Observation
+		ff   []string
+	}{
+		{
+			desc: "matches plan",
+			oo:   []Observation{sw1, sw2},
+		},
+		{
+			desc: "missing",
+			oo:   []Observation{sw1},
+			ff:   []string{"eth1: missing: expected de:ad:be:ef:de:ad port Ethernet2"},
+		},
+		{
+			desc: "mispatched",
+			oo:   []Observation{sw3, sw2},
+			ff:   []string{"eth0: mispatched: expected sw1 port Ethernet1, observed sw3 port Ethernet1"},
+		},
+		{
+			desc: "unexpected",
+			oo: []Observation{
+				sw1, sw2, sw3,
+				{Port: "eth2", ChassisID: "host9", PortID: "eth0"},
+			},
+			ff: []string{
+				"eth0: unexpected: observed sw3 port Ethernet1",
+				"eth2: unexpected: observed host9 port eth0",
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		var ff []string
+		for _, f := range p.Verify(tt.oo) {
+			ff = append(ff, f.String())
+		}
+
+		if want, got := tt.ff, ff; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected findings:\n- want: %q\n-  got: %q", want, got)
+		}
+	}
+}
+
+func TestNewObservation(t *testing.T) {
+	f, err := lldp.NewFrameBuilder().
+		ChassisMAC(net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad}).
+		PortName("Ethernet1").
+		TTL(2 * time.Minute).
+		PortDescription("to host1 ").
+		SystemName(" sw1").
+		Frame()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Observation{
+		Port:            "eth0",
+		SystemName:      "sw1",
+		ChassisID:       "de:ad:be:ef:de:ad",
+		PortID:          "Ethernet1",
+		PortDescription: "to host1",
+	}
+
+	if got := NewObservation("eth0", f); want != got {
+		t.Fatalf("unexpected observation:\n- want: %+v\n-  got: %+v", want, got)
+	}
+}
@@ -0,0 +1,65 @@
+package lldp
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// A Capability is a bit mask of system capabilities, as carried in
+// a SystemCapabilities.
+type Capability uint16
+
+// List of valid Capability values.
+const (
+	CapabilityOther           Capability = 1 << 0
+	CapabilityRepeater        Capability = 1 << 1
+	CapabilityBridge          Capability = 1 << 2
+	CapabilityWLANAccessPoint Capability = 1 << 3
+	CapabilityRouter          Capability = 1 << 4
+	CapabilityTelephone       Capability = 1 << 5
+	CapabilityDOCSIS          Capability = 1 << 6
+	CapabilityStationOnly     Capability = 1 << 7
+	CapabilityCustomerBridge  Capability = 1 << 8
+	CapabilityServiceBridge   Capability = 1 << 9
+	CapabilityTwoPortMACRelay Capability = 1 << 10
+)
+
+// A SystemCapabilities is a structure parsed from a system capabilities TLV.
+// It contains information about the primary functions of a system, and
+// which of those functions are enabled.
+type SystemCapabilities struct {
+	// System specifies the capabilities supported by a system.
+	System Capability
+
+	// Enabled specifies the capabilities currently enabled on a system.
+	Enabled Capability
+}
+
+// MarshalBinary allocates a byte slice and marshals a SystemCapabilities into
+// binary form.
+//
+// MarshalBinary never returns an error.
+func (s *SystemCapabilities) MarshalBinary() ([]byte, error) {
+	// 2 bytes: system capabilities
+	// 2 bytes: enabled capabilities
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint16(b[0:2], uint16(s.System))
+	binary.BigEndian.PutUint16(b[2:4], uint16(s.Enabled))
+
+	return b, nil
+}
+
+// UnmarshalBinary unmarshals a byte slice into a SystemCapabilities.
+//
+// If the byte slice does not contain exactly 4 bytes, io.ErrUnexpectedEOF
+// is returned.
+func (s *SystemCapabilities) UnmarshalBinary(b []byte) error {
+	if len(b) != 4 {
+		return io.ErrUnexpectedEOF
+	}
+
+	s.System = Capability(binary.BigEndian.Uint16(b[0:2]))
+	s.Enabled = Capability(binary.BigEndian.Uint16(b[2:4]))
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package lldp
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestSystemCapabilitiesMarshalBinary(t *testing.T) {
+	s := &SystemCapabilities{
+		System:  CapabilityBridge | CapabilityRouter | CapabilityStationOnly,
+		Enabled: CapabilityRouter,
+	}
+
+	b, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := []byte{0x00, 0x94, 0x00, 0x10}, b; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected SystemCapabilities bytes:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+func TestSystemCapabilitiesUnmarshalBinary(t *testing.T) {
+	var tests = []struct {
+		desc string
+		b    []byte
+		s    *SystemCapabilities
+		err  error
+	}{
+		{
+			desc: "nil buffer",
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "long buffer",
+			b:    []byte{0, 0, 0, 0, 0},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "OK",
+			b:    []byte{0x00, 0x14, 0x00, 0x04},
+			s: &SystemCapabilities{
+				System:  CapabilityBridge | CapabilityRouter,
+				Enabled: CapabilityBridge,
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		s := new(SystemCapabilities)
+		if err := s.UnmarshalBinary(tt.b); err != nil {
+			if want, got := tt.err, err; want != got {
+				t.Fatalf("unexpected error:\n- want: %v\n-  got: %v", want, got)
+			}
+
+			continue
+		}
+
+		if want, got := tt.s, s; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected SystemCapabilities:\n- want: %v\n-  got: %v", want, got)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Command lldp_exporter is a Prometheus exporter which receives LLDP frames on
+// a set of interfaces, and exposes the neighbors discovered and the
+// statistics of each interface.
+//
+// lldp_exporter only receives frames, so it may run alongside lldpd or any
+// other LLDP daemon.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/mdlayher/lldp/agent"
+	"github.com/mdlayher/lldp/host"
+	lldpprom "github.com/mdlayher/lldp/prometheus"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+func main() {
+	var (
+		addrFlag       = flag.String("l", ":9808", "address for the HTTP metrics server")
+		pathFlag       = flag.String("p", "/metrics", "HTTP path for metrics")
+		interfacesFlag = flag.String("i", "", "comma-separated list of interfaces; empty for every physical Ethernet interface")
+	)
+
+	flag.Parse()
+
+	ll := log.New(os.Stderr, "", log.LstdFlags)
+
+	var ifis []string
+	if *interfacesFlag != "" {
+		ifis = strings.Split(*interfacesFlag, ",")
+	} else {
+		var err error
+		ifis, err = new(host.Collector).Interfaces()
+		if err != nil {
+			ll.Fatalf("failed to list interfaces: %v", err)
+		}
+	}
+
+	cfg := agent.Config{Ports: make(map[string]agent.PortConfig, len(ifis))}
+	for _, ifi := range ifis {
+		cfg.Ports[ifi] = agent.PortConfig{AdminStatus: agent.AdminStatusRx}
+	}
+
+	a := agent.New(cfg, host.Collector{}, agent.Listen, ll)
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(lldpprom.New(a))
+
+	mux := http.NewServeMux()
+	mux.Handle(*pathFlag, promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	go func() {
+		ll.Printf("serving metrics on %q", *addrFlag)
+		if err := http.ListenAndServe(*addrFlag, mux); err != nil {
+			ll.Fatalf("failed to serve metrics: %v", err)
+		}
+	}()
+
+	ll.Printf("receiving on %d interfaces", len(ifis))
+
+	if err := a.Run(ctx); err != nil {
+		ll.Fatalf("failed to run: %v", err)
+	}
+}
@@ -0,0 +1,292 @@
+// Command lldpctl queries and controls a running lldpd over its control
+// socket.
+//
+// Usage:
+//
+//	lldpctl [flags] neighbors [INTERFACE]
+//	lldpctl [flags] neighbor INTERFACE INDEX
+//	lldpctl [flags] local [INTERFACE]
+//	lldpctl [flags] counters [INTERFACE]
+//	lldpctl [flags] set INTERFACE both|tx|rx|disabled
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mdlayher/lldp/agent"
+	"github.com/mdlayher/lldp/control"
+)
+
+func main() {
+	var (
+		socketFlag = flag.String("s", "/run/lldpd.sock", "path to lldpd control socket")
+		jsonFlag   = flag.Bool("json", false, "produce JSON output")
+	)
+
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), `usage:
+  lldpctl [flags] neighbors [INTERFACE]
+  lldpctl [flags] neighbor INTERFACE INDEX
+  lldpctl [flags] local [INTERFACE]
+  lldpctl [flags] counters [INTERFACE]
+  lldpctl [flags] set INTERFACE both|tx|rx|disabled
+
+flags:
+`)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	c, err := control.Dial(*socketFlag)
+	if err != nil {
+		fatalf("failed to connect to lldpd: %v", err)
+	}
+	defer c.Close()
+
+	if err := run(c, flag.Args(), *jsonFlag, os.Stdout); err != nil {
+		if errors.Is(err, errUsage) {
+			flag.Usage()
+			os.Exit(2)
+		}
+
+		fatalf("%v", err)
+	}
+}
+
+// errUsage indicates that lldpctl was invoked with invalid arguments.
+var errUsage = errors.New("invalid usage")
+
+// run executes the command specified by args using c, writing its output to
+// w as text or as JSON.
+func run(c *control.Client, args []string, asJSON bool, w io.Writer) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	cmd, args := args[0], args[1:]
+	optional := func() (string, error) {
+		switch len(args) {
+		case 0:
+			return "", nil
+		case 1:
+			return args[0], nil
+		default:
+			return "", errUsage
+		}
+	}
+
+	var (
+		v   any
+		err error
+	)
+
+	switch cmd {
+	case "neighbors":
+		ifname, uerr := optional()
+		if uerr != nil {
+			return uerr
+		}
+
+		var nn []control.Neighbor
+		nn, err = c.Neighbors(ifname)
+		if err == nil && !asJSON {
+			return printNeighbors(w, nn)
+		}
+		v = nn
+	case "neighbor":
+		if len(args) != 2 {
+			return errUsage
+		}
+		index, perr := strconv.Atoi(args[1])
+		if perr != nil {
+			return errUsage
+		}
+
+		var n *control.Neighbor
+		n, err = c.Neighbor(args[0], index)
+		if err == nil && !asJSON {
+			return printNeighbor(w, n)
+		}
+		v = n
+	case "local":
+		ifname, uerr := optional()
+		if uerr != nil {
+			return uerr
+		}
+
+		var pp []control.Port
+		pp, err = c.Local(ifname)
+		if err == nil && !asJSON {
+			return printLocal(w, pp)
+		}
+		v = pp
+	case "counters":
+		ifname, uerr := optional()
+		if uerr != nil {
+			return uerr
+		}
+
+		var (
+			pp    []control.Port
+			table *agent.TableStats
+		)
+		pp, table, err = c.Counters(ifname)
+		if err == nil && !asJSON {
+			return printCounters(w, pp, table)
+		}
+		v = map[string]any{
+			"ports": pp,
+			"table": table,
+		}
+	case "set":
+		if len(args) != 2 {
+			return errUsage
+		}
+
+		var status agent.AdminStatus
+		if err := status.UnmarshalText([]byte(args[1])); err != nil {
+			return err
+		}
+
+		err = c.SetAdminStatus(args[0], status)
+		if err == nil && !asJSON {
+			return nil
+		}
+		v = map[string]any{
+			"interface":    args[0],
+			"admin_status": status,
+		}
+	default:
+		return errUsage
+	}
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// printNeighbors prints a summary of each neighbor.
+func printNeighbors(w io.Writer, nn []control.Neighbor) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "INTERFACE\tINDEX\tCHASSIS ID\tPORT ID\tSYSTEM NAME\tEXPIRES")
+
+	now := time.Now()
+	for _, n := range nn {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\t%s\n",
+			n.Interface, n.Index, n.Frame.ChassisID, n.Frame.PortID,
+			tlvValue(n.Frame, "system-name"), n.Expires.Sub(now).Round(time.Second))
+	}
+
+	return tw.Flush()
+}
+
+// printNeighbor prints the full details of a single neighbor.
+func printNeighbor(w io.Writer, n *control.Neighbor) error {
+	fmt.Fprintf(w, "Interface: %s\nSource:    %s\nUpdated:   %s\nExpires:   %s\n",
+		n.Interface, n.Source, n.Updated.Format(time.RFC3339), n.Expires.Format(time.RFC3339))
+
+	return printFrame(w, n.Frame)
+}
+
+// printLocal prints the frame most recently transmitted on each port.
+func printLocal(w io.Writer, pp []control.Port) error {
+	for i, p := range pp {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+
+		fmt.Fprintf(w, "Interface: %s (%s)\n", p.Interface, p.AdminStatus)
+		if p.Frame == nil {
+			fmt.Fprintln(w, "  not transmitting")
+			continue
+		}
+
+		if err := printFrame(w, *p.Frame); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// printFrame prints each TLV in a Frame.
+func printFrame(w io.Writer, f control.Frame) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "  chassis-id\t%s\t(%s)\n", f.ChassisID.Value, f.ChassisID.Subtype)
+	fmt.Fprintf(tw, "  port-id\t%s\t(%s)\n", f.PortID.Value, f.PortID.Subtype)
+	fmt.Fprintf(tw, "  ttl\t%ds\t\n", f.TTL)
+
+	for _, t := range f.TLVs {
+		value := t.Value
+		if value == "" {
+			value = t.Raw
+		}
+
+		if t.OUI != "" && t.Subtype != nil {
+			fmt.Fprintf(tw, "  %s\t%s\t(%s/%d)\n", t.Name, value, t.OUI, *t.Subtype)
+		} else {
+			fmt.Fprintf(tw, "  %s\t%s\t\n", t.Name, value)
+		}
+	}
+
+	return tw.Flush()
+}
+
+// printCounters prints the statistics of each port, followed by the
+// statistics of the neighbor table.
+func printCounters(w io.Writer, pp []control.Port, table *agent.TableStats) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "INTERFACE\tADMIN\tTX\tRX\tDISCARDED\tERRORS\tTLVS DISCARDED\tTLVS UNRECOGNIZED\tAGEOUTS")
+
+	for _, p := range pp {
+		var s agent.PortStats
+		if p.Stats != nil {
+			s = *p.Stats
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%d\t%d\t%d\t%d\n",
+			p.Interface, p.AdminStatus, s.FramesOutTotal, s.FramesInTotal,
+			s.FramesDiscardedTotal, s.FramesInErrorsTotal, s.TLVsDiscardedTotal,
+			s.TLVsUnrecognizedTotal, s.AgeoutsTotal)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	last := "never"
+	if !table.LastChangeTime.IsZero() {
+		last = table.LastChangeTime.Format(time.RFC3339)
+	}
+
+	_, err := fmt.Fprintf(w, "\nNeighbor table: %d inserts, %d deletes, %d drops, %d ageouts, last changed %s\n",
+		table.Inserts, table.Deletes, table.Drops, table.Ageouts, last)
+	return err
+}
+
+// tlvValue returns the value of the first TLV with the input name.
+func tlvValue(f control.Frame, name string) string {
+	for _, t := range f.TLVs {
+		if t.Name == name {
+			return t.Value
+		}
+	}
+
+	return ""
+}
+
+// fatalf prints an error and exits.
+func fatalf(format string, v ...any) {
+	fmt.Fprintf(os.Stderr, "lldpctl: "+format+"\n", v...)
+	os.Exit(1)
+}
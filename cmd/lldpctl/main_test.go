@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/lldp"
+	"github.com/mdlayher/lldp/agent"
+	"github.com/mdlayher/lldp/control"
+	"github.com/mdlayher/lldp/host"
+)
+
+func TestRun(t *testing.T) {
+	var tests = []struct {
+		desc     string
+		args     []string
+		json     bool
+		contains []string
+		err      error
+	}{
+		{
+			desc: "no command",
+			err:  errUsage,
+		},
+		{
+			desc: "unknown command",
+			args: []string{"reboot"},
+			err:  errUsage,
+		},
+		{
+			desc: "too many arguments",
+			args: []string{"neighbors", "eth0", "eth1"},
+			err:  errUsage,
+		},
+		{
+			desc: "bad neighbor index",
+			args: []string{"neighbor", "eth0", "first"},
+			err:  errUsage,
+		},
+		{
+			desc:     "neighbors",
+			args:     []string{"neighbors"},
+			contains: []string{"INTERFACE", "eth0", "sw1", "Ethernet1", "switch1"},
+		},
+		{
+			desc:     "neighbor",
+			args:     []string{"neighbor", "eth0", "0"},
+			contains: []string{"Source:", "de:ad:be:ef:de:ad", "system-name", "switch1"},
+		},
+		{
+			desc:     "neighbor JSON",
+			args:     []string{"neighbor", "eth0", "0"},
+			json:     true,
+			contains: []string{`"chassis_id"`, `"value": "switch1"`},
+		},
+		{
+			desc:     "local",
+			args:     []string{"local"},
+			contains: []string{"Interface: eth0 (both)", "not transmitting"},
+		},
+		{
+			desc:     "counters",
+			args:     []string{"counters", "eth0"},
+			contains: []string{"DISCARDED", "eth0", "Neighbor table: 1 inserts"},
+		},
+		{
+			desc: "set bad status",
+			args: []string{"set", "eth0", "sometimes"},
+		},
+		{
+			desc:     "set JSON",
+			args:     []string{"set", "eth0", "rx"},
+			json:     true,
+			contains: []string{`"admin_status": "rx"`},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		var buf bytes.Buffer
+		err := run(testClient(t), tt.args, tt.json, &buf)
+		if tt.err != nil || (err != nil && tt.contains == nil) {
+			if tt.err != nil && !errors.Is(err, tt.err) {
+				t.Fatalf("unexpected error: %v != %v", tt.err, err)
+			}
+			if err == nil {
+				t.Fatal("expected an error, but none occurred")
+			}
+
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if tt.json && !json.Valid(buf.Bytes()) {
+			t.Fatalf("invalid JSON output:\n%s", buf.String())
+		}
+
+		for _, s := range tt.contains {
+			if !strings.Contains(buf.String(), s) {
+				t.Fatalf("output does not contain %q:\n%s", s, buf.String())
+			}
+		}
+	}
+}
+
+// testClient returns a control.Client for an idle agent with a single
+// neighbor on eth0.
+func testClient(t *testing.T) *control.Client {
+	t.Helper()
+
+	a := agent.New(agent.Config{Ports: map[string]agent.PortConfig{
+		"eth0": {},
+	}}, host.Collector{}, nil, nil)
+
+	sysName := []byte("switch1")
+	a.Neighbors().Update("eth0", &lldp.EthernetFrame{
+		Source: net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad},
+		Frame: &lldp.Frame{
+			ChassisID: &lldp.ChassisID{
+				Subtype: lldp.ChassisIDSubtypeLocallyAssigned,
+				ID:      []byte("sw1"),
+			},
+			PortID: &lldp.PortID{
+				Subtype: lldp.PortIDSubtypeInterfaceName,
+				ID:      []byte("Ethernet1"),
+			},
+			TTL: time.Minute,
+			Optional: []*lldp.TLV{{
+				Type:   lldp.TLVTypeSystemName,
+				Length: uint16(len(sysName)),
+				Value:  sysName,
+			}},
+		},
+	})
+
+	sc, cc := net.Pipe()
+	go func() { _ = control.NewServer(a).ServeConn(sc) }()
+
+	c := control.NewClient(cc)
+	t.Cleanup(func() { _ = c.Close() })
+
+	return c
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/mdlayher/lldp/agent"
+	"github.com/mdlayher/lldp/host"
+	"gopkg.in/yaml.v3"
+)
+
+// A file is the structure of an lldpd YAML configuration file.
+type file struct {
+	// PortID specifies the port ID policy used on every interface: one of
+	// "name", "mac", or "alias".
+	PortID string `yaml:"port_id"`
+
+	// MaxNeighbors specifies the maximum number of neighbors stored.  If
+	// zero, the number of neighbors is unlimited.
+	MaxNeighbors int `yaml:"max_neighbors"`
+
+	// Defaults specifies settings which apply to every interface, unless
+	// overridden in Interfaces.
+	Defaults portFile `yaml:"defaults"`
+
+	// Interfaces specifies the interfaces on which lldpd runs, and any
+	// settings which override Defaults.  If empty, lldpd runs on every
+	// physical Ethernet interface.
+	Interfaces map[string]portFile `yaml:"interfaces"`
+}
+
+// A portFile specifies the settings of a single interface.  Unset fields
+// are inherited from the defaults.
+type portFile struct {
+	AdminStatus    *agent.AdminStatus `yaml:"admin_status"`
+	TxInterval     *time.Duration     `yaml:"tx_interval"`
+	HoldMultiplier *int               `yaml:"hold_multiplier"`
+	TLVs           []string           `yaml:"tlvs"`
+	ChassisID      *string            `yaml:"chassis_id"`
+}
+
+// A config is a parsed lldpd configuration.
+type config struct {
+	PortID host.PortIDPolicy
+	Agent  agent.Config
+}
+
+// loadConfig opens and parses the configuration file at path.
+func loadConfig(path string, interfaces func() ([]string, error)) (*config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return parseConfig(f, interfaces)
+}
+
+// parseConfig parses a YAML configuration from r.  If no interfaces are
+// configured, interfaces is called to list the interfaces on which lldpd
+// runs.
+func parseConfig(r io.Reader, interfaces func() ([]string, error)) (*config, error) {
+	var f file
+	d := yaml.NewDecoder(r)
+	d.KnownFields(true)
+	if err := d.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to decode config: %w", err)
+	}
+
+	var c config
+	switch f.PortID {
+	case "", "name":
+		c.PortID = host.PortIDInterfaceName
+	case "mac":
+		c.PortID = host.PortIDMACAddress
+	case "alias":
+		c.PortID = host.PortIDInterfaceAlias
+	default:
+		return nil, fmt.Errorf("unknown port ID policy %q", f.PortID)
+	}
+
+	if f.MaxNeighbors < 0 {
+		return nil, fmt.Errorf("invalid max_neighbors %d", f.MaxNeighbors)
+	}
+	c.Agent.MaxNeighbors = f.MaxNeighbors
+
+	defaults := portFile{
+		AdminStatus:    ptr(agent.AdminStatusTxRx),
+		TxInterval:     ptr(agent.DefaultTxInterval),
+		HoldMultiplier: ptr(agent.DefaultHoldMultiplier),
+		TLVs:           agent.DefaultTLVs.Strings(),
+	}
+	defaults = f.Defaults.inherit(defaults)
+
+	if len(f.Interfaces) == 0 {
+		ifis, err := interfaces()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list interfaces: %w", err)
+		}
+
+		f.Interfaces = make(map[string]portFile, len(ifis))
+		for _, ifi := range ifis {
+			f.Interfaces[ifi] = portFile{}
+		}
+	}
+
+	c.Agent.Ports = make(map[string]agent.PortConfig, len(f.Interfaces))
+	for name, pf := range f.Interfaces {
+		pc, err := pf.inherit(defaults).portConfig()
+		if err != nil {
+			return nil, fmt.Errorf("interface %q: %w", name, err)
+		}
+
+		c.Agent.Ports[name] = pc
+	}
+
+	return &c, nil
+}
+
+// inherit fills any unset fields of pf from defaults.
+func (pf portFile) inherit(defaults portFile) portFile {
+	if pf.AdminStatus == nil {
+		pf.AdminStatus = defaults.AdminStatus
+	}
+	if pf.TxInterval == nil {
+		pf.TxInterval = defaults.TxInterval
+	}
+	if pf.HoldMultiplier == nil {
+		pf.HoldMultiplier = defaults.HoldMultiplier
+	}
+	if pf.TLVs == nil {
+		pf.TLVs = defaults.TLVs
+	}
+	if pf.ChassisID == nil {
+		pf.ChassisID = defaults.ChassisID
+	}
+
+	return pf
+}
+
+// portConfig validates a fully inherited portFile and converts it into an
+// agent.PortConfig.
+func (pf portFile) portConfig() (agent.PortConfig, error) {
+	if *pf.TxInterval < time.Second {
+		return agent.PortConfig{}, fmt.Errorf("tx_interval must be at least 1s, but got %v", *pf.TxInterval)
+	}
+	if *pf.HoldMultiplier < 1 {
+		return agent.PortConfig{}, fmt.Errorf("hold_multiplier must be at least 1, but got %d", *pf.HoldMultiplier)
+	}
+
+	tlvs, err := agent.ParseTLVs(pf.TLVs)
+	if err != nil {
+		return agent.PortConfig{}, err
+	}
+
+	var policy host.ChassisIDPolicy
+	if pf.ChassisID != nil {
+		policy, err = host.ParseChassisIDPolicy(*pf.ChassisID)
+		if err != nil {
+			return agent.PortConfig{}, err
+		}
+	}
+
+	return agent.PortConfig{
+		AdminStatus:    *pf.AdminStatus,
+		TxInterval:     *pf.TxInterval,
+		HoldMultiplier: *pf.HoldMultiplier,
+		TLVs:           tlvs,
+		ChassisID:      policy,
+	}, nil
+}
+
+// ptr returns a pointer to v.
+func ptr[T any](v T) *T {
+	return &v
+}
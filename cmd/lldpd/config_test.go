@@ -0,0 +1,182 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/lldp/agent"
+	"github.com/mdlayher/lldp/host"
+)
+
+func TestParseConfig(t *testing.T) {
+	var tests = []struct {
+		desc string
+		s    string
+		c    *config
+		ok   bool
+	}{
+		{
+			desc: "empty, all interfaces with defaults",
+			c: &config{
+				Agent: agent.Config{Ports: map[string]agent.PortConfig{
+					"eth0": defaultPort(),
+					"eth1": defaultPort(),
+				}},
+			},
+			ok: true,
+		},
+		{
+			desc: "unknown field",
+			s:    "defaults:\n  tx_intervals: 10s\n",
+		},
+		{
+			desc: "bad admin status",
+			s:    "defaults:\n  admin_status: sometimes\n",
+		},
+		{
+			desc: "bad port ID policy",
+			s:    "port_id: serial\n",
+		},
+		{
+			desc: "bad TLV",
+			s:    "interfaces:\n  eth0:\n    tlvs: [power]\n",
+		},
+		{
+			desc: "bad chassis ID policy",
+			s:    "defaults:\n  chassis_id: serial-number\n",
+		},
+		{
+			desc: "tx interval too short",
+			s:    "interfaces:\n  eth0:\n    tx_interval: 100ms\n",
+		},
+		{
+			desc: "hold multiplier too small",
+			s:    "defaults:\n  hold_multiplier: 0\n",
+		},
+		{
+			desc: "negative max neighbors",
+			s:    "max_neighbors: -1\n",
+		},
+		{
+			desc: "defaults and overrides",
+			s: `
+port_id: mac
+max_neighbors: 16
+defaults:
+  tx_interval: 10s
+  tlvs: [system-name, vlan]
+interfaces:
+  eth0:
+  eth2:
+    admin_status: rx
+    hold_multiplier: 2
+    tlvs: []
+`,
+			c: &config{
+				PortID: host.PortIDMACAddress,
+				Agent: agent.Config{
+					Ports: map[string]agent.PortConfig{
+						"eth0": {
+							TxInterval:     10 * time.Second,
+							HoldMultiplier: agent.DefaultHoldMultiplier,
+							TLVs:           agent.TLVSystemName | agent.TLVVLAN,
+						},
+						"eth2": {
+							AdminStatus:    agent.AdminStatusRx,
+							TxInterval:     10 * time.Second,
+							HoldMultiplier: 2,
+						},
+					},
+					MaxNeighbors: 16,
+				},
+			},
+			ok: true,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		c, err := parseConfig(strings.NewReader(tt.s), func() ([]string, error) {
+			return []string{"eth0", "eth1"}, nil
+		})
+		if err != nil {
+			if tt.ok {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			continue
+		}
+		if !tt.ok {
+			t.Fatal("expected an error, but none occurred")
+		}
+
+		if want, got := tt.c, c; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected config:\n- want: %+v\n-  got: %+v", want, got)
+		}
+	}
+}
+
+func TestParseConfigChassisID(t *testing.T) {
+	c, err := parseConfig(strings.NewReader(`
+defaults:
+  chassis_id: fixed:rack1
+interfaces:
+  eth0:
+  eth1:
+    chassis_id: fixed:rack2
+`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for ifname, want := range map[string]string{
+		"eth0": "rack1",
+		"eth1": "rack2",
+	} {
+		id, err := c.Agent.Ports[ifname].ChassisID(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := string(id.ID); want != got {
+			t.Fatalf("unexpected chassis ID for %q: %q != %q", ifname, want, got)
+		}
+	}
+}
+
+func TestParseConfigInterfacesError(t *testing.T) {
+	errBroken := errors.New("broken")
+
+	_, err := parseConfig(strings.NewReader(""), func() ([]string, error) {
+		return nil, errBroken
+	})
+	if !errors.Is(err, errBroken) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// defaultPort returns the agent.PortConfig used when no settings are
+// configured.
+func defaultPort() agent.PortConfig {
+	return agent.PortConfig{
+		AdminStatus:    agent.AdminStatusTxRx,
+		TxInterval:     agent.DefaultTxInterval,
+		HoldMultiplier: agent.DefaultHoldMultiplier,
+		TLVs:           agent.DefaultTLVs,
+	}
+}
+
+func TestLoadConfigExample(t *testing.T) {
+	c, err := loadConfig("lldpd.example.yaml", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := 3, len(c.Agent.Ports); want != got {
+		t.Fatalf("unexpected number of ports: %d != %d", want, got)
+	}
+}
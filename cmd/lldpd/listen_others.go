@@ -0,0 +1,11 @@
+//go:build !unix
+
+package main
+
+import "net"
+
+// listenUnix listens on a Unix socket at path with the platform's default
+// permissions.
+func listenUnix(path string) (net.Listener, error) {
+	return net.Listen("unix", path)
+}
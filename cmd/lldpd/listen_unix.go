@@ -0,0 +1,19 @@
+//go:build unix
+
+package main
+
+import (
+	"net"
+	"syscall"
+)
+
+// listenUnix listens on a Unix socket at path which only the owner, typically
+// root, may connect to.
+func listenUnix(path string) (net.Listener, error) {
+	// Create the socket with mode 0600 rather than restricting it afterward,
+	// so no other user can connect in the meantime.
+	old := syscall.Umask(0o077)
+	defer syscall.Umask(old)
+
+	return net.Listen("unix", path)
+}
@@ -0,0 +1,149 @@
+// Command lldpd is an LLDP daemon which transmits and receives LLDP frames
+// on a configurable set of interfaces.
+//
+// The configuration file is reloaded on SIGHUP, preserving the neighbors
+// discovered so far.  The running daemon may be queried and controlled using
+// lldpctl over a Unix domain control socket, and the LLDP-V2-MIB may be
+// exposed over SNMP by connecting to an AgentX master agent.  On SIGINT or
+// SIGTERM, a shutdown frame with a TTL of zero is sent on each transmitting
+// interface before lldpd exits.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"io/fs"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/mdlayher/lldp/agent"
+	"github.com/mdlayher/lldp/agentx"
+	"github.com/mdlayher/lldp/control"
+	"github.com/mdlayher/lldp/host"
+)
+
+func main() {
+	var (
+		configFlag = flag.String("c", "/etc/lldpd.yaml", "path to YAML configuration file")
+		socketFlag = flag.String("s", "/run/lldpd.sock", "path to control socket; empty to disable")
+		agentxFlag = flag.String("x", "", "path to AgentX master agent socket, such as /var/agentx/master; empty to disable")
+	)
+
+	flag.Parse()
+
+	ll := log.New(os.Stderr, "", log.LstdFlags)
+
+	base := host.Collector{
+		Links:   host.Ethtool(),
+		Netlink: host.RTNetlink(),
+	}
+
+	cfg, err := loadConfig(*configFlag, base.Interfaces)
+	if err != nil {
+		ll.Fatalf("failed to load config: %v", err)
+	}
+
+	c := base
+	c.PortID = cfg.PortID
+	a := agent.New(cfg.Agent, c, agent.Listen, ll)
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			cfg, err := loadConfig(*configFlag, base.Interfaces)
+			if err != nil {
+				ll.Printf("failed to reload config, keeping current config: %v", err)
+				continue
+			}
+
+			if cfg.PortID != c.PortID {
+				ll.Printf("port_id changes require a restart, ignoring")
+			}
+
+			if err := a.Reload(cfg.Agent); err != nil {
+				ll.Printf("failed to apply config: %v", err)
+				continue
+			}
+
+			ll.Printf("reloaded config from %q", *configFlag)
+		}
+	}()
+
+	if *socketFlag != "" {
+		l, err := listenControl(*socketFlag)
+		if err != nil {
+			ll.Fatalf("failed to listen on control socket: %v", err)
+		}
+		defer l.Close()
+
+		go func() {
+			if err := control.NewServer(a).Serve(l); err != nil {
+				ll.Printf("control socket failed: %v", err)
+			}
+		}()
+	}
+
+	// agentxDone is closed once the AgentX session, if any, has sent its
+	// Close PDU and finished.
+	agentxDone := make(chan struct{})
+	if *agentxFlag != "" {
+		go func() {
+			defer close(agentxDone)
+			serveAgentX(ctx, *agentxFlag, a, ll)
+		}()
+	} else {
+		close(agentxDone)
+	}
+
+	ll.Printf("starting lldpd on %d interfaces", len(cfg.Agent.Ports))
+
+	if err := a.Run(ctx); err != nil {
+		ll.Fatalf("failed to run: %v", err)
+	}
+
+	<-agentxDone
+}
+
+// listenControl listens on the control socket at path, removing any stale
+// socket left behind by a previous run.
+func listenControl(path string) (net.Listener, error) {
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	return listenUnix(path)
+}
+
+// serveAgentX connects to the AgentX master agent at path and serves the
+// LLDP-V2-MIB until ctx is canceled, reconnecting whenever the session fails.
+func serveAgentX(ctx context.Context, path string, a *agent.Agent, ll *log.Logger) {
+	const retry = 10 * time.Second
+
+	sa := &agentx.Subagent{Source: a}
+	for {
+		c, err := net.Dial("unix", path)
+		if err == nil {
+			err = sa.Serve(ctx, c)
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		ll.Printf("AgentX session failed, retrying in %s: %v", retry, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retry):
+		}
+	}
+}
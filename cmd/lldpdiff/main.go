@@ -0,0 +1,95 @@
+// Command lldpdiff reports the changes between two topology snapshots, such
+// as those taken before and after a maintenance window.
+//
+// Snapshots are topology graphs encoded as JSON by
+// (*topology.Graph).WriteJSON.  Like diff(1), lldpdiff exits with status 0
+// if the snapshots are the same, 1 if they differ, and 2 on error.
+//
+// Usage:
+//
+//	lldpdiff [flags] BEFORE AFTER
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/mdlayher/lldp/topology"
+)
+
+func main() {
+	jsonFlag := flag.Bool("json", false, "produce JSON output")
+
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), `usage:
+  lldpdiff [flags] BEFORE AFTER
+
+flags:
+`)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	changed, err := run(flag.Arg(0), flag.Arg(1), *jsonFlag, os.Stdout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "lldpdiff: %v\n", err)
+		os.Exit(2)
+	}
+	if changed {
+		os.Exit(1)
+	}
+}
+
+// run compares the snapshots in files before and after, writing the changes
+// to w as text or as JSON, and reports whether there were any changes.
+func run(before, after string, asJSON bool, w io.Writer) (bool, error) {
+	bg, err := readGraph(before)
+	if err != nil {
+		return false, err
+	}
+	ag, err := readGraph(after)
+	if err != nil {
+		return false, err
+	}
+
+	d := topology.Compare(bg, ag)
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return !d.Empty(), enc.Encode(d)
+	}
+
+	return !d.Empty(), d.WriteText(w)
+}
+
+// readGraph reads a topology snapshot from a file.
+func readGraph(file string) (*topology.Graph, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var g topology.Graph
+	if err := json.NewDecoder(f).Decode(&g); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot %q: %w", file, err)
+	}
+	if g.Nodes == nil && g.Links == nil {
+		return nil, fmt.Errorf("snapshot %q: %w", file, errEmpty)
+	}
+
+	return &g, nil
+}
+
+// errEmpty indicates that a snapshot contains no topology, which usually
+// means the file is not a snapshot at all.
+var errEmpty = errors.New("not a topology snapshot")
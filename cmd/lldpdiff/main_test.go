@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, s string) string {
+		t.Helper()
+
+		file := filepath.Join(dir, name)
+		if err := os.WriteFile(file, []byte(s), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		return file
+	}
+
+	var (
+		before = write("before.json", `{
+  "nodes": [
+    {"id": "local:host1", "chassis_id": {"subtype": "local", "value": "host1"}, "system_name": "host1", "reported": true},
+    {"id": "local:sw1", "chassis_id": {"subtype": "local", "value": "sw1"}, "system_name": "sw1", "reported": true}
+  ],
+  "links": [
+    {
+      "a": {"node": "local:host1", "port": {"subtype": "interface-name", "value": "eth0"}},
+      "b": {"node": "local:sw1", "port": {"subtype": "interface-name", "value": "Ethernet1"}},
+      "state": "bidirectional",
+      "ambiguous": false
+    }
+  ]
+}`)
+		after = write("after.json", `{
+  "nodes": [
+    {"id": "local:host1", "chassis_id": {"subtype": "local", "value": "host1"}, "system_name": "host1", "reported": true},
+    {"id": "local:sw1", "chassis_id": {"subtype": "local", "value": "sw1"}, "system_name": "sw1-new", "reported": true}
+  ],
+  "links": [
+    {
+      "a": {"node": "local:host1", "port": {"subtype": "interface-name", "value": "eth0"}},
+      "b": {"node": "local:sw1", "port": {"subtype": "interface-name", "value": "Ethernet2"}},
+      "state": "bidirectional",
+      "ambiguous": false
+    }
+  ]
+}`)
+		empty = write("empty.json", `{}`)
+	)
+
+	var tests = []struct {
+		desc          string
+		before, after string
+		json          bool
+		changed       bool
+		out           string
+		err           error
+	}{
+		{
+			desc:   "no changes",
+			before: before,
+			after:  before,
+		},
+		{
+			desc:    "changes",
+			before:  before,
+			after:   after,
+			changed: true,
+			out: `~ device local:sw1 system_name: "sw1" -> "sw1-new"
+- link local:host1/eth0 -- local:sw1/Ethernet1 (bidirectional)
++ link local:host1/eth0 -- local:sw1/Ethernet2 (bidirectional)
+~ port local:host1/eth0 neighbor: local:sw1/Ethernet1 -> local:sw1/Ethernet2
+`,
+		},
+		{
+			desc:    "changes JSON",
+			before:  before,
+			after:   after,
+			json:    true,
+			changed: true,
+		},
+		{
+			desc:   "not a snapshot",
+			before: empty,
+			after:  after,
+			err:    errEmpty,
+		},
+		{
+			desc:   "missing file",
+			before: filepath.Join(dir, "missing.json"),
+			after:  after,
+			err:    os.ErrNotExist,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		var buf bytes.Buffer
+		changed, err := run(tt.before, tt.after, tt.json, &buf)
+		if tt.err != nil {
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("unexpected error: %v != %v", tt.err, err)
+			}
+
+			continue
+		}
+		if err != nil {
+			t.Fatalf("failed to run: %v", err)
+		}
+
+		if want, got := tt.changed, changed; want != got {
+			t.Fatalf("unexpected changed: %v != %v", want, got)
+		}
+
+		switch {
+		case tt.json:
+			if !strings.Contains(buf.String(), `"changed_ports"`) {
+				t.Fatalf("unexpected JSON output:\n%s", buf.String())
+			}
+		default:
+			if want, got := tt.out, buf.String(); want != got {
+				t.Fatalf("unexpected output:\n- want: %s\n-  got: %s", want, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,171 @@
+// Command lldpverify verifies the neighbors discovered by lldpd against a
+// YAML cabling plan.
+//
+// Neighbors are queried from a running lldpd over its control socket, or
+// read from a file written by "lldpctl -json neighbors".  lldpverify exits
+// with status 0 if the cabling matches the plan, 1 if there are findings,
+// and 2 on error.
+//
+// Usage:
+//
+//	lldpverify [flags] PLAN
+//
+// A plan specifies the neighbor expected on each local interface:
+//
+//	links:
+//	  eth0:
+//	    system_name: sw1
+//	    port: Ethernet1
+//	  eth1:
+//	    chassis_id: de:ad:be:ef:de:ad
+//	    port: Ethernet1
+//	aliases:
+//	  eth1:
+//	    - enp1s0
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/mdlayher/lldp/cabling"
+	"github.com/mdlayher/lldp/control"
+)
+
+func main() {
+	var (
+		socketFlag    = flag.String("s", "/run/lldpd.sock", "path to lldpd control socket")
+		neighborsFlag = flag.String("n", "", "read neighbors from a JSON file written by lldpctl, instead of lldpd")
+		jsonFlag      = flag.Bool("json", false, "produce JSON output")
+	)
+
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), `usage:
+  lldpverify [flags] PLAN
+
+flags:
+`)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	plan, err := os.Open(flag.Arg(0))
+	if err != nil {
+		fatalf("%v", err)
+	}
+	defer plan.Close()
+
+	var nn []control.Neighbor
+	if *neighborsFlag != "" {
+		nn, err = readNeighbors(*neighborsFlag)
+	} else {
+		nn, err = queryNeighbors(*socketFlag)
+	}
+	if err != nil {
+		fatalf("%v", err)
+	}
+
+	failed, err := run(plan, nn, *jsonFlag, os.Stdout)
+	if err != nil {
+		fatalf("%v", err)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// run verifies the neighbors nn against the plan read from r, writing the
+// findings to w as text or as JSON, and reports whether there were any
+// findings.
+func run(r io.Reader, nn []control.Neighbor, asJSON bool, w io.Writer) (bool, error) {
+	p, err := cabling.ParsePlan(r)
+	if err != nil {
+		return false, err
+	}
+
+	oo := make([]cabling.Observation, 0, len(nn))
+	for _, n := range nn {
+		oo = append(oo, observation(n))
+	}
+
+	ff := p.Verify(oo)
+	if asJSON {
+		if ff == nil {
+			ff = []cabling.Finding{}
+		}
+
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return len(ff) > 0, enc.Encode(ff)
+	}
+
+	for _, f := range ff {
+		if _, err := fmt.Fprintln(w, f); err != nil {
+			return false, err
+		}
+	}
+
+	return len(ff) > 0, nil
+}
+
+// observation converts a neighbor reported by lldpd into an Observation,
+// normalized in the same way as by cabling.NewObservation.
+func observation(n control.Neighbor) cabling.Observation {
+	o := cabling.Observation{
+		Port:      n.Interface,
+		ChassisID: n.Frame.ChassisID.Value,
+		PortID:    n.Frame.PortID.Value,
+	}
+
+	for _, t := range n.Frame.TLVs {
+		switch t.Type {
+		case "system-name":
+			o.SystemName = t.Value
+		case "port-description":
+			o.PortDescription = t.Value
+		}
+	}
+
+	return o.Normalize()
+}
+
+// queryNeighbors queries the neighbors on every interface from lldpd.
+func queryNeighbors(socket string) ([]control.Neighbor, error) {
+	c, err := control.Dial(socket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to lldpd: %w", err)
+	}
+	defer c.Close()
+
+	return c.Neighbors("")
+}
+
+// readNeighbors reads neighbors from a JSON file.
+func readNeighbors(file string) ([]control.Neighbor, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var nn []control.Neighbor
+	if err := json.NewDecoder(f).Decode(&nn); err != nil {
+		return nil, fmt.Errorf("failed to decode neighbors %q: %w", file, err)
+	}
+
+	return nn, nil
+}
+
+// fatalf prints an error and exits.
+func fatalf(format string, v ...any) {
+	fmt.Fprintf(os.Stderr, "lldpverify: "+format+"\n", v...)
+	os.Exit(2)
+}
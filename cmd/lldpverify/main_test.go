@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/mdlayher/lldp/control"
+)
+
+func TestRun(t *testing.T) {
+	const plan = `links:
+  eth0:
+    system_name: sw1
+    port: Ethernet1
+  eth1:
+    system_name: sw2
+    port: Ethernet1
+`
+
+	nn := []control.Neighbor{{
+		Interface: "eth0",
+		Frame: control.Frame{
+			ChassisID: control.ID{Subtype: "local", Value: "sw1"},
+			PortID:    control.ID{Subtype: "interface-name", Value: "Ethernet1"},
+			TLVs: []control.TLV{
+				{Type: "system-name", Name: "system-name", Value: "sw1 "},
+			},
+		},
+	}}
+
+	var tests = []struct {
+		desc   string
+		plan   string
+		json   bool
+		failed bool
+		out    string
+		ok     bool
+	}{
+		{
+			desc:   "text",
+			plan:   plan,
+			failed: true,
+			out:    "eth1: missing: expected sw2 port Ethernet1\n",
+			ok:     true,
+		},
+		{
+			desc:   "JSON",
+			plan:   plan,
+			json:   true,
+			failed: true,
+			out:    `"type": "missing"`,
+			ok:     true,
+		},
+		{
+			desc: "no findings JSON",
+			plan: "links:\n  eth0:\n    system_name: sw1\n    port: Ethernet1\n",
+			json: true,
+			out:  "[]\n",
+			ok:   true,
+		},
+		{
+			desc: "invalid plan",
+			plan: "links:\n  eth0:\n    port: Ethernet1\n",
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		var buf bytes.Buffer
+		failed, err := run(strings.NewReader(tt.plan), nn, tt.json, &buf)
+		if !tt.ok {
+			if err == nil {
+				t.Fatal("expected an error, but none occurred")
+			}
+
+			continue
+		}
+		if err != nil {
+			t.Fatalf("failed to run: %v", err)
+		}
+
+		if want, got := tt.failed, failed; want != got {
+			t.Fatalf("unexpected failed: %v != %v", want, got)
+		}
+		if !strings.Contains(buf.String(), tt.out) {
+			t.Fatalf("output does not contain %q:\n%s", tt.out, buf.String())
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package control
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net"
+	"sync"
+
+	"github.com/mdlayher/lldp/agent"
+)
+
+// A Client is a client of the control protocol.  It is safe for concurrent
+// use, although requests are issued one at a time.
+type Client struct {
+	mu  sync.Mutex
+	c   net.Conn
+	dec *json.Decoder
+	enc *json.Encoder
+}
+
+// Dial dials the control socket of an agent at the input Unix domain
+// socket path.
+func Dial(path string) (*Client, error) {
+	c, err := net.Dial("unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewClient(c), nil
+}
+
+// NewClient creates a Client which issues requests over an established
+// connection.
+func NewClient(c net.Conn) *Client {
+	return &Client{
+		c:   c,
+		dec: json.NewDecoder(bufio.NewReader(c)),
+		enc: json.NewEncoder(c),
+	}
+}
+
+// Close closes the Client's connection.
+func (c *Client) Close() error {
+	return c.c.Close()
+}
+
+// Neighbors lists the neighbors discovered on ifname, or on all interfaces
+// if ifname is empty.
+func (c *Client) Neighbors(ifname string) ([]Neighbor, error) {
+	res, err := c.Do(Request{
+		Command:   CommandNeighbors,
+		Interface: ifname,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Neighbors, nil
+}
+
+// Neighbor shows the neighbor with the input index on ifname.
+func (c *Client) Neighbor(ifname string, index int) (*Neighbor, error) {
+	res, err := c.Do(Request{
+		Command:   CommandNeighbor,
+		Interface: ifname,
+		Index:     index,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Neighbors) != 1 {
+		return nil, errors.New("control: malformed neighbor response")
+	}
+
+	return &res.Neighbors[0], nil
+}
+
+// Local shows the frames most recently transmitted on ifname, or on all
+// interfaces if ifname is empty.
+func (c *Client) Local(ifname string) ([]Port, error) {
+	return c.ports(CommandLocal, ifname)
+}
+
+// Counters shows the statistics of ifname, or of all interfaces if ifname
+// is empty, along with the statistics of the neighbor table.
+func (c *Client) Counters(ifname string) ([]Port, *agent.TableStats, error) {
+	res, err := c.Do(Request{
+		Command:   CommandCounters,
+		Interface: ifname,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if res.Table == nil {
+		return nil, nil, errors.New("control: malformed counters response")
+	}
+
+	return res.Ports, res.Table, nil
+}
+
+// SetAdminStatus sets the admin status of ifname.
+func (c *Client) SetAdminStatus(ifname string, status agent.AdminStatus) error {
+	_, err := c.Do(Request{
+		Command:     CommandSetAdminStatus,
+		Interface:   ifname,
+		AdminStatus: &status,
+	})
+	return err
+}
+
+func (c *Client) ports(cmd Command, ifname string) ([]Port, error) {
+	res, err := c.Do(Request{
+		Command:   cmd,
+		Interface: ifname,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Ports, nil
+}
+
+// Do issues a Request and returns its Response.  If the Response carries an
+// error, it is returned as an error.
+func (c *Client) Do(req Request) (*Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.enc.Encode(req); err != nil {
+		return nil, err
+	}
+
+	var res Response
+	if err := c.dec.Decode(&res); err != nil {
+		return nil, err
+	}
+	if res.Error != "" {
+		return nil, &Error{Message: res.Error}
+	}
+
+	return &res, nil
+}
+
+// An Error is an error reported by a Server.
+type Error struct {
+	Message string
+}
+
+// Error implements error.
+func (e *Error) Error() string {
+	return "control: " + e.Message
+}
@@ -0,0 +1,90 @@
+// Package control implements a protocol for querying and controlling
+// a running LLDP agent over a stream connection, such as a Unix domain
+// socket.
+//
+// Each request and response is a single JSON object terminated by a newline.
+// A connection may carry any number of requests, each of which receives
+// exactly one response.
+package control
+
+import (
+	"time"
+
+	"github.com/mdlayher/lldp"
+	"github.com/mdlayher/lldp/agent"
+)
+
+// An Agent is the LLDP agent served by a Server.  It is implemented by
+// *agent.Agent.
+type Agent interface {
+	Neighbors() *agent.NeighborTable
+	LocalFrames() map[string]*lldp.Frame
+	Stats() map[string]agent.PortStats
+	AdminStatus() map[string]agent.AdminStatus
+	SetAdminStatus(ifname string, status agent.AdminStatus) error
+}
+
+var _ Agent = &agent.Agent{}
+
+// A Command is an operation requested of a Server.
+type Command string
+
+// List of valid Command values.
+const (
+	// CommandNeighbors lists the neighbors discovered on all interfaces,
+	// or on Request.Interface if set.
+	CommandNeighbors Command = "neighbors"
+
+	// CommandNeighbor shows a single neighbor, identified by
+	// Request.Interface and Request.Index.
+	CommandNeighbor Command = "neighbor"
+
+	// CommandLocal shows the frames most recently transmitted on all
+	// interfaces, or on Request.Interface if set.
+	CommandLocal Command = "local"
+
+	// CommandCounters shows the statistics of all interfaces, or of
+	// Request.Interface if set.
+	CommandCounters Command = "counters"
+
+	// CommandSetAdminStatus sets the admin status of Request.Interface to
+	// Request.AdminStatus.
+	CommandSetAdminStatus Command = "set-admin-status"
+)
+
+// A Request is a request sent from a Client to a Server.
+type Request struct {
+	Command     Command            `json:"command"`
+	Interface   string             `json:"interface,omitempty"`
+	Index       int                `json:"index,omitempty"`
+	AdminStatus *agent.AdminStatus `json:"admin_status,omitempty"`
+}
+
+// A Response is the response sent from a Server to a Client for a single
+// Request.  Only the fields relevant to the request's Command are set.
+type Response struct {
+	Error     string     `json:"error,omitempty"`
+	Neighbors []Neighbor `json:"neighbors,omitempty"`
+	Ports     []Port     `json:"ports,omitempty"`
+
+	// Table is set for CommandCounters.
+	Table *agent.TableStats `json:"table,omitempty"`
+}
+
+// A Neighbor is a neighbor discovered on a local interface.
+type Neighbor struct {
+	Interface string    `json:"interface"`
+	Index     int       `json:"index"`
+	Source    string    `json:"source"`
+	Updated   time.Time `json:"updated"`
+	Expires   time.Time `json:"expires"`
+	Frame     Frame     `json:"frame"`
+}
+
+// A Port is the local state of an interface.
+type Port struct {
+	Interface   string            `json:"interface"`
+	AdminStatus agent.AdminStatus `json:"admin_status"`
+	Frame       *Frame            `json:"frame,omitempty"`
+	Stats       *agent.PortStats  `json:"stats,omitempty"`
+}
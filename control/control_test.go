@@ -0,0 +1,282 @@
+package control
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/lldp"
+	"github.com/mdlayher/lldp/agent"
+)
+
+func TestClientServer(t *testing.T) {
+	a := newTestAgent()
+	c := testClient(t, a)
+
+	nn, err := c.Neighbors("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, n := range nn {
+		got = append(got, n.Interface+"/"+string(rune('0'+n.Index))+"/"+n.Frame.ChassisID.Value)
+	}
+
+	if want := []string{"eth0/0/sw1", "eth0/1/sw2", "eth1/0/sw3"}; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected neighbors:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	nn, err = c.Neighbors("eth1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nn) != 1 || nn[0].Index != 0 || nn[0].Frame.ChassisID.Value != "sw3" {
+		t.Fatalf("unexpected eth1 neighbors: %+v", nn)
+	}
+
+	n, err := c.Neighbor("eth0", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "sw2", n.Frame.ChassisID.Value; want != got {
+		t.Fatalf("unexpected neighbor chassis ID: %q != %q", want, got)
+	}
+	if want, got := "de:ad:be:ef:de:ad", n.Source; want != got {
+		t.Fatalf("unexpected neighbor source: %q != %q", want, got)
+	}
+
+	var cerr *Error
+	if _, err := c.Neighbor("eth0", 2); !errors.As(err, &cerr) {
+		t.Fatalf("expected control error for unknown neighbor, but got: %v", err)
+	}
+
+	local, err := c.Local("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(local) != 2 || local[0].Frame == nil || local[1].Frame != nil || local[0].Stats != nil {
+		t.Fatalf("unexpected local ports: %+v", local)
+	}
+	if want, got := "host1", local[0].Frame.ChassisID.Value; want != got {
+		t.Fatalf("unexpected local chassis ID: %q != %q", want, got)
+	}
+
+	counters, table, err := c.Counters("eth0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Port{{
+		Interface:   "eth0",
+		AdminStatus: agent.AdminStatusTxRx,
+		Stats:       &agent.PortStats{FramesOutTotal: 3, FramesInTotal: 2},
+	}}
+	if !reflect.DeepEqual(want, counters) {
+		t.Fatalf("unexpected counters:\n- want: %+v\n-  got: %+v", want, counters)
+	}
+	if want, got := uint64(3), table.Inserts; want != got {
+		t.Fatalf("unexpected neighbor table inserts: %d != %d", want, got)
+	}
+
+	if err := c.SetAdminStatus("eth1", agent.AdminStatusRx); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := agent.AdminStatusRx, a.status["eth1"]; want != got {
+		t.Fatalf("unexpected admin status: %v != %v", want, got)
+	}
+
+	if err := c.SetAdminStatus("eth9", agent.AdminStatusRx); !errors.As(err, &cerr) {
+		t.Fatalf("expected control error for unknown interface, but got: %v", err)
+	}
+
+	if _, err := c.Do(Request{Command: "reboot"}); !errors.As(err, &cerr) {
+		t.Fatalf("expected control error for unknown command, but got: %v", err)
+	}
+}
+
+func TestServerServeUnix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lldpd.sock")
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skipf("skipping, failed to listen on Unix socket: %v", err)
+	}
+
+	done := make(chan error)
+	go func() { done <- NewServer(newTestAgent()).Serve(l) }()
+
+	c, err := Dial(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if _, err := c.Neighbors(""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Closing the listener also closes open connections.
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Neighbors(""); err == nil {
+		t.Fatal("expected an error after server shutdown")
+	}
+}
+
+func TestServerServeConnInvalidRequests(t *testing.T) {
+	sc, cc := net.Pipe()
+	defer cc.Close()
+
+	done := make(chan error)
+	go func() { done <- NewServer(newTestAgent()).ServeConn(sc) }()
+
+	var (
+		enc = json.NewEncoder(cc)
+		dec = json.NewDecoder(cc)
+	)
+
+	var tests = []struct {
+		desc string
+		req  string
+		ok   bool
+	}{
+		{
+			desc: "wrong type",
+			req:  `{"command": 1}`,
+		},
+		{
+			desc: "unknown admin status",
+			req:  `{"command": "set-admin-status", "interface": "eth0", "admin_status": "sideways"}`,
+		},
+		{
+			desc: "valid after invalid",
+			req:  `{"command": "neighbors", "interface": "eth1"}`,
+			ok:   true,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		if err := enc.Encode(json.RawMessage(tt.req)); err != nil {
+			t.Fatal(err)
+		}
+
+		var res Response
+		if err := dec.Decode(&res); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+
+		if want, got := tt.ok, res.Error == ""; want != got {
+			t.Fatalf("unexpected response error: %q", res.Error)
+		}
+	}
+
+	// Malformed JSON cannot be recovered from, so the connection is closed
+	// after the error is sent.
+	if _, err := cc.Write([]byte("{]\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	var res Response
+	if err := dec.Decode(&res); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if want, got := "malformed request", res.Error; want != got {
+		t.Fatalf("unexpected response error: %q != %q", want, got)
+	}
+	if err := <-done; err == nil {
+		t.Fatal("expected an error after malformed request")
+	}
+}
+
+// testClient serves an Agent over an in-memory connection and returns
+// a Client for it.
+func testClient(t *testing.T, a Agent) *Client {
+	t.Helper()
+
+	sc, cc := net.Pipe()
+	go func() { _ = NewServer(a).ServeConn(sc) }()
+
+	c := NewClient(cc)
+	t.Cleanup(func() { _ = c.Close() })
+
+	return c
+}
+
+// A testAgent is an Agent with fixed state.
+type testAgent struct {
+	nt     *agent.NeighborTable
+	status map[string]agent.AdminStatus
+}
+
+var _ Agent = &testAgent{}
+
+func newTestAgent() *testAgent {
+	nt := agent.NewNeighborTable()
+	for _, n := range []struct{ ifname, chassis string }{
+		{"eth1", "sw3"},
+		{"eth0", "sw2"},
+		{"eth0", "sw1"},
+	} {
+		nt.Update(n.ifname, &lldp.EthernetFrame{
+			Source: net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad},
+			Frame:  testFrame(n.chassis),
+		})
+	}
+
+	return &testAgent{
+		nt: nt,
+		status: map[string]agent.AdminStatus{
+			"eth0": agent.AdminStatusTxRx,
+			"eth1": agent.AdminStatusTx,
+		},
+	}
+}
+
+func (a *testAgent) Neighbors() *agent.NeighborTable { return a.nt }
+
+func (a *testAgent) LocalFrames() map[string]*lldp.Frame {
+	return map[string]*lldp.Frame{"eth0": testFrame("host1")}
+}
+
+func (a *testAgent) Stats() map[string]agent.PortStats {
+	return map[string]agent.PortStats{
+		"eth0": {FramesOutTotal: 3, FramesInTotal: 2},
+		"eth1": {FramesOutTotal: 1},
+	}
+}
+
+func (a *testAgent) AdminStatus() map[string]agent.AdminStatus { return a.status }
+
+func (a *testAgent) SetAdminStatus(ifname string, status agent.AdminStatus) error {
+	if _, ok := a.status[ifname]; !ok {
+		return errors.New("not configured")
+	}
+
+	a.status[ifname] = status
+	return nil
+}
+
+// testFrame returns a Frame with the input locally assigned chassis ID.
+func testFrame(chassis string) *lldp.Frame {
+	return &lldp.Frame{
+		ChassisID: &lldp.ChassisID{
+			Subtype: lldp.ChassisIDSubtypeLocallyAssigned,
+			ID:      []byte(chassis),
+		},
+		PortID: &lldp.PortID{
+			Subtype: lldp.PortIDSubtypeInterfaceName,
+			ID:      []byte("1"),
+		},
+		TTL: time.Minute,
+	}
+}
@@ -0,0 +1,213 @@
+package control
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+
+	"github.com/mdlayher/lldp"
+	"github.com/mdlayher/lldp/internal/format"
+)
+
+// A Frame is a decoded representation of an lldp.Frame, suitable for
+// display and for encoding as JSON.
+type Frame struct {
+	ChassisID ID    `json:"chassis_id"`
+	PortID    ID    `json:"port_id"`
+	TTL       int   `json:"ttl"`
+	TLVs      []TLV `json:"tlvs"`
+}
+
+// An ID is a decoded chassis ID or port ID.
+type ID struct {
+	// Subtype specifies the name of the ID subtype, such as "mac-address".
+	Subtype string `json:"subtype"`
+
+	// Value specifies the ID, formatted according to its subtype.
+	Value string `json:"value"`
+}
+
+// String returns the value of an ID.
+func (id ID) String() string {
+	return id.Value
+}
+
+// A TLV is a decoded optional TLV.
+type TLV struct {
+	// Type specifies the name of the TLV type, such as "system-name".
+	Type string `json:"type"`
+
+	// OUI and Subtype identify an organizationally specific TLV.
+	OUI     string `json:"oui,omitempty"`
+	Subtype *uint8 `json:"subtype,omitempty"`
+
+	// Name specifies a human readable name for the TLV.
+	Name string `json:"name"`
+
+	// Value specifies a human readable decoding of the TLV value.
+	Value string `json:"value"`
+
+	// Raw specifies the TLV value in hexadecimal.
+	Raw string `json:"raw"`
+}
+
+// NewFrame decodes an lldp.Frame into a Frame.
+func NewFrame(f *lldp.Frame) Frame {
+	out := Frame{
+		ChassisID: NewChassisID(f.ChassisID),
+		PortID:    NewPortID(f.PortID),
+		TTL:       int(f.TTL.Seconds()),
+		TLVs:      make([]TLV, 0, len(f.Optional)),
+	}
+
+	for _, t := range f.Optional {
+		out.TLVs = append(out.TLVs, newTLV(t))
+	}
+
+	return out
+}
+
+// NewChassisID decodes a ChassisID.  A nil ChassisID decodes to a zero ID.
+func NewChassisID(c *lldp.ChassisID) ID {
+	subtype, value := format.ChassisID(c)
+	return ID{Subtype: subtype, Value: value}
+}
+
+// NewPortID decodes a PortID.  A nil PortID decodes to a zero ID.
+func NewPortID(p *lldp.PortID) ID {
+	subtype, value := format.PortID(p)
+	return ID{Subtype: subtype, Value: value}
+}
+
+// Names of basic TLV types.
+var tlvTypes = map[lldp.TLVType]string{
+	lldp.TLVTypePortDescription:      "port-description",
+	lldp.TLVTypeSystemName:           "system-name",
+	lldp.TLVTypeSystemDescription:    "system-description",
+	lldp.TLVTypeSystemCapabilities:   "system-capabilities",
+	lldp.TLVTypeManagementAddress:    "management-address",
+	lldp.TLVTypeOrganizationSpecific: "organization-specific",
+}
+
+// newTLV decodes an optional TLV.
+func newTLV(t *lldp.TLV) TLV {
+	out := TLV{
+		Type: tlvTypes[t.Type],
+		Raw:  hex.EncodeToString(t.Value),
+	}
+	if out.Type == "" {
+		out.Type = fmt.Sprintf("unknown-%d", t.Type)
+	}
+	out.Name = out.Type
+
+	switch t.Type {
+	case lldp.TLVTypePortDescription, lldp.TLVTypeSystemName, lldp.TLVTypeSystemDescription:
+		out.Value = format.Text(t.Value)
+	case lldp.TLVTypeSystemCapabilities:
+		sc := new(lldp.SystemCapabilities)
+		if err := sc.UnmarshalBinary(t.Value); err == nil {
+			out.Value = fmt.Sprintf("system: %s; enabled: %s",
+				Capabilities(sc.System), Capabilities(sc.Enabled))
+		}
+	case lldp.TLVTypeManagementAddress:
+		m := new(lldp.ManagementAddress)
+		if err := m.UnmarshalBinary(t.Value); err == nil {
+			out.Value = managementAddress(m)
+		}
+	case lldp.TLVTypeOrganizationSpecific:
+		o := new(lldp.OrganizationSpecific)
+		if err := o.UnmarshalBinary(t.Value); err == nil {
+			subtype := o.Subtype
+			out.OUI = net.HardwareAddr(o.OUI[:]).String()
+			out.Subtype = &subtype
+			out.Name, out.Value = orgSpecific(o, t.Value)
+		}
+	}
+
+	return out
+}
+
+// Capabilities formats a Capability bit mask as a comma-separated list of
+// capability names, such as "bridge,router", or "none" if no bits are set.
+func Capabilities(c lldp.Capability) string {
+	return format.Capabilities(c)
+}
+
+// managementAddress formats a ManagementAddress.
+func managementAddress(m *lldp.ManagementAddress) string {
+	var addr string
+	switch m.Family {
+	case lldp.AddressFamilyIPv4, lldp.AddressFamilyIPv6:
+		addr = net.IP(m.Address).String()
+	case lldp.AddressFamily802:
+		addr = net.HardwareAddr(m.Address).String()
+	default:
+		addr = hex.EncodeToString(m.Address)
+	}
+
+	switch m.InterfaceNumbering {
+	case lldp.InterfaceNumberingIfIndex:
+		return fmt.Sprintf("%s (ifindex %d)", addr, m.InterfaceNumber)
+	case lldp.InterfaceNumberingSystemPortNumber:
+		return fmt.Sprintf("%s (port %d)", addr, m.InterfaceNumber)
+	default:
+		return addr
+	}
+}
+
+// orgSpecific returns the name and decoded value of a known
+// organizationally specific TLV.
+func orgSpecific(o *lldp.OrganizationSpecific, b []byte) (string, string) {
+	switch {
+	case o.OUI == lldp.OUIIEEE8021 && o.Subtype == lldp.IEEE8021SubtypePortVLANID:
+		p := new(lldp.PortVLANID)
+		if err := p.UnmarshalBinary(b); err == nil {
+			return "port-vlan-id", fmt.Sprintf("%d", p.ID)
+		}
+	case o.OUI == lldp.OUIIEEE8021 && o.Subtype == lldp.IEEE8021SubtypePortProtocolVLANID:
+		p := new(lldp.PortProtocolVLANID)
+		if err := p.UnmarshalBinary(b); err == nil {
+			return "port-protocol-vlan-id", fmt.Sprintf("%d (supported: %t, enabled: %t)",
+				p.ID, p.Supported, p.Enabled)
+		}
+	case o.OUI == lldp.OUIIEEE8021 && o.Subtype == lldp.IEEE8021SubtypeVLANName:
+		v := new(lldp.VLANName)
+		if err := v.UnmarshalBinary(b); err == nil {
+			return "vlan-name", fmt.Sprintf("%d: %s", v.ID, format.Text([]byte(v.Name)))
+		}
+	case o.OUI == lldp.OUIIEEE8021 && o.Subtype == lldp.IEEE8021SubtypePortExtension:
+		p := new(lldp.PortExtension)
+		if err := p.UnmarshalBinary(b); err == nil {
+			return "port-extension", fmt.Sprintf("E-CID %d, CSP %s (upstream: %t, cascade: %t)",
+				p.ECID, p.CSPAddress, p.UpstreamPort, p.CascadePort)
+		}
+	case o.OUI == lldp.OUIIEEE8021 && o.Subtype == lldp.IEEE8021SubtypeLinkAggregation,
+		o.OUI == lldp.OUIIEEE8023 && o.Subtype == lldp.IEEE8023SubtypeLinkAggregation:
+		l := new(lldp.LinkAggregation)
+		if err := l.UnmarshalBinary(b); err == nil {
+			return "link-aggregation", fmt.Sprintf("port %d (capable: %t, aggregated: %t)",
+				l.PortID, l.Capable, l.Enabled)
+		}
+	case o.OUI == lldp.OUIIEEE8023 && o.Subtype == lldp.IEEE8023SubtypeMACPHY:
+		m := new(lldp.MACPHY)
+		if err := m.UnmarshalBinary(b); err == nil {
+			return "mac-phy", fmt.Sprintf("MAU type %d, advertised %#04x (autoneg supported: %t, enabled: %t)",
+				m.MAUType, uint16(m.Advertised), m.AutonegSupported, m.AutonegEnabled)
+		}
+	case o.OUI == lldp.OUITIA && o.Subtype == lldp.TIASubtypeCapabilities:
+		m := new(lldp.MEDCapabilities)
+		if err := m.UnmarshalBinary(b); err == nil {
+			return "med-capabilities", fmt.Sprintf("%#04x (device type %d)",
+				uint16(m.Capabilities), m.DeviceType)
+		}
+	case o.OUI == lldp.OUITIA && o.Subtype >= lldp.TIASubtypeHardwareRevision && o.Subtype <= lldp.TIASubtypeAssetID:
+		names := []string{
+			"hardware-revision", "firmware-revision", "software-revision",
+			"serial-number", "manufacturer", "model", "asset-id",
+		}
+
+		return "med-" + names[o.Subtype-lldp.TIASubtypeHardwareRevision], format.Text(o.Info)
+	}
+
+	return "organization-specific", hex.EncodeToString(o.Info)
+}
@@ -0,0 +1,113 @@
+package control
+
+import (
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/lldp"
+)
+
+func TestNewFrame(t *testing.T) {
+	tlv := func(typ lldp.TLVType, m interface{ MarshalBinary() ([]byte, error) }) *lldp.TLV {
+		b, err := m.MarshalBinary()
+		if err != nil {
+			panic(err)
+		}
+
+		return &lldp.TLV{Type: typ, Length: uint16(len(b)), Value: b}
+	}
+	org := func(m interface{ MarshalBinary() ([]byte, error) }) *lldp.TLV {
+		return tlv(lldp.TLVTypeOrganizationSpecific, m)
+	}
+	u8 := func(v uint8) *uint8 { return &v }
+
+	f := &lldp.Frame{
+		ChassisID: &lldp.ChassisID{
+			Subtype: lldp.ChassisIDSubtypeMACAddress,
+			ID:      net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0x00, 0x01},
+		},
+		PortID: &lldp.PortID{
+			Subtype: lldp.PortIDSubtypeLocallyAssigned,
+			ID:      []byte{0x00, 0xff},
+		},
+		TTL: 2 * time.Minute,
+		Optional: []*lldp.TLV{
+			{Type: lldp.TLVTypeSystemName, Length: 2, Value: []byte("sw")},
+			tlv(lldp.TLVTypeSystemCapabilities, &lldp.SystemCapabilities{
+				System:  lldp.CapabilityBridge | lldp.CapabilityRouter,
+				Enabled: lldp.CapabilityBridge,
+			}),
+			tlv(lldp.TLVTypeManagementAddress, &lldp.ManagementAddress{
+				Family:             lldp.AddressFamilyIPv4,
+				Address:            net.IPv4(192, 0, 2, 1).To4(),
+				InterfaceNumbering: lldp.InterfaceNumberingIfIndex,
+				InterfaceNumber:    2,
+			}),
+			org(&lldp.VLANName{ID: 100, Name: "voip"}),
+			org(&lldp.LinkAggregation{Capable: true, Enabled: true, PortID: 10}),
+			org(&lldp.OrganizationSpecific{OUI: lldp.OUITIA, Subtype: lldp.TIASubtypeSerialNumber, Info: []byte("ABC")}),
+			org(&lldp.OrganizationSpecific{OUI: lldp.OUI{0x00, 0x00, 0x5e}, Subtype: 1, Info: []byte{0x01}}),
+			{Type: 127 - 1, Length: 1, Value: []byte{0x01}},
+		},
+	}
+
+	want := Frame{
+		ChassisID: ID{Subtype: "mac-address", Value: "de:ad:be:ef:00:01"},
+		PortID:    ID{Subtype: "local", Value: "00ff"},
+		TTL:       120,
+		TLVs: []TLV{
+			{Type: "system-name", Name: "system-name", Value: "sw", Raw: "7377"},
+			{
+				Type:  "system-capabilities",
+				Name:  "system-capabilities",
+				Value: "system: bridge,router; enabled: bridge",
+				Raw:   "00140004",
+			},
+			{
+				Type:  "management-address",
+				Name:  "management-address",
+				Value: "192.0.2.1 (ifindex 2)",
+				Raw:   "0501c0000201020000000200",
+			},
+			{
+				Type:    "organization-specific",
+				OUI:     "00:80:c2",
+				Subtype: u8(3),
+				Name:    "vlan-name",
+				Value:   "100: voip",
+				Raw:     "0080c203006404766f6970",
+			},
+			{
+				Type:    "organization-specific",
+				OUI:     "00:80:c2",
+				Subtype: u8(7),
+				Name:    "link-aggregation",
+				Value:   "port 10 (capable: true, aggregated: true)",
+				Raw:     "0080c207030000000a",
+			},
+			{
+				Type:    "organization-specific",
+				OUI:     "00:12:bb",
+				Subtype: u8(8),
+				Name:    "med-serial-number",
+				Value:   "ABC",
+				Raw:     "0012bb08414243",
+			},
+			{
+				Type:    "organization-specific",
+				OUI:     "00:00:5e",
+				Subtype: u8(1),
+				Name:    "organization-specific",
+				Value:   "01",
+				Raw:     "00005e0101",
+			},
+			{Type: "unknown-126", Name: "unknown-126", Raw: "01"},
+		},
+	}
+
+	if got := NewFrame(f); !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected Frame:\n- want: %+v\n-  got: %+v", want, got)
+	}
+}
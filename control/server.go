@@ -0,0 +1,220 @@
+package control
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+)
+
+// A Server serves the control protocol for an Agent.
+type Server struct {
+	a Agent
+
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+	wg    sync.WaitGroup
+}
+
+// NewServer creates a Server for an Agent.
+func NewServer(a Agent) *Server {
+	return &Server{
+		a:     a,
+		conns: make(map[net.Conn]struct{}),
+	}
+}
+
+// Serve accepts connections on l and serves each until it is closed.  When
+// l is closed, Serve closes any open connections and returns nil.
+func (s *Server) Serve(l net.Listener) error {
+	defer s.closeAll()
+
+	for {
+		c, err := l.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+
+			return err
+		}
+
+		s.mu.Lock()
+		s.conns[c] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			_ = s.ServeConn(c)
+
+			s.mu.Lock()
+			delete(s.conns, c)
+			s.mu.Unlock()
+		}()
+	}
+}
+
+// closeAll closes all open connections and waits for them to finish.
+func (s *Server) closeAll() {
+	s.mu.Lock()
+	for c := range s.conns {
+		_ = c.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+// ServeConn serves requests on a single connection until the client closes
+// it, and then closes the connection.
+//
+// A request which is not valid JSON is answered with an error, and the
+// connection is closed.  A request which is valid JSON but contains invalid
+// values, such as an unknown admin status, is answered with an error, and
+// the connection continues to be served.
+func (s *Server) ServeConn(c net.Conn) error {
+	defer c.Close()
+
+	dec := json.NewDecoder(bufio.NewReader(c))
+	enc := json.NewEncoder(c)
+
+	for {
+		// Read each request in full before decoding its values, so that
+		// an invalid value leaves the stream in sync.
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			var serr *json.SyntaxError
+			if errors.As(err, &serr) {
+				_ = enc.Encode(Response{Error: "malformed request"})
+			}
+
+			return err
+		}
+
+		var req Request
+		if err := json.Unmarshal(raw, &req); err != nil {
+			if err := enc.Encode(errorResponse(fmt.Errorf("invalid request: %v", err))); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err := enc.Encode(s.handle(req)); err != nil {
+			return err
+		}
+	}
+}
+
+// handle produces the Response for a single Request.
+func (s *Server) handle(req Request) Response {
+	switch req.Command {
+	case CommandNeighbors:
+		return Response{Neighbors: s.neighbors(req.Interface)}
+	case CommandNeighbor:
+		for _, n := range s.neighbors(req.Interface) {
+			if n.Index == req.Index {
+				return Response{Neighbors: []Neighbor{n}}
+			}
+		}
+
+		return errorResponse(fmt.Errorf("no neighbor %d on interface %q", req.Index, req.Interface))
+	case CommandLocal:
+		return Response{Ports: s.ports(req.Interface, req.Command)}
+	case CommandCounters:
+		ts := s.a.Neighbors().Stats()
+		return Response{
+			Ports: s.ports(req.Interface, req.Command),
+			Table: &ts,
+		}
+	case CommandSetAdminStatus:
+		if req.AdminStatus == nil {
+			return errorResponse(errors.New("admin status is required"))
+		}
+		if err := s.a.SetAdminStatus(req.Interface, *req.AdminStatus); err != nil {
+			return errorResponse(err)
+		}
+
+		return Response{Ports: s.ports(req.Interface, req.Command)}
+	default:
+		return errorResponse(fmt.Errorf("unknown command %q", req.Command))
+	}
+}
+
+// neighbors returns the neighbors discovered on ifname, or on all
+// interfaces if ifname is empty.  Neighbors are numbered from zero on each
+// interface.
+func (s *Server) neighbors(ifname string) []Neighbor {
+	var (
+		nn      []Neighbor
+		indices = make(map[string]int)
+	)
+
+	for _, n := range s.a.Neighbors().Neighbors() {
+		i := indices[n.Interface]
+		indices[n.Interface]++
+
+		if ifname != "" && n.Interface != ifname {
+			continue
+		}
+
+		nn = append(nn, Neighbor{
+			Interface: n.Interface,
+			Index:     i,
+			Source:    n.Source.String(),
+			Updated:   n.Updated,
+			Expires:   n.Expires,
+			Frame:     NewFrame(n.Frame),
+		})
+	}
+
+	return nn
+}
+
+// ports returns the local state of ifname, or of all configured interfaces
+// if ifname is empty, as needed for a Command.
+func (s *Server) ports(ifname string, cmd Command) []Port {
+	status := s.a.AdminStatus()
+
+	var (
+		frames = s.a.LocalFrames()
+		stats  = s.a.Stats()
+	)
+
+	var pp []Port
+	for name, as := range status {
+		if ifname != "" && name != ifname {
+			continue
+		}
+
+		p := Port{
+			Interface:   name,
+			AdminStatus: as,
+		}
+
+		if f, ok := frames[name]; ok && cmd == CommandLocal {
+			lf := NewFrame(f)
+			p.Frame = &lf
+		}
+		if st, ok := stats[name]; ok && cmd == CommandCounters {
+			p.Stats = &st
+		}
+
+		pp = append(pp, p)
+	}
+
+	sort.Slice(pp, func(i, j int) bool {
+		return pp[i].Interface < pp[j].Interface
+	})
+
+	return pp
+}
+
+// errorResponse produces a Response for an error.
+func errorResponse(err error) Response {
+	return Response{Error: err.Error()}
+}
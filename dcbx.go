@@ -0,0 +1,115 @@
+package lldp
+
+import (
+	"io"
+)
+
+// IEEE 802.1Qaz organizationally specific subtypes for Data Center Bridging
+// Capability Exchange (DCBX) TLVs.
+const (
+	IEEE8021SubtypePFC uint8 = 0x0b
+)
+
+// PFCCapabilityMax is the maximum number of traffic classes which may be
+// advertised as supporting PFC in a PFC TLV.
+const PFCCapabilityMax = 15
+
+// Flag bits carried in a PFC.
+const (
+	pfcWilling = 1 << 7
+	pfcMBC     = 1 << 6
+
+	pfcCapabilityMask = 0x0f
+)
+
+// A PFC is a structure parsed from an IEEE 802.1Qaz Priority-based Flow
+// Control (PFC) Configuration organizationally specific TLV.
+type PFC struct {
+	// Willing indicates that the port is willing to accept a PFC
+	// configuration from its peer.
+	Willing bool
+
+	// MBC indicates that the port is capable of bypassing MACsec when
+	// sending PFC frames.
+	MBC bool
+
+	// Capability specifies the number of traffic classes which may
+	// simultaneously support PFC.
+	Capability uint8
+
+	// Enabled is a bit mask of the priorities on which PFC is enabled,
+	// where bit n indicates priority n.
+	Enabled uint8
+}
+
+// MarshalBinary allocates a byte slice and marshals a PFC into the binary
+// form of an organizationally specific TLV value.
+//
+// If Capability is greater than PFCCapabilityMax, ErrInvalidTLV is returned.
+func (p *PFC) MarshalBinary() ([]byte, error) {
+	if p.Capability > PFCCapabilityMax {
+		return nil, ErrInvalidTLV
+	}
+
+	// 1 byte: willing, MBC, and PFC capability
+	// 1 byte: PFC enable
+	info := make([]byte, 2)
+	if p.Willing {
+		info[0] |= pfcWilling
+	}
+	if p.MBC {
+		info[0] |= pfcMBC
+	}
+	info[0] |= p.Capability
+	info[1] = p.Enabled
+
+	return (&OrganizationSpecific{
+		OUI:     OUIIEEE8021,
+		Subtype: IEEE8021SubtypePFC,
+		Info:    info,
+	}).MarshalBinary()
+}
+
+// UnmarshalBinary unmarshals an organizationally specific TLV value into
+// a PFC.
+//
+// If the byte slice does not contain enough data to unmarshal a valid PFC,
+// io.ErrUnexpectedEOF is returned.
+//
+// If the byte slice does not carry the IEEE 802.1 OUI and PFC subtype,
+// ErrInvalidTLV is returned.
+func (p *PFC) UnmarshalBinary(b []byte) error {
+	info, err := organizationSpecificInfo(b, OUIIEEE8021, IEEE8021SubtypePFC)
+	if err != nil {
+		return err
+	}
+	if len(info) != 2 {
+		return io.ErrUnexpectedEOF
+	}
+
+	p.Willing = info[0]&pfcWilling != 0
+	p.MBC = info[0]&pfcMBC != 0
+	p.Capability = info[0] & pfcCapabilityMask
+	p.Enabled = info[1]
+
+	return nil
+}
+
+// PFC returns the IEEE 802.1Qaz PFC Configuration information carried in
+// a Frame's optional TLVs.
+//
+// If no PFC TLV is present, PFC returns nil and false.  Any errors
+// encountered while unmarshaling the TLV are also reported as false.
+func (f *Frame) PFC() (*PFC, bool) {
+	tt := f.OrganizationSpecific(OUIIEEE8021, IEEE8021SubtypePFC)
+	if len(tt) == 0 {
+		return nil, false
+	}
+
+	p := new(PFC)
+	if err := p.UnmarshalBinary(tt[0].Value); err != nil {
+		return nil, false
+	}
+
+	return p, true
+}
@@ -0,0 +1,105 @@
+package lldp
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestPFCMarshalBinary(t *testing.T) {
+	p := &PFC{Capability: PFCCapabilityMax + 1}
+	if _, err := p.MarshalBinary(); err != ErrInvalidTLV {
+		t.Fatalf("unexpected error:\n- want: %v\n-  got: %v", ErrInvalidTLV, err)
+	}
+}
+
+func TestPFCUnmarshalBinary(t *testing.T) {
+	var tests = []struct {
+		desc string
+		b    []byte
+		p    *PFC
+		err  error
+	}{
+		{
+			desc: "nil buffer",
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "wrong OUI",
+			b:    []byte{0x00, 0x12, 0x0f, 0x0b, 0x08, 0x18},
+			err:  ErrInvalidTLV,
+		},
+		{
+			desc: "short information string",
+			b:    []byte{0x00, 0x80, 0xc2, 0x0b, 0x08},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "PFC disabled",
+			b:    []byte{0x00, 0x80, 0xc2, 0x0b, 0x08, 0x00},
+			p:    &PFC{Capability: 8},
+		},
+		{
+			desc: "willing, priorities 3 and 4",
+			b:    []byte{0x00, 0x80, 0xc2, 0x0b, 0x88, 0x18},
+			p: &PFC{
+				Willing:    true,
+				Capability: 8,
+				Enabled:    1<<3 | 1<<4,
+			},
+		},
+		{
+			desc: "MBC, all priorities",
+			b:    []byte{0x00, 0x80, 0xc2, 0x0b, 0x48, 0xff},
+			p: &PFC{
+				MBC:        true,
+				Capability: 8,
+				Enabled:    0xff,
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		p := new(PFC)
+		if err := p.UnmarshalBinary(tt.b); err != nil {
+			if want, got := tt.err, err; want != got {
+				t.Fatalf("unexpected error:\n- want: %v\n-  got: %v", want, got)
+			}
+
+			continue
+		}
+
+		if want, got := tt.p, p; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected PFC:\n- want: %v\n-  got: %v", want, got)
+		}
+
+		b, err := p.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want, got := tt.b, b; !bytes.Equal(want, got) {
+			t.Fatalf("unexpected PFC bytes:\n- want: %v\n-  got: %v", want, got)
+		}
+
+		f := &Frame{
+			Optional: []*TLV{{
+				Type:   TLVTypeOrganizationSpecific,
+				Length: uint16(len(b)),
+				Value:  b,
+			}},
+		}
+
+		fp, ok := f.PFC()
+		if !ok {
+			t.Fatal("expected PFC TLV in Frame")
+		}
+
+		if want, got := tt.p, fp; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected Frame PFC:\n- want: %v\n-  got: %v", want, got)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package lldp
+
+import (
+	"fmt"
+)
+
+// An ErrorReason is a value used to indicate the specific reason a
+// DecodeError or EncodeError occurred.
+type ErrorReason uint8
+
+// List of valid ErrorReason values.
+const (
+	ErrorReasonUnknown ErrorReason = iota
+	ErrorReasonTruncatedHeader
+	ErrorReasonTruncatedValue
+	ErrorReasonTooFewTLVs
+	ErrorReasonMissingChassisID
+	ErrorReasonMissingPortID
+	ErrorReasonMissingTTL
+	ErrorReasonMissingEnd
+	ErrorReasonInvalidChassisID
+	ErrorReasonInvalidPortID
+	ErrorReasonInvalidTTLLength
+	ErrorReasonInvalidEndLength
+	ErrorReasonTTLTooLarge
+	ErrorReasonTypeTooLarge
+	ErrorReasonLengthTooLarge
+	ErrorReasonLengthMismatch
+	ErrorReasonOutOfOrder
+	ErrorReasonDuplicateTLV
+	ErrorReasonFrameTooLarge
+	ErrorReasonMissingXPDUDescriptor
+	ErrorReasonInvalidXPDUDescriptor
+	ErrorReasonInvalidAddressFamily
+	ErrorReasonInvalidAddressLength
+	ErrorReasonInvalidInterfaceNumbering
+	ErrorReasonOIDTooLarge
+	ErrorReasonNotOptional
+)
+
+// String returns a human-readable description of an ErrorReason.
+func (r ErrorReason) String() string {
+	switch r {
+	case ErrorReasonTruncatedHeader:
+		return "truncated TLV header"
+	case ErrorReasonTruncatedValue:
+		return "truncated TLV value"
+	case ErrorReasonTooFewTLVs:
+		return "too few TLVs"
+	case ErrorReasonMissingChassisID:
+		return "missing chassis ID"
+	case ErrorReasonMissingPortID:
+		return "missing port ID"
+	case ErrorReasonMissingTTL:
+		return "missing TTL"
+	case ErrorReasonMissingEnd:
+		return "missing end of LLDPDU"
+	case ErrorReasonInvalidChassisID:
+		return "invalid chassis ID"
+	case ErrorReasonInvalidPortID:
+		return "invalid port ID"
+	case ErrorReasonInvalidTTLLength:
+		return "TTL length is not 2"
+	case ErrorReasonInvalidEndLength:
+		return "end of LLDPDU length is not 0"
+	case ErrorReasonTTLTooLarge:
+		return "TTL is greater than 65535 seconds"
+	case ErrorReasonTypeTooLarge:
+		return "TLV type is greater than 127"
+	case ErrorReasonLengthTooLarge:
+		return "TLV length is greater than 511"
+	case ErrorReasonLengthMismatch:
+		return "TLV length does not match value length"
+	case ErrorReasonOutOfOrder:
+		return "mandatory TLV out of order"
+	case ErrorReasonDuplicateTLV:
+		return "duplicate mandatory TLV"
+	case ErrorReasonFrameTooLarge:
+		return "mandatory TLVs exceed maximum frame size"
+	case ErrorReasonMissingXPDUDescriptor:
+		return "missing XPDU descriptor"
+	case ErrorReasonInvalidXPDUDescriptor:
+		return "invalid XPDU descriptor"
+	case ErrorReasonInvalidAddressFamily:
+		return "invalid management address family"
+	case ErrorReasonInvalidAddressLength:
+		return "management address length is not between 1 and 31"
+	case ErrorReasonInvalidInterfaceNumbering:
+		return "invalid management address interface numbering"
+	case ErrorReasonOIDTooLarge:
+		return "management address OID length is greater than 128"
+	case ErrorReasonNotOptional:
+		return "TLV type is reserved for a mandatory TLV or end of LLDPDU"
+	default:
+		return fmt.Sprintf("ErrorReason(%d)", r)
+	}
+}
+
+// A DecodeError is returned when a Frame or TLV cannot be unmarshaled from
+// binary form.  It describes where and why decoding failed.
+//
+// DecodeError wraps one of io.ErrUnexpectedEOF, ErrInvalidFrame,
+// ErrInvalidXPDU, or ErrInvalidTLV, which can be checked using errors.Is.
+type DecodeError struct {
+	// Offset specifies the byte offset into the input where the problem
+	// was detected.
+	Offset int
+
+	// Index specifies the index of the TLV which caused the problem, or
+	// -1 if the problem is not associated with a single TLV.
+	Index int
+
+	// Type specifies the type of the TLV which caused the problem.  Type
+	// is only meaningful if Index is not -1.
+	Type TLVType
+
+	// Reason specifies the specific reason decoding failed.
+	Reason ErrorReason
+
+	// Err specifies the underlying error.
+	Err error
+}
+
+// Error implements error.
+func (e *DecodeError) Error() string {
+	if e.Index < 0 {
+		return fmt.Sprintf("lldp: decode error at offset %d: %s: %v",
+			e.Offset, e.Reason, e.Err)
+	}
+
+	return fmt.Sprintf("lldp: decode error at offset %d, TLV %d (type %d): %s: %v",
+		e.Offset, e.Index, e.Type, e.Reason, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// An EncodeError is returned when a Frame or TLV cannot be marshaled into
+// binary form.  It describes which value caused the problem and why.
+//
+// EncodeError wraps one of ErrInvalidFrame or ErrInvalidTLV, which can be
+// checked using errors.Is.
+type EncodeError struct {
+	// Index specifies the index of the TLV which caused the problem, or
+	// -1 if the problem is not associated with a single TLV.
+	Index int
+
+	// Type specifies the type of the TLV which caused the problem.  Type
+	// is only meaningful if Index is not -1.
+	Type TLVType
+
+	// Reason specifies the specific reason encoding failed.
+	Reason ErrorReason
+
+	// Err specifies the underlying error.
+	Err error
+}
+
+// Error implements error.
+func (e *EncodeError) Error() string {
+	if e.Index < 0 {
+		return fmt.Sprintf("lldp: encode error: %s: %v", e.Reason, e.Err)
+	}
+
+	return fmt.Sprintf("lldp: encode error, TLV %d (type %d): %s: %v",
+		e.Index, e.Type, e.Reason, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *EncodeError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,218 @@
+package lldp
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestFrameUnmarshalBinaryDecodeError(t *testing.T) {
+	var tests = []struct {
+		desc string
+		b    []byte
+		err  *DecodeError
+	}{
+		{
+			desc: "truncated header",
+			b: []byte{
+				0x02, 0x01, 0x00,
+				0x04,
+			},
+			err: &DecodeError{
+				Offset: 4,
+				Index:  1,
+				Reason: ErrorReasonTruncatedHeader,
+				Err:    io.ErrUnexpectedEOF,
+			},
+		},
+		{
+			desc: "truncated value",
+			b: []byte{
+				0x02, 0x01, 0x00,
+				0x04, 0x05, 0x00,
+			},
+			err: &DecodeError{
+				Offset: 5,
+				Index:  1,
+				Type:   TLVTypePortID,
+				Reason: ErrorReasonTruncatedValue,
+				Err:    io.ErrUnexpectedEOF,
+			},
+		},
+		{
+			desc: "too few TLVs",
+			b: []byte{
+				0x02, 0x01, 0x00,
+				0x04, 0x01, 0x00,
+			},
+			err: &DecodeError{
+				Offset: 6,
+				Index:  -1,
+				Reason: ErrorReasonTooFewTLVs,
+				Err:    io.ErrUnexpectedEOF,
+			},
+		},
+		{
+			desc: "early end of LLDPDU",
+			b: []byte{
+				0x02, 0x01, 0x00,
+				0x00, 0x00,
+			},
+			err: &DecodeError{
+				Offset: 3,
+				Index:  1,
+				Type:   TLVTypeEnd,
+				Reason: ErrorReasonMissingPortID,
+				Err:    ErrInvalidFrame,
+			},
+		},
+		{
+			desc: "missing chassis ID",
+			b: []byte{
+				0x04, 0x01, 0x00,
+				0x04, 0x01, 0x00,
+				0x06, 0x02, 0x00, 0x00,
+				0x00, 0x00,
+			},
+			err: &DecodeError{
+				Index:  0,
+				Type:   TLVTypePortID,
+				Reason: ErrorReasonMissingChassisID,
+				Err:    ErrInvalidFrame,
+			},
+		},
+		{
+			desc: "empty port ID",
+			b: []byte{
+				0x02, 0x01, 0x00,
+				0x04, 0x00,
+				0x06, 0x02, 0x00, 0x00,
+				0x00, 0x00,
+			},
+			err: &DecodeError{
+				Offset: 3,
+				Index:  1,
+				Type:   TLVTypePortID,
+				Reason: ErrorReasonInvalidPortID,
+				Err:    io.ErrUnexpectedEOF,
+			},
+		},
+		{
+			desc: "TTL length not 2",
+			b: []byte{
+				0x02, 0x01, 0x00,
+				0x04, 0x01, 0x00,
+				0x06, 0x01, 0x00,
+				0x00, 0x00,
+			},
+			err: &DecodeError{
+				Offset: 6,
+				Index:  2,
+				Type:   TLVTypeTTL,
+				Reason: ErrorReasonInvalidTTLLength,
+				Err:    ErrInvalidFrame,
+			},
+		},
+		{
+			desc: "end of LLDPDU length not 0",
+			b: []byte{
+				0x02, 0x01, 0x00,
+				0x04, 0x01, 0x00,
+				0x06, 0x02, 0x00, 0x00,
+				0x00, 0x01, 0x00,
+			},
+			err: &DecodeError{
+				Offset: 10,
+				Index:  3,
+				Type:   TLVTypeEnd,
+				Reason: ErrorReasonInvalidEndLength,
+				Err:    ErrInvalidFrame,
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		err := new(Frame).UnmarshalBinary(tt.b)
+
+		var de *DecodeError
+		if !errors.As(err, &de) {
+			t.Fatalf("expected *DecodeError, but got: %#v", err)
+		}
+
+		if want, got := tt.err, de; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected DecodeError:\n- want: %#v\n-  got: %#v", want, got)
+		}
+	}
+}
+
+func TestFrameMarshalBinaryEncodeError(t *testing.T) {
+	var tests = []struct {
+		desc string
+		f    *Frame
+		err  *EncodeError
+	}{
+		{
+			desc: "missing port ID",
+			f: &Frame{
+				ChassisID: &ChassisID{},
+			},
+			err: &EncodeError{
+				Index:  -1,
+				Reason: ErrorReasonMissingPortID,
+				Err:    ErrInvalidFrame,
+			},
+		},
+		{
+			desc: "optional TLV type too large",
+			f: &Frame{
+				ChassisID: &ChassisID{},
+				PortID:    &PortID{},
+				Optional: []*TLV{
+					{Type: TLVTypeSystemName},
+					{Type: TLVTypeMax + 1},
+				},
+			},
+			err: &EncodeError{
+				Index:  4,
+				Type:   TLVTypeMax + 1,
+				Reason: ErrorReasonTypeTooLarge,
+				Err:    ErrInvalidTLV,
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		_, err := tt.f.MarshalBinary()
+
+		var ee *EncodeError
+		if !errors.As(err, &ee) {
+			t.Fatalf("expected *EncodeError, but got: %#v", err)
+		}
+
+		if want, got := tt.err, ee; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected EncodeError:\n- want: %#v\n-  got: %#v", want, got)
+		}
+	}
+}
+
+func TestDecodeErrorIs(t *testing.T) {
+	err := error(&DecodeError{
+		Index:  2,
+		Type:   TLVTypeTTL,
+		Reason: ErrorReasonInvalidTTLLength,
+		Err:    ErrInvalidFrame,
+	})
+
+	if !errors.Is(err, ErrInvalidFrame) {
+		t.Fatal("DecodeError does not match ErrInvalidFrame")
+	}
+
+	if want, got := "lldp: decode error at offset 0, TLV 2 (type 3): TTL length is not 2: invalid frame", err.Error(); want != got {
+		t.Fatalf("unexpected error string:\n- want: %v\n-  got: %v", want, got)
+	}
+}
@@ -0,0 +1,120 @@
+package lldp
+
+import (
+	"bytes"
+	"errors"
+	"net"
+
+	"github.com/mdlayher/ethernet"
+)
+
+// List of destination MAC addresses to which LLDP frames may be sent.
+var (
+	// NearestBridge is the destination MAC address used to reach the
+	// nearest bridge.  It is the most commonly used LLDP address.
+	NearestBridge = net.HardwareAddr{0x01, 0x80, 0xc2, 0x00, 0x00, 0x0e}
+
+	// NearestNonTPMRBridge is the destination MAC address used to reach
+	// the nearest bridge which is not a two-port MAC relay.
+	NearestNonTPMRBridge = net.HardwareAddr{0x01, 0x80, 0xc2, 0x00, 0x00, 0x03}
+
+	// NearestCustomerBridge is the destination MAC address used to reach
+	// the nearest customer bridge.
+	NearestCustomerBridge = net.HardwareAddr{0x01, 0x80, 0xc2, 0x00, 0x00, 0x00}
+)
+
+var (
+	// ErrNotLLDP is returned when an Ethernet frame does not carry LLDP,
+	// because its EtherType is not EtherType or its destination address
+	// is not one of the LLDP destination MAC addresses.
+	ErrNotLLDP = errors.New("not an LLDP Ethernet frame")
+)
+
+// An EthernetFrame is a Frame along with information from the Ethernet
+// frame which carried it.
+type EthernetFrame struct {
+	// Destination specifies the destination MAC address of the Ethernet
+	// frame.
+	Destination net.HardwareAddr
+
+	// Source specifies the source MAC address of the Ethernet frame, which
+	// identifies the sending port.
+	Source net.HardwareAddr
+
+	// VLAN specifies the IEEE 802.1Q VLAN tag of the Ethernet frame, if
+	// present.
+	VLAN *ethernet.VLAN
+
+	// Frame specifies the LLDP frame carried in the Ethernet frame.
+	Frame *Frame
+}
+
+// UnmarshalBinary unmarshals a byte slice containing an Ethernet frame,
+// without a frame check sequence, into an EthernetFrame.
+//
+// If the Ethernet frame does not carry LLDP, ErrNotLLDP is returned.  Any
+// errors which occur while unmarshaling the Frame are also returned.
+func (e *EthernetFrame) UnmarshalBinary(b []byte) error {
+	ef := new(ethernet.Frame)
+	if err := ef.UnmarshalBinary(b); err != nil {
+		return err
+	}
+
+	return e.UnmarshalEthernet(ef)
+}
+
+// UnmarshalFCS unmarshals a byte slice containing an Ethernet frame,
+// with a trailing frame check sequence, into an EthernetFrame.
+//
+// If the frame check sequence is invalid, ethernet.ErrInvalidFCS is
+// returned.  If the Ethernet frame does not carry LLDP, ErrNotLLDP is
+// returned.  Any errors which occur while unmarshaling the Frame are also
+// returned.
+func (e *EthernetFrame) UnmarshalFCS(b []byte) error {
+	ef := new(ethernet.Frame)
+	if err := ef.UnmarshalFCS(b); err != nil {
+		return err
+	}
+
+	return e.UnmarshalEthernet(ef)
+}
+
+// UnmarshalEthernet unmarshals an ethernet.Frame into an EthernetFrame.
+// Any padding which follows the end of LLDPDU TLV is stored in the Frame's
+// Trailing field.
+//
+// If the Ethernet frame does not carry LLDP, ErrNotLLDP is returned.  Any
+// errors which occur while unmarshaling the Frame are also returned.
+func (e *EthernetFrame) UnmarshalEthernet(ef *ethernet.Frame) error {
+	if ef.EtherType != EtherType || !isLLDPDestination(ef.Destination) {
+		return ErrNotLLDP
+	}
+
+	f := new(Frame)
+	if err := f.UnmarshalBinary(ef.Payload); err != nil {
+		return err
+	}
+
+	e.Destination = ef.Destination
+	e.Source = ef.Source
+	e.VLAN = ef.VLAN
+	e.Frame = f
+
+	return nil
+}
+
+// isLLDPDestination determines if a MAC address is one of the LLDP
+// destination MAC addresses.
+func isLLDPDestination(addr net.HardwareAddr) bool {
+	for _, d := range []net.HardwareAddr{
+		NearestBridge,
+		NearestNonTPMRBridge,
+		NearestCustomerBridge,
+	} {
+		if bytes.Equal(addr, d) {
+			return true
+		}
+	}
+
+	return false
+}
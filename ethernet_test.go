@@ -0,0 +1,141 @@
+package lldp
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+)
+
+func TestEthernetFrameUnmarshalBinary(t *testing.T) {
+	source := net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad}
+
+	lldpdu := []byte{
+		0x02, 0x02, 7, 'a',
+		0x04, 0x02, 7, 'b',
+		0x06, 0x02, 0x00, 0x78,
+		0x00, 0x00,
+	}
+
+	// Minimum size Ethernet frame, padded with non-zero garbage.
+	padding := bytes.Repeat([]byte{0xff}, 46-len(lldpdu))
+
+	header := func(dst net.HardwareAddr, et ...byte) []byte {
+		b := append([]byte{}, dst...)
+		b = append(b, source...)
+		return append(b, et...)
+	}
+
+	var tests = []struct {
+		desc string
+		b    []byte
+		e    *EthernetFrame
+		err  error
+	}{
+		{
+			desc: "wrong EtherType",
+			b:    append(header(NearestBridge, 0x08, 0x00), lldpdu...),
+			err:  ErrNotLLDP,
+		},
+		{
+			desc: "wrong destination",
+			b:    append(header(ethernet.Broadcast, 0x88, 0xcc), lldpdu...),
+			err:  ErrNotLLDP,
+		},
+		{
+			desc: "invalid LLDPDU",
+			b:    append(header(NearestBridge, 0x88, 0xcc), 0x00, 0x00),
+			err:  ErrInvalidFrame,
+		},
+		{
+			desc: "OK, padding",
+			b:    append(append(header(NearestBridge, 0x88, 0xcc), lldpdu...), padding...),
+			e: &EthernetFrame{
+				Destination: NearestBridge,
+				Source:      source,
+				Frame: &Frame{
+					ChassisID: &ChassisID{Subtype: 7, ID: []byte("a")},
+					PortID:    &PortID{Subtype: 7, ID: []byte("b")},
+					TTL:       120 * time.Second,
+					Optional:  []*TLV{},
+					Trailing:  padding,
+				},
+			},
+		},
+		{
+			desc: "OK, 802.1Q tag",
+			b:    append(header(NearestNonTPMRBridge, 0x81, 0x00, 0x20, 0x0a, 0x88, 0xcc), lldpdu...),
+			e: &EthernetFrame{
+				Destination: NearestNonTPMRBridge,
+				Source:      source,
+				VLAN: &ethernet.VLAN{
+					Priority: 1,
+					ID:       10,
+				},
+				Frame: &Frame{
+					ChassisID: &ChassisID{Subtype: 7, ID: []byte("a")},
+					PortID:    &PortID{Subtype: 7, ID: []byte("b")},
+					TTL:       120 * time.Second,
+					Optional:  []*TLV{},
+				},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		e := new(EthernetFrame)
+		if err := e.UnmarshalBinary(tt.b); err != nil {
+			if want, got := tt.err, err; !errors.Is(got, want) {
+				t.Fatalf("unexpected error:\n- want: %v\n-  got: %v", want, got)
+			}
+
+			continue
+		}
+
+		if want, got := tt.e, e; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected EthernetFrame:\n- want: %+v\n-  got: %+v", want, got)
+		}
+	}
+}
+
+func TestEthernetFrameUnmarshalFCS(t *testing.T) {
+	lb, err := (&Frame{
+		ChassisID: &ChassisID{Subtype: 7, ID: []byte("a")},
+		PortID:    &PortID{Subtype: 7, ID: []byte("b")},
+		TTL:       120 * time.Second,
+	}).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := (&ethernet.Frame{
+		Destination: NearestBridge,
+		Source:      net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad},
+		EtherType:   EtherType,
+		Payload:     lb,
+	}).MarshalFCS()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := new(EthernetFrame)
+	if err := e.UnmarshalFCS(b); err != nil {
+		t.Fatal(err)
+	}
+
+	// Padding may follow the LLDPDU, but must not include the FCS.
+	if want, got := 46-len(lb), len(e.Frame.Trailing); got > want {
+		t.Fatalf("unexpected trailing bytes: %d > %d", got, want)
+	}
+
+	b[len(b)-1]++
+	if err := e.UnmarshalFCS(b); err != ethernet.ErrInvalidFCS {
+		t.Fatalf("unexpected error for invalid FCS: %v", err)
+	}
+}
@@ -0,0 +1,172 @@
+package host
+
+import (
+	"fmt"
+	"io/fs"
+	"net"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mdlayher/lldp"
+)
+
+// A Bond is a Linux bonding interface, which aggregates one or more slave
+// interfaces.
+type Bond struct {
+	// Name and Index specify the interface name and index of the bond.
+	Name  string
+	Index int
+
+	// Mode specifies the bonding mode, such as "802.3ad" or
+	// "active-backup".
+	Mode string
+
+	// Slaves specifies the names of the slave interfaces of the bond.
+	Slaves []string
+}
+
+// ReadBonds reads the bonding interfaces on a system from sysfs, in lexical
+// order of interface names.  If the bonding module is not loaded, it returns
+// no bonds.
+func ReadBonds(fsys fs.FS) ([]*Bond, error) {
+	matches, err := fs.Glob(fsys, "sys/class/net/*/bonding/slaves")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(matches)
+
+	bonds := make([]*Bond, 0, len(matches))
+	for _, m := range matches {
+		name := path.Base(path.Dir(path.Dir(m)))
+
+		slaves, err := readString(fsys, m)
+		if err != nil {
+			return nil, err
+		}
+
+		// The mode is reported as a name followed by a number, such as
+		// "802.3ad 4".
+		mode, err := readOptional(fsys, netPath(name, "bonding/mode"))
+		if err != nil {
+			return nil, err
+		}
+		mode, _, _ = strings.Cut(mode, " ")
+
+		index, err := interfaceIndex(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+
+		bonds = append(bonds, &Bond{
+			Name:   name,
+			Index:  index,
+			Mode:   mode,
+			Slaves: strings.Fields(slaves),
+		})
+	}
+
+	return bonds, nil
+}
+
+// LinkAggregation produces the link aggregation status of a slave interface
+// of a Bond.  A slave is considered aggregated if its link is up and, in
+// 802.3ad mode, if it belongs to the bond's active aggregator.
+func (b *Bond) LinkAggregation(fsys fs.FS, slave string) (*lldp.LinkAggregation, error) {
+	status, err := readOptional(fsys, netPath(slave, "bonding_slave/mii_status"))
+	if err != nil {
+		return nil, err
+	}
+
+	enabled := status == "up"
+	if enabled && b.Mode == "802.3ad" {
+		active, err := readOptional(fsys, netPath(b.Name, "bonding/ad_aggregator"))
+		if err != nil {
+			return nil, err
+		}
+		agg, err := readOptional(fsys, netPath(slave, "bonding_slave/ad_aggregator_id"))
+		if err != nil {
+			return nil, err
+		}
+
+		enabled = active != "" && active == agg
+	}
+
+	l := &lldp.LinkAggregation{
+		Capable: true,
+		Enabled: enabled,
+	}
+	if enabled {
+		l.PortID = uint32(b.Index)
+	}
+
+	return l, nil
+}
+
+// bondOf returns the Bond of which an interface is a slave, or nil if it is
+// not a bond slave.
+func bondOf(fsys fs.FS, ifname string) (*Bond, error) {
+	bonds, err := ReadBonds(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, b := range bonds {
+		for _, s := range b.Slaves {
+			if s == ifname {
+				return b, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// hardwareMAC returns the hardware MAC address of an interface, and whether
+// that address is permanent.  Bond slaves take on the MAC address of their
+// bond, so the permanent address of a slave is read from its bonding_slave
+// directory instead.
+func hardwareMAC(fsys fs.FS, ifname string) (net.HardwareAddr, bool, error) {
+	perm, err := readOptional(fsys, netPath(ifname, "bonding_slave/perm_hwaddr"))
+	if err != nil {
+		return nil, false, err
+	}
+	if perm != "" {
+		mac, err := net.ParseMAC(perm)
+		if err != nil {
+			return nil, false, fmt.Errorf("host: invalid permanent MAC address for %q: %w", ifname, err)
+		}
+
+		return mac, true, nil
+	}
+
+	mac, err := interfaceMAC(fsys, ifname)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// NET_ADDR_PERM is 0; any other value indicates a random, stolen, or
+	// administratively set address.
+	t, err := readOptional(fsys, netPath(ifname, "addr_assign_type"))
+	if err != nil {
+		return nil, false, err
+	}
+
+	return mac, t == "0", nil
+}
+
+// interfaceIndex returns the interface index of an interface.
+func interfaceIndex(fsys fs.FS, ifname string) (int, error) {
+	s, err := readString(fsys, netPath(ifname, "ifindex"))
+	if err != nil {
+		return 0, err
+	}
+
+	index, err := strconv.ParseUint(s, 10, 31)
+	if err != nil {
+		return 0, fmt.Errorf("host: invalid ifindex for %q: %w", ifname, err)
+	}
+
+	return int(index), nil
+}
@@ -0,0 +1,187 @@
+package host
+
+import (
+	"net"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/mdlayher/lldp"
+)
+
+// addBond adds an 802.3ad bond of eth0 and eth1 to a fixture filesystem.
+// Both slaves take on the MAC address of the bond, but only eth0 belongs to
+// the active aggregator.
+func addBond(fsys fstest.MapFS) {
+	addInterface(fsys, "bond0", "de:ad:be:ef:00:10", 10, false)
+	for k, v := range map[string]string{
+		"bond0/bonding/slaves":                "eth0 eth1",
+		"bond0/bonding/mode":                  "802.3ad 4",
+		"bond0/bonding/ad_aggregator":         "1",
+		"eth0/address":                        "de:ad:be:ef:00:10",
+		"eth0/addr_assign_type":               "3",
+		"eth0/bonding_slave/perm_hwaddr":      "de:ad:be:ef:00:02",
+		"eth0/bonding_slave/mii_status":       "up",
+		"eth0/bonding_slave/ad_aggregator_id": "1",
+		"eth1/address":                        "de:ad:be:ef:00:10",
+		"eth1/addr_assign_type":               "3",
+		"eth1/bonding_slave/perm_hwaddr":      "de:ad:be:ef:00:01",
+		"eth1/bonding_slave/mii_status":       "up",
+		"eth1/bonding_slave/ad_aggregator_id": "2",
+	} {
+		fsys["sys/class/net/"+k] = &fstest.MapFile{Data: []byte(v + "\n")}
+	}
+}
+
+func TestReadBonds(t *testing.T) {
+	fsys := testFS()
+
+	bonds, err := ReadBonds(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bonds) != 0 {
+		t.Fatalf("expected no bonds, but got: %v", bonds)
+	}
+
+	addBond(fsys)
+
+	bonds, err = ReadBonds(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*Bond{{
+		Name:   "bond0",
+		Index:  10,
+		Mode:   "802.3ad",
+		Slaves: []string{"eth0", "eth1"},
+	}}
+
+	if !reflect.DeepEqual(want, bonds) {
+		t.Fatalf("unexpected Bonds:\n- want: %v\n-  got: %v", want, bonds)
+	}
+}
+
+func TestBondLinkAggregation(t *testing.T) {
+	var tests = []struct {
+		desc   string
+		mode   string
+		slave  string
+		status string
+		l      *lldp.LinkAggregation
+	}{
+		{
+			desc:   "802.3ad active aggregator",
+			mode:   "802.3ad",
+			slave:  "eth0",
+			status: "up",
+			l: &lldp.LinkAggregation{
+				Capable: true,
+				Enabled: true,
+				PortID:  10,
+			},
+		},
+		{
+			desc:   "802.3ad inactive aggregator",
+			mode:   "802.3ad",
+			slave:  "eth1",
+			status: "up",
+			l: &lldp.LinkAggregation{
+				Capable: true,
+			},
+		},
+		{
+			desc:   "active-backup link up",
+			mode:   "active-backup",
+			slave:  "eth1",
+			status: "up",
+			l: &lldp.LinkAggregation{
+				Capable: true,
+				Enabled: true,
+				PortID:  10,
+			},
+		},
+		{
+			desc:   "active-backup link down",
+			mode:   "active-backup",
+			slave:  "eth1",
+			status: "down",
+			l: &lldp.LinkAggregation{
+				Capable: true,
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		fsys := testFS()
+		addBond(fsys)
+		fsys["sys/class/net/"+tt.slave+"/bonding_slave/mii_status"] = &fstest.MapFile{Data: []byte(tt.status + "\n")}
+
+		b := &Bond{
+			Name:  "bond0",
+			Index: 10,
+			Mode:  tt.mode,
+		}
+
+		l, err := b.LinkAggregation(fsys, tt.slave)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want, got := tt.l, l; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected LinkAggregation:\n- want: %v\n-  got: %v", want, got)
+		}
+	}
+}
+
+func TestCollectorFrameBondSlave(t *testing.T) {
+	fsys := testFS()
+	addBond(fsys)
+
+	var addrsIfname string
+	c := &Collector{
+		FS:        fsys,
+		ChassisID: LowestPermanentMAC,
+		PortID:    PortIDMACAddress,
+		Addrs: func(ifname string) ([]net.IP, error) {
+			addrsIfname = ifname
+			return []net.IP{net.IPv4(192, 0, 2, 1)}, nil
+		},
+	}
+
+	f, err := c.Frame("eth0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Slaves share the bond MAC, so their permanent addresses must be used
+	// for both the chassis ID and the port ID.
+	if want, got := (net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0x00, 0x01}), net.HardwareAddr(f.ChassisID.ID); !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected chassis ID: %v != %v", want, got)
+	}
+	if want, got := (net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0x00, 0x02}), net.HardwareAddr(f.PortID.ID); !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected port ID: %v != %v", want, got)
+	}
+
+	if want, got := "bond0", addrsIfname; want != got {
+		t.Fatalf("unexpected management address interface: %q != %q", want, got)
+	}
+
+	l, ok := f.LinkAggregation()
+	if !ok {
+		t.Fatal("expected Link Aggregation TLV in Frame")
+	}
+
+	want := &lldp.LinkAggregation{
+		Capable: true,
+		Enabled: true,
+		PortID:  10,
+	}
+
+	if !reflect.DeepEqual(want, l) {
+		t.Fatalf("unexpected LinkAggregation:\n- want: %v\n-  got: %v", want, l)
+	}
+}
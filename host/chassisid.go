@@ -0,0 +1,268 @@
+package host
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"strings"
+
+	"github.com/mdlayher/lldp"
+)
+
+// A ChassisIDPolicy selects the ChassisID advertised on every interface of
+// a host, using information read from a filesystem.
+type ChassisIDPolicy func(fsys fs.FS) (*lldp.ChassisID, error)
+
+// ErrNoChassisID is returned by a ChassisIDPolicy when the information it
+// relies upon is not available on a host.
+var ErrNoChassisID = errors.New("no chassis ID available")
+
+// DefaultChassisID is the ChassisIDPolicy used by a Collector when no policy
+// is specified.  It prefers identifiers which survive NIC replacement and
+// interface renumbering, in the following order:
+//   - LowestPermanentMAC
+//   - MachineID
+//   - DMISerial
+//   - LowestMAC
+var DefaultChassisID = Fallback(
+	LowestPermanentMAC,
+	MachineID,
+	DMISerial,
+	LowestMAC,
+)
+
+// Fallback produces a ChassisIDPolicy which tries each of the input policies
+// in order, and uses the result of the first one which succeeds.
+//
+// If every policy fails, the returned error wraps each policy's error.
+func Fallback(policies ...ChassisIDPolicy) ChassisIDPolicy {
+	return func(fsys fs.FS) (*lldp.ChassisID, error) {
+		errs := make([]error, 0, len(policies))
+		for _, p := range policies {
+			c, err := p(fsys)
+			if err == nil {
+				return c, nil
+			}
+
+			errs = append(errs, err)
+		}
+
+		if len(errs) == 0 {
+			return nil, ErrNoChassisID
+		}
+
+		return nil, errors.Join(errs...)
+	}
+}
+
+// LowestMAC is a ChassisIDPolicy which selects the numerically lowest MAC
+// address of all physical Ethernet interfaces.
+func LowestMAC(fsys fs.FS) (*lldp.ChassisID, error) {
+	return selectMAC(fsys, false, true)
+}
+
+// LowestPermanentMAC is a ChassisIDPolicy which selects the numerically
+// lowest permanent MAC address of all physical Ethernet interfaces.
+// Interfaces with random or administratively assigned MAC addresses are
+// ignored.
+func LowestPermanentMAC(fsys fs.FS) (*lldp.ChassisID, error) {
+	return selectMAC(fsys, true, true)
+}
+
+// FirstPermanentMAC is a ChassisIDPolicy which selects the permanent MAC
+// address of the first physical Ethernet interface, in lexical order of
+// interface names.
+func FirstPermanentMAC(fsys fs.FS) (*lldp.ChassisID, error) {
+	return selectMAC(fsys, true, false)
+}
+
+// InterfaceMAC produces a ChassisIDPolicy which selects the MAC address of
+// the named interface.
+func InterfaceMAC(ifname string) ChassisIDPolicy {
+	return func(fsys fs.FS) (*lldp.ChassisID, error) {
+		mac, err := interfaceMAC(fsys, ifname)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("host: interface %q: %w", ifname, ErrNoChassisID)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return &lldp.ChassisID{
+			Subtype: lldp.ChassisIDSubtypeMACAddress,
+			ID:      mac,
+		}, nil
+	}
+}
+
+// machineIDApp is the application ID used to derive an application
+// specific ID from the systemd machine ID.
+var machineIDApp = [16]byte{
+	0x88, 0x35, 0x4a, 0xc5, 0x19, 0xf0, 0x44, 0xf6,
+	0x83, 0x20, 0x0d, 0x7d, 0x3e, 0x99, 0x3b, 0x8c,
+}
+
+// MachineID is a ChassisIDPolicy which selects an ID derived from the
+// systemd machine ID in /etc/machine-id, as a locally assigned chassis ID.
+//
+// The machine ID is confidential and must not be exposed on the network,
+// so it is not advertised directly.  Instead, MachineID advertises an
+// application specific ID, computed from the machine ID in the same way as
+// sd_id128_get_machine_app_specific(3): an HMAC-SHA256 of an application ID
+// keyed by the machine ID, truncated to a 128-bit UUID and formatted in
+// hexadecimal.  The ID is stable for a host, but cannot be used to recover
+// its machine ID.
+func MachineID(fsys fs.FS) (*lldp.ChassisID, error) {
+	s, err := readOptional(fsys, "etc/machine-id")
+	if err != nil {
+		return nil, err
+	}
+
+	// An uninitialized or malformed machine ID is treated as missing.
+	key, err := hex.DecodeString(s)
+	if err != nil || len(key) != 16 {
+		return nil, fmt.Errorf("host: machine ID: %w", ErrNoChassisID)
+	}
+
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write(machineIDApp[:])
+	id := mac.Sum(nil)[:16]
+
+	// Mark the ID as a version 4, variant 1 UUID.
+	id[6] = id[6]&0x0f | 0x40
+	id[8] = id[8]&0x3f | 0x80
+
+	return &lldp.ChassisID{
+		Subtype: lldp.ChassisIDSubtypeLocallyAssigned,
+		ID:      []byte(hex.EncodeToString(id)),
+	}, nil
+}
+
+// DMISerial is a ChassisIDPolicy which selects the product serial number
+// from /sys/class/dmi/id/product_serial, as a chassis component chassis ID.
+// Well-known placeholder values used by firmware vendors are ignored.
+func DMISerial(fsys fs.FS) (*lldp.ChassisID, error) {
+	serial, err := readOptional(fsys, "sys/class/dmi/id/product_serial")
+	if err != nil {
+		return nil, err
+	}
+	if !validDMI(serial) {
+		return nil, fmt.Errorf("host: DMI product serial: %w", ErrNoChassisID)
+	}
+
+	return &lldp.ChassisID{
+		Subtype: lldp.ChassisIDSubtypeChassisComponenent,
+		ID:      []byte(serial),
+	}, nil
+}
+
+// Fixed produces a ChassisIDPolicy which always selects the input string,
+// as a locally assigned chassis ID.
+func Fixed(id string) ChassisIDPolicy {
+	return func(_ fs.FS) (*lldp.ChassisID, error) {
+		if id == "" {
+			return nil, fmt.Errorf("host: fixed: %w", ErrNoChassisID)
+		}
+
+		return &lldp.ChassisID{
+			Subtype: lldp.ChassisIDSubtypeLocallyAssigned,
+			ID:      []byte(id),
+		}, nil
+	}
+}
+
+// ParseChassisIDPolicy parses a comma-separated list of chassis ID
+// strategies into a ChassisIDPolicy which tries each in order.  Valid
+// strategies are:
+//   - "lowest-mac": LowestMAC
+//   - "lowest-permanent-mac": LowestPermanentMAC
+//   - "first-permanent-mac": FirstPermanentMAC
+//   - "interface:NAME": InterfaceMAC(NAME)
+//   - "machine-id": MachineID
+//   - "dmi-serial": DMISerial
+//   - "fixed:ID": Fixed(ID)
+func ParseChassisIDPolicy(s string) (ChassisIDPolicy, error) {
+	var policies []ChassisIDPolicy
+	for _, f := range strings.Split(s, ",") {
+		f = strings.TrimSpace(f)
+		k, v, _ := strings.Cut(f, ":")
+
+		var p ChassisIDPolicy
+		switch {
+		case f == "lowest-mac":
+			p = LowestMAC
+		case f == "lowest-permanent-mac":
+			p = LowestPermanentMAC
+		case f == "first-permanent-mac":
+			p = FirstPermanentMAC
+		case f == "machine-id":
+			p = MachineID
+		case f == "dmi-serial":
+			p = DMISerial
+		case k == "interface" && v != "":
+			p = InterfaceMAC(v)
+		case k == "fixed" && v != "":
+			p = Fixed(v)
+		default:
+			return nil, fmt.Errorf("host: unknown chassis ID strategy %q", f)
+		}
+
+		policies = append(policies, p)
+	}
+
+	return Fallback(policies...), nil
+}
+
+// selectMAC selects a MAC address from the physical Ethernet interfaces on
+// a system, optionally considering only permanent addresses, and choosing
+// either the numerically lowest address or the first interface's address.
+func selectMAC(fsys fs.FS, permanent, lowest bool) (*lldp.ChassisID, error) {
+	ifis, err := physicalInterfaces(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	var selected net.HardwareAddr
+	for _, ifi := range ifis {
+		mac, perm, err := hardwareMAC(fsys, ifi)
+		if err != nil {
+			return nil, err
+		}
+		if permanent && !perm {
+			continue
+		}
+
+		if selected == nil || (lowest && bytes.Compare(mac, selected) < 0) {
+			selected = mac
+		}
+		if !lowest {
+			break
+		}
+	}
+
+	if selected == nil {
+		return nil, fmt.Errorf("host: MAC address: %w", ErrNoChassisID)
+	}
+
+	return &lldp.ChassisID{
+		Subtype: lldp.ChassisIDSubtypeMACAddress,
+		ID:      selected,
+	}, nil
+}
+
+// validDMI determines if a DMI string contains meaningful information, as
+// opposed to empty or placeholder values set by firmware vendors.
+func validDMI(s string) bool {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "0", "none", "not specified", "not applicable", "default string",
+		"to be filled by o.e.m.", "system serial number", "0123456789":
+		return false
+	}
+
+	return true
+}
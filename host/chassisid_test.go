@@ -0,0 +1,166 @@
+package host
+
+import (
+	"errors"
+	"io/fs"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/mdlayher/lldp"
+)
+
+func TestChassisIDPolicies(t *testing.T) {
+	// eth0 has a permanent address, eth1 has the lowest address but it was
+	// assigned randomly, and eth2 has the lowest permanent address.
+	fsys := testFS()
+	fsys["sys/class/net/eth0/address"] = &fstest.MapFile{Data: []byte("de:ad:be:ef:00:05\n")}
+	fsys["sys/class/net/eth1/address"] = &fstest.MapFile{Data: []byte("de:ad:be:ef:00:01\n")}
+	fsys["sys/class/net/eth1/addr_assign_type"] = &fstest.MapFile{Data: []byte("1\n")}
+	addInterface(fsys, "eth2", "de:ad:be:ef:00:03", 5, true)
+	fsys["etc/machine-id"] = &fstest.MapFile{Data: []byte("0123456789abcdef0123456789abcdef\n")}
+	fsys["sys/class/dmi/id/product_serial"] = &fstest.MapFile{Data: []byte("ABC123\n")}
+
+	mac := func(b byte) *lldp.ChassisID {
+		return &lldp.ChassisID{
+			Subtype: lldp.ChassisIDSubtypeMACAddress,
+			ID:      []byte{0xde, 0xad, 0xbe, 0xef, 0x00, b},
+		}
+	}
+
+	var tests = []struct {
+		desc   string
+		policy ChassisIDPolicy
+		c      *lldp.ChassisID
+	}{
+		{
+			desc:   "lowest MAC",
+			policy: LowestMAC,
+			c:      mac(0x01),
+		},
+		{
+			desc:   "lowest permanent MAC",
+			policy: LowestPermanentMAC,
+			c:      mac(0x03),
+		},
+		{
+			desc:   "first permanent MAC",
+			policy: FirstPermanentMAC,
+			c:      mac(0x05),
+		},
+		{
+			desc:   "interface MAC",
+			policy: InterfaceMAC("eth2"),
+			c:      mac(0x03),
+		},
+		{
+			desc:   "machine ID",
+			policy: MachineID,
+			c: &lldp.ChassisID{
+				Subtype: lldp.ChassisIDSubtypeLocallyAssigned,
+				// Derived from, but not revealing, the machine ID.
+				ID: []byte("e587ec45844f4345b2a8ccd88e27e2fc"),
+			},
+		},
+		{
+			desc:   "DMI serial",
+			policy: DMISerial,
+			c: &lldp.ChassisID{
+				Subtype: lldp.ChassisIDSubtypeChassisComponenent,
+				ID:      []byte("ABC123"),
+			},
+		},
+		{
+			desc:   "fixed",
+			policy: Fixed("rack1-host1"),
+			c: &lldp.ChassisID{
+				Subtype: lldp.ChassisIDSubtypeLocallyAssigned,
+				ID:      []byte("rack1-host1"),
+			},
+		},
+		{
+			desc:   "fallback skips missing interface",
+			policy: Fallback(InterfaceMAC("eth9"), DMISerial, MachineID),
+			c: &lldp.ChassisID{
+				Subtype: lldp.ChassisIDSubtypeChassisComponenent,
+				ID:      []byte("ABC123"),
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		c, err := tt.policy(fsys)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want, got := tt.c, c; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected ChassisID:\n- want: %v\n-  got: %v", want, got)
+		}
+	}
+}
+
+func TestChassisIDPoliciesUnavailable(t *testing.T) {
+	fsys := testFS()
+	for _, ifi := range []string{"eth0", "eth1"} {
+		fsys["sys/class/net/"+ifi+"/addr_assign_type"] = &fstest.MapFile{Data: []byte("3\n")}
+	}
+	fsys["sys/class/dmi/id/product_serial"] = &fstest.MapFile{Data: []byte("To Be Filled By O.E.M.\n")}
+
+	var tests = []struct {
+		desc   string
+		policy ChassisIDPolicy
+	}{
+		{desc: "no permanent MAC", policy: LowestPermanentMAC},
+		{desc: "no such interface", policy: InterfaceMAC("eth9")},
+		{desc: "no machine ID", policy: MachineID},
+		{
+			desc: "uninitialized machine ID",
+			policy: func(fs.FS) (*lldp.ChassisID, error) {
+				return MachineID(fstest.MapFS{"etc/machine-id": {Data: []byte("uninitialized\n")}})
+			},
+		},
+		{desc: "placeholder DMI serial", policy: DMISerial},
+		{desc: "empty fixed", policy: Fixed("")},
+		{desc: "all fallbacks fail", policy: Fallback(MachineID, DMISerial)},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		if _, err := tt.policy(fsys); !errors.Is(err, ErrNoChassisID) {
+			t.Fatalf("unexpected error:\n- want: %v\n-  got: %v", ErrNoChassisID, err)
+		}
+	}
+}
+
+func TestParseChassisIDPolicy(t *testing.T) {
+	fsys := testFS()
+
+	p, err := ParseChassisIDPolicy("machine-id, interface:eth0,fixed:foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := p(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &lldp.ChassisID{
+		Subtype: lldp.ChassisIDSubtypeMACAddress,
+		ID:      []byte{0xde, 0xad, 0xbe, 0xef, 0x00, 0x02},
+	}
+
+	if !reflect.DeepEqual(want, c) {
+		t.Fatalf("unexpected ChassisID:\n- want: %v\n-  got: %v", want, c)
+	}
+
+	for _, s := range []string{"", "bogus", "interface:", "fixed"} {
+		if _, err := ParseChassisIDPolicy(s); err == nil {
+			t.Fatalf("expected error for strategy %q", s)
+		}
+	}
+}
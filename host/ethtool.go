@@ -0,0 +1,114 @@
+package host
+
+import (
+	"errors"
+
+	"github.com/mdlayher/lldp"
+)
+
+// ErrNotSupported is returned by a LinkStater when link state information
+// is not available for an interface, such as for many virtual interfaces.
+var ErrNotSupported = errors.New("link state not supported")
+
+// Link mode bits, as defined by the ETHTOOL_LINK_MODE_* constants in the
+// Linux ethtool API.
+const (
+	linkMode10BaseTHalf    = 0
+	linkMode10BaseTFull    = 1
+	linkMode100BaseTHalf   = 2
+	linkMode100BaseTFull   = 3
+	linkMode1000BaseTHalf  = 4
+	linkMode1000BaseTFull  = 5
+	linkModePause          = 13
+	linkModeAsymPause      = 14
+	linkMode1000BaseKXFull = 17
+	linkMode1000BaseXFull  = 41
+)
+
+// A LinkState describes the configuration and status of a network link, as
+// reported by ethtool.
+type LinkState struct {
+	// Speed specifies the link speed in Mb/s, or 0 if unknown.
+	Speed int
+
+	// FullDuplex indicates if the link is operating in full duplex mode.
+	FullDuplex bool
+
+	// Fibre indicates if the link uses a fibre, rather than twisted pair,
+	// medium.
+	Fibre bool
+
+	// AutonegSupported and AutonegEnabled indicate if auto-negotiation is
+	// supported and currently enabled.
+	AutonegSupported bool
+	AutonegEnabled   bool
+
+	// Advertised specifies the link modes advertised by auto-negotiation,
+	// where bit N is set for ethtool link mode N.
+	Advertised uint64
+}
+
+// A LinkStater retrieves the LinkState of a network interface.
+type LinkStater interface {
+	LinkState(ifname string) (*LinkState, error)
+}
+
+// MACPHY produces an IEEE 802.3 MAC/PHY Configuration/Status TLV value from
+// a LinkState, mapping advertised link modes to PMD capability bits and the
+// operational speed and duplex to a MAU type.
+//
+// Link modes and speeds which have no equivalent are omitted or reported as
+// lldp.MAUTypeUnknown.
+func MACPHY(ls *LinkState) *lldp.MACPHY {
+	var adv lldp.PMDCapability
+	for bit, c := range map[uint]lldp.PMDCapability{
+		linkMode10BaseTHalf:    lldp.PMDCapability10BaseT,
+		linkMode10BaseTFull:    lldp.PMDCapability10BaseTFD,
+		linkMode100BaseTHalf:   lldp.PMDCapability100BaseTX,
+		linkMode100BaseTFull:   lldp.PMDCapability100BaseTXFD,
+		linkMode1000BaseTHalf:  lldp.PMDCapability1000BaseT,
+		linkMode1000BaseTFull:  lldp.PMDCapability1000BaseTFD,
+		linkModePause:          lldp.PMDCapabilityPause,
+		linkModeAsymPause:      lldp.PMDCapabilityAsymmetricPause,
+		linkMode1000BaseKXFull: lldp.PMDCapability1000BaseXFD,
+		linkMode1000BaseXFull:  lldp.PMDCapability1000BaseXFD,
+	} {
+		if ls.Advertised&(1<<bit) != 0 {
+			adv |= c
+		}
+	}
+
+	return &lldp.MACPHY{
+		AutonegSupported: ls.AutonegSupported,
+		AutonegEnabled:   ls.AutonegEnabled,
+		Advertised:       adv,
+		MAUType:          mauType(ls),
+	}
+}
+
+// mauType determines the operational MAU type of a link from its speed,
+// duplex, and medium.
+func mauType(ls *LinkState) lldp.MAUType {
+	type key struct {
+		speed      int
+		fullDuplex bool
+		fibre      bool
+	}
+
+	m := map[key]lldp.MAUType{
+		{10, false, false}:   lldp.MAUType10BaseTHD,
+		{10, true, false}:    lldp.MAUType10BaseTFD,
+		{100, false, false}:  lldp.MAUType100BaseTXHD,
+		{100, true, false}:   lldp.MAUType100BaseTXFD,
+		{100, false, true}:   lldp.MAUType100BaseFXHD,
+		{100, true, true}:    lldp.MAUType100BaseFXFD,
+		{1000, false, false}: lldp.MAUType1000BaseTHD,
+		{1000, true, false}:  lldp.MAUType1000BaseTFD,
+		{1000, false, true}:  lldp.MAUType1000BaseXHD,
+		{1000, true, true}:   lldp.MAUType1000BaseXFD,
+		{10000, true, false}: lldp.MAUType10GBaseT,
+		{10000, true, true}:  lldp.MAUType10GBaseR,
+	}
+
+	return m[key{ls.Speed, ls.FullDuplex, ls.Fibre}]
+}
@@ -0,0 +1,101 @@
+//go:build linux
+
+package host
+
+import (
+	"errors"
+	"syscall"
+	"unsafe"
+)
+
+// Constants from the Linux ethtool API.
+const (
+	siocEthtool    = 0x8946
+	ethtoolGSet    = 0x00000001
+	supportedAuto  = 1 << 6
+	portFibre      = 0x03
+	duplexFull     = 0x01
+	speedUnknown   = 0xffffffff
+	autonegEnabled = 0x01
+)
+
+// ethtoolCmd is the legacy struct ethtool_cmd, used with ETHTOOL_GSET.
+type ethtoolCmd struct {
+	Cmd           uint32
+	Supported     uint32
+	Advertising   uint32
+	Speed         uint16
+	Duplex        uint8
+	Port          uint8
+	PhyAddress    uint8
+	Transceiver   uint8
+	Autoneg       uint8
+	MDIOSupport   uint8
+	MaxTxPkt      uint32
+	MaxRxPkt      uint32
+	SpeedHi       uint16
+	EthTPMDIX     uint8
+	EthTPMDIXCtrl uint8
+	LPAdvertising uint32
+	Reserved      [2]uint32
+}
+
+// ifreqData is a struct ifreq carrying a pointer in its union.
+type ifreqData struct {
+	Name [syscall.IFNAMSIZ]byte
+	Data uintptr
+	_    [16]byte
+}
+
+// Ethtool returns a LinkStater which retrieves link state from the kernel
+// using the ethtool ioctl interface.
+func Ethtool() LinkStater {
+	return ethtool{}
+}
+
+type ethtool struct{}
+
+// LinkState implements LinkStater.
+func (ethtool) LinkState(ifname string) (*LinkState, error) {
+	if len(ifname) >= syscall.IFNAMSIZ {
+		return nil, syscall.EINVAL
+	}
+
+	fd, err := syscall.Socket(syscall.AF_INET, syscall.SOCK_DGRAM, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer syscall.Close(fd)
+
+	cmd := ethtoolCmd{Cmd: ethtoolGSet}
+	ifr := ifreqData{Data: uintptr(unsafe.Pointer(&cmd))}
+	copy(ifr.Name[:], ifname)
+
+	_, _, errno := syscall.Syscall(
+		syscall.SYS_IOCTL,
+		uintptr(fd),
+		siocEthtool,
+		uintptr(unsafe.Pointer(&ifr)),
+	)
+	if errno != 0 {
+		if errors.Is(errno, syscall.EOPNOTSUPP) {
+			return nil, ErrNotSupported
+		}
+
+		return nil, errno
+	}
+
+	speed := uint32(cmd.SpeedHi)<<16 | uint32(cmd.Speed)
+	if speed == speedUnknown {
+		speed = 0
+	}
+
+	return &LinkState{
+		Speed:            int(speed),
+		FullDuplex:       cmd.Duplex == duplexFull,
+		Fibre:            cmd.Port == portFibre,
+		AutonegSupported: cmd.Supported&supportedAuto != 0,
+		AutonegEnabled:   cmd.Autoneg == autonegEnabled,
+		Advertised:       uint64(cmd.Advertising),
+	}, nil
+}
@@ -0,0 +1,17 @@
+//go:build !linux
+
+package host
+
+// Ethtool returns a LinkStater which retrieves link state from the kernel
+// using the ethtool ioctl interface.  On platforms other than Linux, the
+// LinkStater always returns ErrNotSupported.
+func Ethtool() LinkStater {
+	return ethtool{}
+}
+
+type ethtool struct{}
+
+// LinkState implements LinkStater.
+func (ethtool) LinkState(_ string) (*LinkState, error) {
+	return nil, ErrNotSupported
+}
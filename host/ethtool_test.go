@@ -0,0 +1,155 @@
+package host
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/mdlayher/lldp"
+)
+
+func TestMACPHY(t *testing.T) {
+	var tests = []struct {
+		desc string
+		ls   *LinkState
+		m    *lldp.MACPHY
+	}{
+		{
+			desc: "unknown speed",
+			ls:   &LinkState{},
+			m:    &lldp.MACPHY{},
+		},
+		{
+			desc: "1000BASE-T full duplex, autoneg",
+			ls: &LinkState{
+				Speed:            1000,
+				FullDuplex:       true,
+				AutonegSupported: true,
+				AutonegEnabled:   true,
+				Advertised:       1<<linkMode100BaseTFull | 1<<linkMode1000BaseTFull | 1<<linkModePause,
+			},
+			m: &lldp.MACPHY{
+				AutonegSupported: true,
+				AutonegEnabled:   true,
+				Advertised: lldp.PMDCapability100BaseTXFD |
+					lldp.PMDCapability1000BaseTFD |
+					lldp.PMDCapabilityPause,
+				MAUType: lldp.MAUType1000BaseTFD,
+			},
+		},
+		{
+			desc: "100BASE-TX half duplex",
+			ls: &LinkState{
+				Speed: 100,
+			},
+			m: &lldp.MACPHY{
+				MAUType: lldp.MAUType100BaseTXHD,
+			},
+		},
+		{
+			desc: "10GBASE-R fibre, autoneg supported but disabled",
+			ls: &LinkState{
+				Speed:            10000,
+				FullDuplex:       true,
+				Fibre:            true,
+				AutonegSupported: true,
+			},
+			m: &lldp.MACPHY{
+				AutonegSupported: true,
+				MAUType:          lldp.MAUType10GBaseR,
+			},
+		},
+		{
+			desc: "25G has no MAU type, unknown link modes ignored",
+			ls: &LinkState{
+				Speed:      25000,
+				FullDuplex: true,
+				Advertised: 1 << 63,
+			},
+			m: &lldp.MACPHY{},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		if want, got := tt.m, MACPHY(tt.ls); !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected MACPHY:\n- want: %v\n-  got: %v", want, got)
+		}
+	}
+}
+
+func TestCollectorFrameLinks(t *testing.T) {
+	errBroken := errors.New("broken")
+
+	var tests = []struct {
+		desc string
+		ls   *LinkState
+		err  error
+		ok   bool
+	}{
+		{
+			desc: "link state",
+			ls: &LinkState{
+				Speed:      1000,
+				FullDuplex: true,
+			},
+			ok: true,
+		},
+		{
+			desc: "not supported",
+			err:  ErrNotSupported,
+		},
+		{
+			desc: "error",
+			err:  errBroken,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		c := &Collector{
+			FS:    testFS(),
+			Addrs: noAddrs,
+			Links: &testLinkStater{
+				ls:  tt.ls,
+				err: tt.err,
+			},
+		}
+
+		f, err := c.Frame("eth0")
+		if tt.err != nil && !errors.Is(tt.err, ErrNotSupported) {
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("unexpected error: %v != %v", tt.err, err)
+			}
+
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		m, ok := f.MACPHY()
+		if want, got := tt.ok, ok; want != got {
+			t.Fatalf("unexpected MACPHY presence: %v != %v", want, got)
+		}
+		if !ok {
+			continue
+		}
+
+		if want, got := lldp.MAUType1000BaseTFD, m.MAUType; want != got {
+			t.Fatalf("unexpected MAU type: %v != %v", want, got)
+		}
+	}
+}
+
+// testLinkStater is a LinkStater which returns fixed values.
+type testLinkStater struct {
+	ls  *LinkState
+	err error
+}
+
+func (s *testLinkStater) LinkState(_ string) (*LinkState, error) {
+	return s.ls, s.err
+}
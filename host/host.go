@@ -0,0 +1,474 @@
+// Package host collects information about the local Linux system, for use
+// when building outgoing LLDP frames.
+//
+// All information is read from a filesystem rooted at "/" by default, so
+// that collection can be tested against fixture trees of sysfs, procfs, and
+// configuration files.
+package host
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/mdlayher/lldp"
+)
+
+// DefaultTTL is the TTL used in Frames produced by a Collector when no TTL
+// is specified.
+const DefaultTTL = 120 * time.Second
+
+// A PortIDPolicy selects the PortID advertised on an interface.
+type PortIDPolicy int
+
+// List of valid PortIDPolicy values.
+const (
+	// PortIDInterfaceName advertises the interface name.
+	PortIDInterfaceName PortIDPolicy = iota
+
+	// PortIDMACAddress advertises the interface MAC address.
+	PortIDMACAddress
+
+	// PortIDInterfaceAlias advertises the interface alias (ifAlias), or
+	// the interface name if no alias is set.
+	PortIDInterfaceAlias
+)
+
+// A Collector collects information about the local system and produces
+// ready-to-send Frames for each of its interfaces.
+type Collector struct {
+	// FS specifies the filesystem from which system information is read.
+	// If nil, the root of the local filesystem is used.
+	FS fs.FS
+
+	// Addrs specifies a function which returns the IP addresses assigned
+	// to an interface, for use as management addresses.  If nil, the
+	// addresses are retrieved using the net package.
+	Addrs func(ifname string) ([]net.IP, error)
+
+	// ChassisID specifies the policy used to select the chassis ID.  If
+	// nil, DefaultChassisID is used.
+	ChassisID ChassisIDPolicy
+
+	// PortID specifies the policy used to select the port ID.
+	PortID PortIDPolicy
+
+	// TTL specifies the TTL of each Frame.  If zero, DefaultTTL is used.
+	TTL time.Duration
+
+	// Inventory specifies whether each Frame should carry the LLDP-MED
+	// capabilities and inventory TLVs produced by InventoryTLVs.
+	Inventory bool
+
+	// Links specifies a LinkStater used to produce the IEEE 802.3 MAC/PHY
+	// Configuration/Status TLV for each interface.  If nil, the TLV is
+	// omitted.  Interfaces for which Links returns ErrNotSupported are
+	// also sent without the TLV.
+	Links LinkStater
+
+	// VLANs specifies whether each Frame should carry the IEEE 802.1 VLAN
+	// TLVs produced by ReadVLANs.
+	VLANs bool
+
+	// Netlink specifies the Netlink used to read bridge VLAN filtering
+	// state when VLANs is set.  If nil, only 802.1Q VLAN subinterfaces are
+	// considered.
+	Netlink Netlink
+}
+
+// Interfaces returns the names of the physical Ethernet interfaces on the
+// system, in lexical order.
+func (c *Collector) Interfaces() ([]string, error) {
+	return physicalInterfaces(c.fs())
+}
+
+// Frames produces a Frame for each physical Ethernet interface on the
+// system, keyed by interface name.
+func (c *Collector) Frames() (map[string]*lldp.Frame, error) {
+	ifis, err := c.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	frames := make(map[string]*lldp.Frame, len(ifis))
+	for _, ifi := range ifis {
+		f, err := c.Frame(ifi)
+		if err != nil {
+			return nil, err
+		}
+
+		frames[ifi] = f
+	}
+
+	return frames, nil
+}
+
+// Frame produces a Frame for the named interface, containing:
+//   - a chassis ID selected by the chassis ID policy
+//   - a port ID selected by the port ID policy, using the permanent MAC
+//     address of a bond slave rather than the shared bond MAC address
+//   - the interface alias or name as the port description
+//   - the hostname as the system name
+//   - the operating system and kernel as the system description
+//   - system capabilities, inferred from IP forwarding and bridge membership
+//   - a management address for each global unicast IP address assigned to
+//     the interface, or to its bond if it is a bond slave
+//   - link aggregation status, if the interface is a bond slave
+//   - the IEEE 802.3 MAC/PHY configuration and status, if Links is set
+//   - IEEE 802.1 port VLAN ID and VLAN names, if VLANs is set
+//   - LLDP-MED capabilities and inventory, if Inventory is set
+func (c *Collector) Frame(ifname string) (*lldp.Frame, error) {
+	fsys := c.fs()
+
+	policy := c.ChassisID
+	if policy == nil {
+		policy = DefaultChassisID
+	}
+	chassis, err := policy(fsys)
+	if err != nil {
+		return nil, fmt.Errorf("host: failed to select chassis ID: %w", err)
+	}
+
+	// Bond slaves share the MAC address of their bond, so their hardware
+	// address is used to distinguish them, and their management addresses
+	// are those of the bond.
+	bond, err := bondOf(fsys, ifname)
+	if err != nil {
+		return nil, err
+	}
+	mac, _, err := hardwareMAC(fsys, ifname)
+	if err != nil {
+		return nil, err
+	}
+	alias, err := readOptional(fsys, netPath(ifname, "ifalias"))
+	if err != nil {
+		return nil, err
+	}
+	hostname, err := readString(fsys, "proc/sys/kernel/hostname")
+	if err != nil {
+		return nil, err
+	}
+	desc, err := systemDescription(fsys)
+	if err != nil {
+		return nil, err
+	}
+	system, enabled, err := capabilities(fsys, ifname)
+	if err != nil {
+		return nil, err
+	}
+	mgmtIfname := ifname
+	if bond != nil {
+		mgmtIfname = bond.Name
+	}
+	mgmt, err := c.managementAddresses(fsys, mgmtIfname)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := c.TTL
+	if ttl == 0 {
+		ttl = DefaultTTL
+	}
+
+	b := lldp.NewFrameBuilder().
+		ChassisID(chassis.Subtype, chassis.ID).
+		TTL(ttl)
+
+	switch {
+	case c.PortID == PortIDMACAddress:
+		b.PortMAC(mac)
+	case c.PortID == PortIDInterfaceAlias && alias != "":
+		b.PortID(lldp.PortIDSubtypeInterfaceAlias, []byte(alias))
+	default:
+		b.PortName(ifname)
+	}
+
+	portDesc := alias
+	if portDesc == "" {
+		portDesc = ifname
+	}
+
+	b.PortDescription(portDesc).
+		SystemName(hostname).
+		SystemDescription(desc).
+		SystemCapabilities(system, enabled)
+
+	for _, m := range mgmt {
+		b.ManagementAddress(m)
+	}
+
+	if bond != nil {
+		l, err := bond.LinkAggregation(fsys, ifname)
+		if err != nil {
+			return nil, err
+		}
+
+		v, _ := l.MarshalBinary()
+		b.TLV(lldp.TLVTypeOrganizationSpecific, v)
+	}
+
+	if c.Links != nil {
+		ls, err := c.Links.LinkState(ifname)
+		switch {
+		case errors.Is(err, ErrNotSupported):
+		case err != nil:
+			return nil, fmt.Errorf("host: failed to get link state of %q: %w", ifname, err)
+		default:
+			v, _ := MACPHY(ls).MarshalBinary()
+			b.TLV(lldp.TLVTypeOrganizationSpecific, v)
+		}
+	}
+
+	if c.VLANs {
+		pv, err := ReadVLANs(fsys, c.Netlink, ifname)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, t := range pv.TLVs() {
+			b.TLV(t.Type, t.Value)
+		}
+	}
+
+	if c.Inventory {
+		tt, err := InventoryTLVs(fsys)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, t := range tt {
+			b.TLV(t.Type, t.Value)
+		}
+	}
+
+	return b.Frame()
+}
+
+// fs returns the filesystem used by a Collector.
+func (c *Collector) fs() fs.FS {
+	if c.FS == nil {
+		return os.DirFS("/")
+	}
+
+	return c.FS
+}
+
+// managementAddresses produces management addresses for each global unicast
+// IP address assigned to an interface.
+func (c *Collector) managementAddresses(fsys fs.FS, ifname string) ([]*lldp.ManagementAddress, error) {
+	addrs := c.Addrs
+	if addrs == nil {
+		addrs = interfaceAddrs
+	}
+
+	ips, err := addrs(ifname)
+	if err != nil {
+		return nil, fmt.Errorf("host: failed to get addresses for %q: %w", ifname, err)
+	}
+
+	ifindex, err := interfaceIndex(fsys, ifname)
+	if err != nil {
+		return nil, err
+	}
+
+	var mm []*lldp.ManagementAddress
+	for _, ip := range ips {
+		if !ip.IsGlobalUnicast() {
+			continue
+		}
+
+		m := &lldp.ManagementAddress{
+			Family:             lldp.AddressFamilyIPv6,
+			Address:            ip.To16(),
+			InterfaceNumbering: lldp.InterfaceNumberingIfIndex,
+			InterfaceNumber:    uint32(ifindex),
+		}
+		if ip4 := ip.To4(); ip4 != nil {
+			m.Family = lldp.AddressFamilyIPv4
+			m.Address = ip4
+		}
+
+		mm = append(mm, m)
+	}
+
+	return mm, nil
+}
+
+// systemDescription produces a system description from the operating system
+// name and kernel version.
+func systemDescription(fsys fs.FS) (string, error) {
+	var fields []string
+
+	osName, err := osPrettyName(fsys)
+	if err != nil {
+		return "", err
+	}
+	if osName != "" {
+		fields = append(fields, osName)
+	}
+
+	for _, f := range []string{"ostype", "osrelease", "version"} {
+		s, err := readOptional(fsys, path.Join("proc/sys/kernel", f))
+		if err != nil {
+			return "", err
+		}
+		if s != "" {
+			fields = append(fields, s)
+		}
+	}
+
+	return strings.Join(fields, " "), nil
+}
+
+// osPrettyName returns the PRETTY_NAME field from os-release, or an empty
+// string if it is not available.
+func osPrettyName(fsys fs.FS) (string, error) {
+	for _, p := range []string{"etc/os-release", "usr/lib/os-release"} {
+		b, err := fs.ReadFile(fsys, p)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+
+		s := bufio.NewScanner(bytes.NewReader(b))
+		for s.Scan() {
+			k, v, ok := strings.Cut(s.Text(), "=")
+			if ok && k == "PRETTY_NAME" {
+				return strings.Trim(v, `"'`), nil
+			}
+		}
+
+		return "", s.Err()
+	}
+
+	return "", nil
+}
+
+// capabilities infers the system capabilities of a host, and the
+// capabilities enabled on an interface.
+func capabilities(fsys fs.FS, ifname string) (system, enabled lldp.Capability, err error) {
+	system = lldp.CapabilityBridge | lldp.CapabilityRouter | lldp.CapabilityStationOnly
+
+	for _, p := range []string{
+		"proc/sys/net/ipv4/ip_forward",
+		"proc/sys/net/ipv6/conf/all/forwarding",
+	} {
+		s, err := readOptional(fsys, p)
+		if err != nil {
+			return 0, 0, err
+		}
+		if s == "1" {
+			enabled |= lldp.CapabilityRouter
+		}
+	}
+
+	// An interface which is a member of a bridge has a brport directory.
+	if _, err := fs.Stat(fsys, netPath(ifname, "brport")); err == nil {
+		enabled |= lldp.CapabilityBridge
+	}
+
+	if enabled == 0 {
+		enabled = lldp.CapabilityStationOnly
+	}
+
+	return system, enabled, nil
+}
+
+// physicalInterfaces returns the names of all physical Ethernet interfaces.
+func physicalInterfaces(fsys fs.FS) ([]string, error) {
+	des, err := fs.ReadDir(fsys, "sys/class/net")
+	if err != nil {
+		return nil, err
+	}
+
+	var ifis []string
+	for _, de := range des {
+		// Physical interfaces have a backing device.
+		if _, err := fs.Stat(fsys, netPath(de.Name(), "device")); err != nil {
+			continue
+		}
+
+		// Only Ethernet interfaces (ARPHRD_ETHER) are eligible.
+		if t, err := readString(fsys, netPath(de.Name(), "type")); err != nil || t != "1" {
+			continue
+		}
+
+		ifis = append(ifis, de.Name())
+	}
+
+	return ifis, nil
+}
+
+// interfaceMAC returns the MAC address of an interface.
+func interfaceMAC(fsys fs.FS, ifname string) (net.HardwareAddr, error) {
+	s, err := readString(fsys, netPath(ifname, "address"))
+	if err != nil {
+		return nil, err
+	}
+
+	mac, err := net.ParseMAC(s)
+	if err != nil {
+		return nil, fmt.Errorf("host: invalid MAC address for %q: %w", ifname, err)
+	}
+
+	return mac, nil
+}
+
+// interfaceAddrs returns the IP addresses assigned to an interface using the
+// net package.
+func interfaceAddrs(ifname string) ([]net.IP, error) {
+	ifi, err := net.InterfaceByName(ifname)
+	if err != nil {
+		return nil, err
+	}
+
+	addrs, err := ifi.Addrs()
+	if err != nil {
+		return nil, err
+	}
+
+	var ips []net.IP
+	for _, a := range addrs {
+		if ipn, ok := a.(*net.IPNet); ok {
+			ips = append(ips, ipn.IP)
+		}
+	}
+
+	return ips, nil
+}
+
+// netPath returns the path to a sysfs attribute of a network interface.
+func netPath(ifname, attr string) string {
+	return path.Join("sys/class/net", ifname, attr)
+}
+
+// readString reads a file and returns its contents with surrounding
+// whitespace removed.
+func readString(fsys fs.FS, name string) (string, error) {
+	b, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(b)), nil
+}
+
+// readOptional is like readString, but returns an empty string if the file
+// does not exist.
+func readOptional(fsys fs.FS, name string) (string, error) {
+	s, err := readString(fsys, name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+
+	return s, err
+}
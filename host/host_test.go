@@ -0,0 +1,236 @@
+package host
+
+import (
+	"net"
+	"reflect"
+	"strconv"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/mdlayher/lldp"
+)
+
+// testFS returns a fixture filesystem describing a host with two physical
+// interfaces, a loopback interface, and a bridge.
+func testFS() fstest.MapFS {
+	fsys := fstest.MapFS{
+		"etc/os-release": {Data: []byte(
+			"NAME=\"Debian GNU/Linux\"\nPRETTY_NAME=\"Debian GNU/Linux 12 (bookworm)\"\nID=debian\n",
+		)},
+		"proc/sys/kernel/hostname":              {Data: []byte("host1\n")},
+		"proc/sys/kernel/ostype":                {Data: []byte("Linux\n")},
+		"proc/sys/kernel/osrelease":             {Data: []byte("6.1.0-13-amd64\n")},
+		"proc/sys/kernel/version":               {Data: []byte("#1 SMP PREEMPT_DYNAMIC\n")},
+		"proc/sys/net/ipv4/ip_forward":          {Data: []byte("0\n")},
+		"proc/sys/net/ipv6/conf/all/forwarding": {Data: []byte("0\n")},
+	}
+
+	addInterface(fsys, "eth0", "de:ad:be:ef:00:02", 2, true)
+	addInterface(fsys, "eth1", "de:ad:be:ef:00:01", 3, true)
+	addInterface(fsys, "lo", "00:00:00:00:00:00", 1, false)
+	fsys["sys/class/net/lo/type"] = &fstest.MapFile{Data: []byte("772\n")}
+	addInterface(fsys, "br0", "de:ad:be:ef:00:00", 4, false)
+	fsys["sys/class/net/br0/bridge/bridge_id"] = &fstest.MapFile{Data: []byte("8000.deadbeef0000\n")}
+
+	return fsys
+}
+
+// addInterface adds a network interface to a fixture filesystem.
+func addInterface(fsys fstest.MapFS, name, mac string, index int, physical bool) {
+	dir := "sys/class/net/" + name + "/"
+	fsys[dir+"address"] = &fstest.MapFile{Data: []byte(mac + "\n")}
+	fsys[dir+"ifindex"] = &fstest.MapFile{Data: []byte(strconv.Itoa(index) + "\n")}
+	fsys[dir+"type"] = &fstest.MapFile{Data: []byte("1\n")}
+	fsys[dir+"addr_assign_type"] = &fstest.MapFile{Data: []byte("0\n")}
+	fsys[dir+"ifalias"] = &fstest.MapFile{Data: []byte("\n")}
+	if physical {
+		fsys[dir+"device/vendor"] = &fstest.MapFile{Data: []byte("0x8086\n")}
+	}
+}
+
+func TestCollectorInterfaces(t *testing.T) {
+	c := &Collector{FS: testFS()}
+
+	ifis, err := c.Interfaces()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := []string{"eth0", "eth1"}, ifis; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected interfaces:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+func TestCollectorFrame(t *testing.T) {
+	fsys := testFS()
+	fsys["sys/class/net/eth0/ifalias"] = &fstest.MapFile{Data: []byte("uplink\n")}
+	fsys["sys/class/net/eth0/brport/state"] = &fstest.MapFile{Data: []byte("3\n")}
+
+	addrs := func(ifname string) ([]net.IP, error) {
+		return []net.IP{
+			net.ParseIP("192.0.2.1"),
+			net.ParseIP("fe80::1"),
+			net.ParseIP("2001:db8::1"),
+		}, nil
+	}
+
+	var tests = []struct {
+		desc     string
+		ifname   string
+		policy   PortIDPolicy
+		port     *lldp.PortID
+		portDesc string
+		caps     lldp.Capability
+	}{
+		{
+			desc:   "interface name, bridge member with alias",
+			ifname: "eth0",
+			policy: PortIDInterfaceName,
+			port: &lldp.PortID{
+				Subtype: lldp.PortIDSubtypeInterfaceName,
+				ID:      []byte("eth0"),
+			},
+			portDesc: "uplink",
+			caps:     lldp.CapabilityBridge,
+		},
+		{
+			desc:   "interface alias",
+			ifname: "eth0",
+			policy: PortIDInterfaceAlias,
+			port: &lldp.PortID{
+				Subtype: lldp.PortIDSubtypeInterfaceAlias,
+				ID:      []byte("uplink"),
+			},
+			portDesc: "uplink",
+			caps:     lldp.CapabilityBridge,
+		},
+		{
+			desc:   "interface alias not set",
+			ifname: "eth1",
+			policy: PortIDInterfaceAlias,
+			port: &lldp.PortID{
+				Subtype: lldp.PortIDSubtypeInterfaceName,
+				ID:      []byte("eth1"),
+			},
+			portDesc: "eth1",
+			caps:     lldp.CapabilityStationOnly,
+		},
+		{
+			desc:   "MAC address",
+			ifname: "eth1",
+			policy: PortIDMACAddress,
+			port: &lldp.PortID{
+				Subtype: lldp.PortIDSubtypeMACAddress,
+				ID:      []byte{0xde, 0xad, 0xbe, 0xef, 0x00, 0x01},
+			},
+			portDesc: "eth1",
+			caps:     lldp.CapabilityStationOnly,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		c := &Collector{
+			FS:     fsys,
+			Addrs:  addrs,
+			PortID: tt.policy,
+		}
+
+		f, err := c.Frame(tt.ifname)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		mgmt := func(family lldp.AddressFamily, ip net.IP, index uint32) *lldp.TLV {
+			b, err := (&lldp.ManagementAddress{
+				Family:             family,
+				Address:            ip,
+				InterfaceNumbering: lldp.InterfaceNumberingIfIndex,
+				InterfaceNumber:    index,
+			}).MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			return tlv(lldp.TLVTypeManagementAddress, b)
+		}
+
+		index := uint32(2)
+		if tt.ifname == "eth1" {
+			index = 3
+		}
+
+		want := &lldp.Frame{
+			// Lowest MAC address of all physical interfaces.
+			ChassisID: &lldp.ChassisID{
+				Subtype: lldp.ChassisIDSubtypeMACAddress,
+				ID:      []byte{0xde, 0xad, 0xbe, 0xef, 0x00, 0x01},
+			},
+			PortID: tt.port,
+			TTL:    DefaultTTL,
+			Optional: []*lldp.TLV{
+				tlv(lldp.TLVTypePortDescription, []byte(tt.portDesc)),
+				tlv(lldp.TLVTypeSystemName, []byte("host1")),
+				tlv(lldp.TLVTypeSystemDescription, []byte(
+					"Debian GNU/Linux 12 (bookworm) Linux 6.1.0-13-amd64 #1 SMP PREEMPT_DYNAMIC",
+				)),
+				tlv(lldp.TLVTypeSystemCapabilities, []byte{0x00, 0x94, 0x00, byte(tt.caps)}),
+				mgmt(lldp.AddressFamilyIPv4, net.IP{192, 0, 2, 1}, index),
+				mgmt(lldp.AddressFamilyIPv6, net.ParseIP("2001:db8::1"), index),
+			},
+		}
+
+		if !reflect.DeepEqual(want, f) {
+			t.Fatalf("unexpected Frame:\n- want: %v\n-  got: %v", want, f)
+		}
+	}
+}
+
+func TestCollectorFramesRouter(t *testing.T) {
+	fsys := testFS()
+	fsys["proc/sys/net/ipv4/ip_forward"] = &fstest.MapFile{Data: []byte("1\n")}
+
+	c := &Collector{
+		FS:    fsys,
+		Addrs: noAddrs,
+		TTL:   30 * time.Second,
+	}
+
+	frames, err := c.Frames()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := 2, len(frames); want != got {
+		t.Fatalf("unexpected number of Frames: %d != %d", want, got)
+	}
+
+	for ifname, f := range frames {
+		if want, got := 30*time.Second, f.TTL; want != got {
+			t.Fatalf("unexpected TTL for %q: %v != %v", ifname, want, got)
+		}
+
+		sc := new(lldp.SystemCapabilities)
+		if err := sc.UnmarshalBinary(f.Optional[3].Value); err != nil {
+			t.Fatal(err)
+		}
+
+		if want, got := lldp.CapabilityRouter, sc.Enabled; want != got {
+			t.Fatalf("unexpected enabled capabilities for %q: %v != %v", ifname, want, got)
+		}
+	}
+}
+
+func tlv(t lldp.TLVType, v []byte) *lldp.TLV {
+	return &lldp.TLV{
+		Type:   t,
+		Length: uint16(len(v)),
+		Value:  v,
+	}
+}
+
+func noAddrs(string) ([]net.IP, error) {
+	return nil, nil
+}
@@ -0,0 +1,77 @@
+package host
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+
+	"github.com/mdlayher/lldp"
+)
+
+// ReadInventory reads hardware inventory information from the DMI (SMBIOS)
+// attributes in /sys/class/dmi/id, and the software revision from the
+// running kernel, for use in the LLDP-MED inventory TLVs.
+//
+// Attributes which are missing, unreadable without privileges, or which
+// contain well-known placeholder values are left empty.
+func ReadInventory(fsys fs.FS) (*lldp.Inventory, error) {
+	inv := new(lldp.Inventory)
+
+	for _, f := range []struct {
+		name string
+		s    *string
+	}{
+		{name: "sys/class/dmi/id/product_version", s: &inv.HardwareRevision},
+		{name: "sys/class/dmi/id/bios_version", s: &inv.FirmwareRevision},
+		{name: "proc/sys/kernel/osrelease", s: &inv.SoftwareRevision},
+		{name: "sys/class/dmi/id/product_serial", s: &inv.SerialNumber},
+		{name: "sys/class/dmi/id/sys_vendor", s: &inv.ManufacturerName},
+		{name: "sys/class/dmi/id/product_name", s: &inv.ModelName},
+		{name: "sys/class/dmi/id/chassis_asset_tag", s: &inv.AssetID},
+	} {
+		s, err := readOptional(fsys, f.name)
+		if err != nil {
+			// Some attributes, such as the serial number, are only
+			// readable by root.
+			if errors.Is(err, fs.ErrPermission) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		if path.Dir(f.name) == "sys/class/dmi/id" && !validDMI(s) {
+			continue
+		}
+
+		*f.s = s
+	}
+
+	return inv, nil
+}
+
+// InventoryTLVs produces the LLDP-MED capabilities TLV and an LLDP-MED
+// inventory TLV for each field returned by ReadInventory.  Each field is
+// truncated to lldp.InventoryLengthMax bytes.
+func InventoryTLVs(fsys fs.FS) ([]*lldp.TLV, error) {
+	inv, err := ReadInventory(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	cb, err := (&lldp.MEDCapabilities{
+		Capabilities: lldp.MEDCapabilityCapabilities | lldp.MEDCapabilityInventory,
+		DeviceType:   lldp.MEDDeviceTypeEndpointClassI,
+	}).MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	tt := []*lldp.TLV{{
+		Type:   lldp.TLVTypeOrganizationSpecific,
+		Length: uint16(len(cb)),
+		Value:  cb,
+	}}
+
+	return append(tt, inv.TLVs()...), nil
+}
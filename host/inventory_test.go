@@ -0,0 +1,83 @@
+package host
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/mdlayher/lldp"
+)
+
+func TestReadInventory(t *testing.T) {
+	fsys := testFS()
+	for k, v := range map[string]string{
+		"product_version":   "1.0",
+		"bios_version":      "2.3.4",
+		"product_serial":    "ABC123",
+		"sys_vendor":        "Example Corp.",
+		"product_name":      "Server " + strings.Repeat("X", lldp.InventoryLengthMax),
+		"chassis_asset_tag": "Default string",
+	} {
+		fsys["sys/class/dmi/id/"+k] = &fstest.MapFile{Data: []byte(v + "\n")}
+	}
+
+	inv, err := ReadInventory(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &lldp.Inventory{
+		HardwareRevision: "1.0",
+		FirmwareRevision: "2.3.4",
+		SoftwareRevision: "6.1.0-13-amd64",
+		SerialNumber:     "ABC123",
+		ManufacturerName: "Example Corp.",
+		ModelName:        "Server " + strings.Repeat("X", lldp.InventoryLengthMax),
+	}
+
+	if !reflect.DeepEqual(want, inv) {
+		t.Fatalf("unexpected Inventory:\n- want: %v\n-  got: %v", want, inv)
+	}
+
+	c := &Collector{
+		FS:        fsys,
+		Addrs:     noAddrs,
+		Inventory: true,
+	}
+
+	f, err := c.Frame("eth0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n := len(f.OrganizationSpecific(lldp.OUITIA, lldp.TIASubtypeCapabilities)); n != 1 {
+		t.Fatalf("expected one LLDP-MED capabilities TLV, but got %d", n)
+	}
+
+	got, ok := f.Inventory()
+	if !ok {
+		t.Fatal("expected inventory in Frame")
+	}
+
+	// The model name must be truncated when transmitted.
+	want.ModelName = want.ModelName[:lldp.InventoryLengthMax]
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected Frame Inventory:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+func TestReadInventoryNoDMI(t *testing.T) {
+	inv, err := ReadInventory(testFS())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &lldp.Inventory{
+		SoftwareRevision: "6.1.0-13-amd64",
+	}
+
+	if !reflect.DeepEqual(want, inv) {
+		t.Fatalf("unexpected Inventory:\n- want: %v\n-  got: %v", want, inv)
+	}
+}
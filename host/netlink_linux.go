@@ -0,0 +1,183 @@
+//go:build linux
+
+package host
+
+import (
+	"encoding/binary"
+	"strings"
+	"syscall"
+)
+
+// Constants from the Linux rtnetlink and bridge APIs.
+const (
+	iflaAFSpec             = 26
+	iflaExtMask            = 29
+	rtextFilterBRVLAN      = 1 << 1
+	iflaBridgeVLANInfo     = 2
+	bridgeVLANInfoPVID     = 1 << 1
+	bridgeVLANInfoUntagged = 1 << 2
+	bridgeVLANInfoRangeBeg = 1 << 3
+	bridgeVLANInfoRangeEnd = 1 << 4
+)
+
+// RTNetlink returns a Netlink which retrieves VLAN configuration from the
+// kernel using rtnetlink.
+func RTNetlink() Netlink {
+	return rtnetlink{}
+}
+
+type rtnetlink struct{}
+
+// BridgeVLANs implements Netlink.
+func (rtnetlink) BridgeVLANs(ifname string) ([]BridgeVLAN, error) {
+	msgs, err := dumpBridgeLinks()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range msgs {
+		attrs, err := syscall.ParseNetlinkRouteAttr(&m)
+		if err != nil {
+			return nil, err
+		}
+
+		var (
+			name string
+			spec []byte
+		)
+		for _, a := range attrs {
+			switch a.Attr.Type {
+			case syscall.IFLA_IFNAME:
+				name = strings.TrimRight(string(a.Value), "\x00")
+			case iflaAFSpec:
+				spec = a.Value
+			}
+		}
+
+		if name == ifname {
+			return parseBridgeVLANs(spec), nil
+		}
+	}
+
+	return nil, nil
+}
+
+// dumpBridgeLinks dumps the AF_BRIDGE links on the system, along with their
+// VLAN information.
+func dumpBridgeLinks() ([]syscall.NetlinkMessage, error) {
+	fd, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_RAW|syscall.SOCK_CLOEXEC, syscall.NETLINK_ROUTE)
+	if err != nil {
+		return nil, err
+	}
+	defer syscall.Close(fd)
+
+	sa := &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK}
+	if err := syscall.Bind(fd, sa); err != nil {
+		return nil, err
+	}
+
+	// Header, ifinfomsg, and a single IFLA_EXT_MASK attribute.
+	req := make([]byte, syscall.NLMSG_HDRLEN+syscall.SizeofIfInfomsg+8)
+	ne := binary.NativeEndian
+	ne.PutUint32(req[0:4], uint32(len(req)))
+	ne.PutUint16(req[4:6], syscall.RTM_GETLINK)
+	ne.PutUint16(req[6:8], syscall.NLM_F_REQUEST|syscall.NLM_F_DUMP)
+	ne.PutUint32(req[8:12], 1)
+	req[syscall.NLMSG_HDRLEN] = syscall.AF_BRIDGE
+
+	attr := req[syscall.NLMSG_HDRLEN+syscall.SizeofIfInfomsg:]
+	ne.PutUint16(attr[0:2], 8)
+	ne.PutUint16(attr[2:4], iflaExtMask)
+	ne.PutUint32(attr[4:8], rtextFilterBRVLAN)
+
+	if err := syscall.Sendto(fd, req, 0, sa); err != nil {
+		return nil, err
+	}
+
+	var (
+		out []syscall.NetlinkMessage
+		buf = make([]byte, 32*1024)
+	)
+	for {
+		n, _, err := syscall.Recvfrom(fd, buf, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		msgs, err := syscall.ParseNetlinkMessage(buf[:n])
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range msgs {
+			switch m.Header.Type {
+			case syscall.NLMSG_DONE:
+				return out, nil
+			case syscall.NLMSG_ERROR:
+				if len(m.Data) < 4 {
+					return nil, syscall.EINVAL
+				}
+				if errno := int32(ne.Uint32(m.Data[0:4])); errno != 0 {
+					if syscall.Errno(-errno) == syscall.EOPNOTSUPP {
+						return nil, ErrNotSupported
+					}
+
+					return nil, syscall.Errno(-errno)
+				}
+			case syscall.RTM_NEWLINK:
+				// Copy the message data, because buf is reused.
+				m.Data = append([]byte(nil), m.Data...)
+				out = append(out, m)
+			}
+		}
+	}
+}
+
+// parseBridgeVLANs parses the IFLA_BRIDGE_VLAN_INFO attributes nested in an
+// IFLA_AF_SPEC attribute.
+func parseBridgeVLANs(b []byte) []BridgeVLAN {
+	ne := binary.NativeEndian
+
+	var (
+		vlans []BridgeVLAN
+		begin uint16
+	)
+	for len(b) >= 4 {
+		l := int(ne.Uint16(b[0:2]))
+		if l < 4 || l > len(b) {
+			break
+		}
+
+		if t := ne.Uint16(b[2:4]); t == iflaBridgeVLANInfo && l >= 8 {
+			flags := ne.Uint16(b[4:6])
+			vid := ne.Uint16(b[6:8])
+
+			bv := BridgeVLAN{
+				PVID:     flags&bridgeVLANInfoPVID != 0,
+				Untagged: flags&bridgeVLANInfoUntagged != 0,
+			}
+
+			switch {
+			case flags&bridgeVLANInfoRangeBeg != 0:
+				begin = vid
+			case flags&bridgeVLANInfoRangeEnd != 0:
+				for id := begin; id <= vid && id != 0; id++ {
+					bv.ID = id
+					vlans = append(vlans, bv)
+				}
+			default:
+				bv.ID = vid
+				vlans = append(vlans, bv)
+			}
+		}
+
+		// Attributes are padded to 4 byte alignment.
+		l = (l + 3) &^ 3
+		if l > len(b) {
+			break
+		}
+		b = b[l:]
+	}
+
+	return vlans
+}
@@ -0,0 +1,44 @@
+//go:build linux
+
+package host
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+func TestParseBridgeVLANs(t *testing.T) {
+	attr := func(typ, flags, vid uint16) []byte {
+		b := make([]byte, 8)
+		binary.NativeEndian.PutUint16(b[0:2], 8)
+		binary.NativeEndian.PutUint16(b[2:4], typ)
+		binary.NativeEndian.PutUint16(b[4:6], flags)
+		binary.NativeEndian.PutUint16(b[6:8], vid)
+		return b
+	}
+
+	var b []byte
+	for _, a := range [][]byte{
+		attr(iflaBridgeVLANInfo, bridgeVLANInfoPVID|bridgeVLANInfoUntagged, 1),
+		// Unrelated attribute.
+		attr(1, 0, 0),
+		attr(iflaBridgeVLANInfo, bridgeVLANInfoRangeBeg, 10),
+		attr(iflaBridgeVLANInfo, bridgeVLANInfoRangeEnd, 12),
+		// Truncated attribute.
+		{0xff, 0xff},
+	} {
+		b = append(b, a...)
+	}
+
+	want := []BridgeVLAN{
+		{ID: 1, PVID: true, Untagged: true},
+		{ID: 10},
+		{ID: 11},
+		{ID: 12},
+	}
+
+	if got := parseBridgeVLANs(b); !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected BridgeVLANs:\n- want: %v\n-  got: %v", want, got)
+	}
+}
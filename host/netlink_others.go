@@ -0,0 +1,17 @@
+//go:build !linux
+
+package host
+
+// RTNetlink returns a Netlink which retrieves VLAN configuration from the
+// kernel using rtnetlink.  On platforms other than Linux, the Netlink always
+// returns ErrNotSupported.
+func RTNetlink() Netlink {
+	return rtnetlink{}
+}
+
+type rtnetlink struct{}
+
+// BridgeVLANs implements Netlink.
+func (rtnetlink) BridgeVLANs(_ string) ([]BridgeVLAN, error) {
+	return nil, ErrNotSupported
+}
@@ -0,0 +1,191 @@
+package host
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mdlayher/lldp"
+	"github.com/mdlayher/lldp/internal/text"
+)
+
+// A BridgeVLAN is a VLAN configured on a bridge port when VLAN filtering is
+// enabled on its bridge.
+type BridgeVLAN struct {
+	// ID specifies the VLAN ID.
+	ID uint16
+
+	// PVID indicates if this VLAN is the port VLAN ID, which is assigned
+	// to untagged frames received on the port.
+	PVID bool
+
+	// Untagged indicates if frames in this VLAN are transmitted untagged.
+	Untagged bool
+}
+
+// A Netlink retrieves VLAN configuration from the kernel.  It is an
+// interface so that tests may substitute a fake implementation.
+type Netlink interface {
+	// BridgeVLANs returns the VLANs configured on a bridge port.  If the
+	// interface is not a port of a VLAN filtering bridge, it returns no
+	// VLANs and no error.
+	BridgeVLANs(ifname string) ([]BridgeVLAN, error)
+}
+
+// A VLAN is a VLAN of which a port is a member.
+type VLAN struct {
+	ID   uint16
+	Name string
+}
+
+// PortVLANs describes the VLAN configuration of a port.
+type PortVLANs struct {
+	// PVID specifies the port VLAN ID, or 0 if none is configured.
+	PVID uint16
+
+	// VLANs specifies the VLANs of which the port is a member, in order
+	// of VLAN ID.
+	VLANs []VLAN
+}
+
+// ReadVLANs reads the VLAN configuration of a port, using the 802.1Q VLAN
+// subinterfaces listed in /proc/net/vlan/config, and the bridge VLAN
+// filtering state reported by nl.
+//
+// VLANs carried by a VLAN subinterface are named after that subinterface.
+// Other VLANs are named "vlanN".  If nl is nil or returns ErrNotSupported,
+// only VLAN subinterfaces are considered.
+func ReadVLANs(fsys fs.FS, nl Netlink, ifname string) (*PortVLANs, error) {
+	subs, err := vlanSubinterfaces(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[uint16]string)
+	for _, s := range subs {
+		if s.parent == ifname {
+			names[s.id] = s.name
+		}
+	}
+
+	var pv PortVLANs
+	if nl != nil {
+		bvs, err := nl.BridgeVLANs(ifname)
+		if err != nil && !errors.Is(err, ErrNotSupported) {
+			return nil, fmt.Errorf("host: failed to get bridge VLANs of %q: %w", ifname, err)
+		}
+
+		for _, bv := range bvs {
+			if bv.PVID {
+				pv.PVID = bv.ID
+			}
+			if _, ok := names[bv.ID]; !ok {
+				names[bv.ID] = "vlan" + strconv.Itoa(int(bv.ID))
+			}
+		}
+	}
+
+	for id, name := range names {
+		pv.VLANs = append(pv.VLANs, VLAN{
+			ID:   id,
+			Name: name,
+		})
+	}
+
+	sort.Slice(pv.VLANs, func(i, j int) bool {
+		return pv.VLANs[i].ID < pv.VLANs[j].ID
+	})
+
+	return &pv, nil
+}
+
+// TLVs produces the IEEE 802.1 Port VLAN ID, Port and Protocol VLAN ID, and
+// VLAN Name TLVs describing a port.  Linux does not classify frames into
+// VLANs by protocol, so the Port and Protocol VLAN ID TLV always reports that
+// protocol VLANs are unsupported.  VLAN names longer than
+// lldp.VLANNameLengthMax bytes are truncated without splitting a multi-byte
+// UTF-8 sequence.
+func (pv *PortVLANs) TLVs() []*lldp.TLV {
+	var ms []interface{ MarshalBinary() ([]byte, error) }
+	ms = append(ms,
+		&lldp.PortVLANID{ID: pv.PVID},
+		&lldp.PortProtocolVLANID{},
+	)
+
+	for _, v := range pv.VLANs {
+		ms = append(ms, &lldp.VLANName{
+			ID:   v.ID,
+			Name: text.Truncate(v.Name, lldp.VLANNameLengthMax),
+		})
+	}
+
+	tt := make([]*lldp.TLV, 0, len(ms))
+	for _, m := range ms {
+		b, err := m.MarshalBinary()
+		if err != nil {
+			// Out of range VLAN ID.
+			continue
+		}
+
+		tt = append(tt, &lldp.TLV{
+			Type:   lldp.TLVTypeOrganizationSpecific,
+			Length: uint16(len(b)),
+			Value:  b,
+		})
+	}
+
+	return tt
+}
+
+// A vlanSubinterface is an 802.1Q VLAN subinterface.
+type vlanSubinterface struct {
+	name   string
+	id     uint16
+	parent string
+}
+
+// vlanSubinterfaces parses /proc/net/vlan/config.  If the 8021q module is
+// not loaded, it returns no subinterfaces.
+func vlanSubinterfaces(fsys fs.FS) ([]vlanSubinterface, error) {
+	b, err := fs.ReadFile(fsys, "proc/net/vlan/config")
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The file begins with two header lines, followed by one line per
+	// subinterface:
+	//   eth0.100       | 100  | eth0
+	var subs []vlanSubinterface
+	s := bufio.NewScanner(bytes.NewReader(b))
+	for n := 0; s.Scan(); n++ {
+		if n < 2 {
+			continue
+		}
+
+		fields := strings.Split(s.Text(), "|")
+		if len(fields) != 3 {
+			continue
+		}
+
+		id, err := strconv.ParseUint(strings.TrimSpace(fields[1]), 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("host: invalid VLAN ID in %q: %w", s.Text(), err)
+		}
+
+		subs = append(subs, vlanSubinterface{
+			name:   strings.TrimSpace(fields[0]),
+			id:     uint16(id),
+			parent: strings.TrimSpace(fields[2]),
+		})
+	}
+
+	return subs, s.Err()
+}
@@ -0,0 +1,203 @@
+package host
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"unicode/utf8"
+
+	"github.com/mdlayher/lldp"
+)
+
+const testVLANConfig = `VLAN Dev name	 | VLAN ID
+Name-Type: VLAN_NAME_TYPE_RAW_PLUS_VID_NO_PAD
+eth0.100       | 100  | eth0
+eth0.voice     | 20  | eth0
+eth1.300       | 300  | eth1
+`
+
+func TestReadVLANs(t *testing.T) {
+	errBroken := errors.New("broken")
+
+	var tests = []struct {
+		desc   string
+		config string
+		nl     Netlink
+		pv     *PortVLANs
+		err    error
+	}{
+		{
+			desc: "no 8021q module",
+			pv:   &PortVLANs{},
+		},
+		{
+			desc:   "subinterfaces only",
+			config: testVLANConfig,
+			pv: &PortVLANs{
+				VLANs: []VLAN{
+					{ID: 20, Name: "eth0.voice"},
+					{ID: 100, Name: "eth0.100"},
+				},
+			},
+		},
+		{
+			desc:   "netlink not supported",
+			config: testVLANConfig,
+			nl:     &testNetlink{err: ErrNotSupported},
+			pv: &PortVLANs{
+				VLANs: []VLAN{
+					{ID: 20, Name: "eth0.voice"},
+					{ID: 100, Name: "eth0.100"},
+				},
+			},
+		},
+		{
+			desc: "netlink error",
+			nl:   &testNetlink{err: errBroken},
+			err:  errBroken,
+		},
+		{
+			desc:   "bridge VLANs and subinterfaces",
+			config: testVLANConfig,
+			nl: &testNetlink{vlans: map[string][]BridgeVLAN{
+				"eth0": {
+					{ID: 1, PVID: true, Untagged: true},
+					{ID: 100},
+					{ID: 10},
+				},
+				"eth1": {{ID: 5, PVID: true}},
+			}},
+			pv: &PortVLANs{
+				PVID: 1,
+				VLANs: []VLAN{
+					{ID: 1, Name: "vlan1"},
+					{ID: 10, Name: "vlan10"},
+					{ID: 20, Name: "eth0.voice"},
+					{ID: 100, Name: "eth0.100"},
+				},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		fsys := testFS()
+		if tt.config != "" {
+			fsys["proc/net/vlan/config"] = &fstest.MapFile{Data: []byte(tt.config)}
+		}
+
+		pv, err := ReadVLANs(fsys, tt.nl, "eth0")
+		if err != nil {
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("unexpected error: %v != %v", tt.err, err)
+			}
+
+			continue
+		}
+
+		if want, got := tt.pv, pv; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected PortVLANs:\n- want: %v\n-  got: %v", want, got)
+		}
+	}
+}
+
+func TestCollectorFrameVLANs(t *testing.T) {
+	fsys := testFS()
+	fsys["proc/net/vlan/config"] = &fstest.MapFile{Data: []byte(testVLANConfig)}
+
+	c := &Collector{
+		FS:    fsys,
+		Addrs: noAddrs,
+		VLANs: true,
+		Netlink: &testNetlink{vlans: map[string][]BridgeVLAN{
+			"eth1": {
+				{ID: 5, PVID: true, Untagged: true},
+				{ID: 4000},
+			},
+		}},
+	}
+
+	f, err := c.Frame("eth1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, ok := f.PortVLANID()
+	if !ok {
+		t.Fatal("expected Port VLAN ID TLV in Frame")
+	}
+	if want, got := uint16(5), p.ID; want != got {
+		t.Fatalf("unexpected port VLAN ID: %d != %d", want, got)
+	}
+
+	if want, got := []*lldp.PortProtocolVLANID{{}}, f.PortProtocolVLANIDs(); !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected PortProtocolVLANIDs:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	want := []*lldp.VLANName{
+		{ID: 5, Name: "vlan5"},
+		{ID: 300, Name: "eth1.300"},
+		{ID: 4000, Name: "vlan4000"},
+	}
+
+	if got := f.VLANNames(); !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected VLANNames:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+func TestPortVLANsTLVsTruncateName(t *testing.T) {
+	var tests = []struct {
+		desc string
+		name string
+		want string
+	}{
+		{
+			desc: "ASCII",
+			name: strings.Repeat("x", lldp.VLANNameLengthMax+8),
+			want: strings.Repeat("x", lldp.VLANNameLengthMax),
+		},
+		{
+			desc: "multi-byte rune at limit",
+			name: strings.Repeat("x", lldp.VLANNameLengthMax-1) + "été",
+			want: strings.Repeat("x", lldp.VLANNameLengthMax-1),
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		pv := &PortVLANs{
+			VLANs: []VLAN{{ID: 10, Name: tt.name}},
+		}
+
+		f := &lldp.Frame{Optional: pv.TLVs()}
+		vv := f.VLANNames()
+		if len(vv) != 1 {
+			t.Fatalf("expected one VLAN name, but got %d", len(vv))
+		}
+
+		if want, got := tt.want, vv[0].Name; want != got {
+			t.Fatalf("unexpected VLAN name: %q != %q", want, got)
+		}
+		if !utf8.ValidString(vv[0].Name) {
+			t.Fatalf("VLAN name is not valid UTF-8: %q", vv[0].Name)
+		}
+	}
+}
+
+// testNetlink is a Netlink which returns fixed VLANs per interface.
+type testNetlink struct {
+	vlans map[string][]BridgeVLAN
+	err   error
+}
+
+func (nl *testNetlink) BridgeVLANs(ifname string) ([]BridgeVLAN, error) {
+	if nl.err != nil {
+		return nil, nl.err
+	}
+
+	return nl.vlans[ifname], nil
+}
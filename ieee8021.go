@@ -0,0 +1,265 @@
+package lldp
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// IEEE 802.1 organizationally specific subtypes for VLAN TLVs.
+const (
+	IEEE8021SubtypePortVLANID         uint8 = 1
+	IEEE8021SubtypePortProtocolVLANID uint8 = 2
+	IEEE8021SubtypeVLANName           uint8 = 3
+)
+
+const (
+	// VLANIDMax is the maximum possible value for a VLAN ID carried in an
+	// IEEE 802.1 VLAN TLV.
+	VLANIDMax = 4094
+
+	// VLANNameLengthMax is the maximum length of the name carried in
+	// a VLANName.
+	VLANNameLengthMax = 32
+)
+
+// Port and protocol VLAN flag bits carried in a PortProtocolVLANID.
+const (
+	ppvidSupported = 1 << 1
+	ppvidEnabled   = 1 << 2
+)
+
+// A PortVLANID is a structure parsed from an IEEE 802.1 Port VLAN ID
+// organizationally specific TLV.  It specifies the VLAN ID assigned to
+// untagged and priority tagged frames received on a port.
+type PortVLANID struct {
+	// ID specifies the port VLAN ID, or 0 if the port does not support
+	// port-based VLANs.
+	ID uint16
+}
+
+// MarshalBinary allocates a byte slice and marshals a PortVLANID into the
+// binary form of an organizationally specific TLV value.
+//
+// If the VLAN ID is greater than VLANIDMax, ErrInvalidTLV is returned.
+func (p *PortVLANID) MarshalBinary() ([]byte, error) {
+	if p.ID > VLANIDMax {
+		return nil, ErrInvalidTLV
+	}
+
+	// 2 bytes: port VLAN ID
+	info := make([]byte, 2)
+	binary.BigEndian.PutUint16(info, p.ID)
+
+	return (&OrganizationSpecific{
+		OUI:     OUIIEEE8021,
+		Subtype: IEEE8021SubtypePortVLANID,
+		Info:    info,
+	}).MarshalBinary()
+}
+
+// UnmarshalBinary unmarshals an organizationally specific TLV value into
+// a PortVLANID.
+//
+// If the byte slice does not contain enough data to unmarshal a valid
+// PortVLANID, io.ErrUnexpectedEOF is returned.
+//
+// If the byte slice does not carry the IEEE 802.1 OUI and Port VLAN ID
+// subtype, ErrInvalidTLV is returned.
+func (p *PortVLANID) UnmarshalBinary(b []byte) error {
+	info, err := organizationSpecificInfo(b, OUIIEEE8021, IEEE8021SubtypePortVLANID)
+	if err != nil {
+		return err
+	}
+	if len(info) != 2 {
+		return io.ErrUnexpectedEOF
+	}
+
+	p.ID = binary.BigEndian.Uint16(info)
+	return nil
+}
+
+// A PortProtocolVLANID is a structure parsed from an IEEE 802.1 Port and
+// Protocol VLAN ID organizationally specific TLV.  It specifies a VLAN ID
+// assigned to frames of a particular protocol received on a port.
+type PortProtocolVLANID struct {
+	// Supported and Enabled indicate if the port supports port and
+	// protocol VLANs, and if one is currently enabled.
+	Supported bool
+	Enabled   bool
+
+	// ID specifies the port and protocol VLAN ID, or 0 if none is
+	// known.
+	ID uint16
+}
+
+// MarshalBinary allocates a byte slice and marshals a PortProtocolVLANID
+// into the binary form of an organizationally specific TLV value.
+//
+// If the VLAN ID is greater than VLANIDMax, ErrInvalidTLV is returned.
+func (p *PortProtocolVLANID) MarshalBinary() ([]byte, error) {
+	if p.ID > VLANIDMax {
+		return nil, ErrInvalidTLV
+	}
+
+	// 1 byte: flags
+	// 2 bytes: port and protocol VLAN ID
+	info := make([]byte, 3)
+	if p.Supported {
+		info[0] |= ppvidSupported
+	}
+	if p.Enabled {
+		info[0] |= ppvidEnabled
+	}
+	binary.BigEndian.PutUint16(info[1:3], p.ID)
+
+	return (&OrganizationSpecific{
+		OUI:     OUIIEEE8021,
+		Subtype: IEEE8021SubtypePortProtocolVLANID,
+		Info:    info,
+	}).MarshalBinary()
+}
+
+// UnmarshalBinary unmarshals an organizationally specific TLV value into
+// a PortProtocolVLANID.
+//
+// If the byte slice does not contain enough data to unmarshal a valid
+// PortProtocolVLANID, io.ErrUnexpectedEOF is returned.
+//
+// If the byte slice does not carry the IEEE 802.1 OUI and Port and Protocol
+// VLAN ID subtype, ErrInvalidTLV is returned.
+func (p *PortProtocolVLANID) UnmarshalBinary(b []byte) error {
+	info, err := organizationSpecificInfo(b, OUIIEEE8021, IEEE8021SubtypePortProtocolVLANID)
+	if err != nil {
+		return err
+	}
+	if len(info) != 3 {
+		return io.ErrUnexpectedEOF
+	}
+
+	p.Supported = info[0]&ppvidSupported != 0
+	p.Enabled = info[0]&ppvidEnabled != 0
+	p.ID = binary.BigEndian.Uint16(info[1:3])
+
+	return nil
+}
+
+// A VLANName is a structure parsed from an IEEE 802.1 VLAN Name
+// organizationally specific TLV.  It associates a name with a VLAN of
+// which a port is a member.
+type VLANName struct {
+	// ID specifies the VLAN ID.
+	ID uint16
+
+	// Name specifies the name of the VLAN, which may be no longer than
+	// VLANNameLengthMax bytes.
+	Name string
+}
+
+// MarshalBinary allocates a byte slice and marshals a VLANName into the
+// binary form of an organizationally specific TLV value.
+//
+// If the VLAN ID is greater than VLANIDMax or the name is longer than
+// VLANNameLengthMax bytes, ErrInvalidTLV is returned.
+func (v *VLANName) MarshalBinary() ([]byte, error) {
+	if v.ID > VLANIDMax || len(v.Name) > VLANNameLengthMax {
+		return nil, ErrInvalidTLV
+	}
+
+	// 2 bytes: VLAN ID
+	// 1 byte: VLAN name length
+	// N bytes: VLAN name
+	info := make([]byte, 3+len(v.Name))
+	binary.BigEndian.PutUint16(info[0:2], v.ID)
+	info[2] = byte(len(v.Name))
+	copy(info[3:], v.Name)
+
+	return (&OrganizationSpecific{
+		OUI:     OUIIEEE8021,
+		Subtype: IEEE8021SubtypeVLANName,
+		Info:    info,
+	}).MarshalBinary()
+}
+
+// UnmarshalBinary unmarshals an organizationally specific TLV value into
+// a VLANName.
+//
+// If the byte slice does not contain enough data to unmarshal a valid
+// VLANName, io.ErrUnexpectedEOF is returned.
+//
+// If the byte slice does not carry the IEEE 802.1 OUI and VLAN Name
+// subtype, or the name is longer than VLANNameLengthMax bytes,
+// ErrInvalidTLV is returned.
+func (v *VLANName) UnmarshalBinary(b []byte) error {
+	info, err := organizationSpecificInfo(b, OUIIEEE8021, IEEE8021SubtypeVLANName)
+	if err != nil {
+		return err
+	}
+	if len(info) < 3 {
+		return io.ErrUnexpectedEOF
+	}
+
+	n := int(info[2])
+	if n > VLANNameLengthMax {
+		return ErrInvalidTLV
+	}
+	if len(info[3:]) != n {
+		return io.ErrUnexpectedEOF
+	}
+
+	v.ID = binary.BigEndian.Uint16(info[0:2])
+	v.Name = string(info[3:])
+
+	return nil
+}
+
+// PortVLANID returns the IEEE 802.1 port VLAN ID carried in a Frame's
+// optional TLVs.
+//
+// If no Port VLAN ID TLV is present, PortVLANID returns nil and false.  Any
+// errors encountered while unmarshaling the TLV are also reported as false.
+func (f *Frame) PortVLANID() (*PortVLANID, bool) {
+	tt := f.OrganizationSpecific(OUIIEEE8021, IEEE8021SubtypePortVLANID)
+	if len(tt) == 0 {
+		return nil, false
+	}
+
+	p := new(PortVLANID)
+	if err := p.UnmarshalBinary(tt[0].Value); err != nil {
+		return nil, false
+	}
+
+	return p, true
+}
+
+// PortProtocolVLANIDs returns each IEEE 802.1 port and protocol VLAN ID
+// carried in a Frame's optional TLVs.  TLVs which cannot be unmarshaled are
+// skipped.
+func (f *Frame) PortProtocolVLANIDs() []*PortProtocolVLANID {
+	var pp []*PortProtocolVLANID
+	for _, t := range f.OrganizationSpecific(OUIIEEE8021, IEEE8021SubtypePortProtocolVLANID) {
+		p := new(PortProtocolVLANID)
+		if err := p.UnmarshalBinary(t.Value); err != nil {
+			continue
+		}
+
+		pp = append(pp, p)
+	}
+
+	return pp
+}
+
+// VLANNames returns each IEEE 802.1 VLAN name carried in a Frame's optional
+// TLVs.  TLVs which cannot be unmarshaled are skipped.
+func (f *Frame) VLANNames() []*VLANName {
+	var vv []*VLANName
+	for _, t := range f.OrganizationSpecific(OUIIEEE8021, IEEE8021SubtypeVLANName) {
+		v := new(VLANName)
+		if err := v.UnmarshalBinary(t.Value); err != nil {
+			continue
+		}
+
+		vv = append(vv, v)
+	}
+
+	return vv
+}
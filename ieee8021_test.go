@@ -0,0 +1,222 @@
+package lldp
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestPortVLANIDUnmarshalBinary(t *testing.T) {
+	var tests = []struct {
+		desc string
+		b    []byte
+		p    *PortVLANID
+		err  error
+	}{
+		{
+			desc: "nil buffer",
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "wrong subtype",
+			b:    []byte{0x00, 0x80, 0xc2, 2, 0x00, 0x64},
+			err:  ErrInvalidTLV,
+		},
+		{
+			desc: "short information string",
+			b:    []byte{0x00, 0x80, 0xc2, 1, 0x00},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "OK",
+			b:    []byte{0x00, 0x80, 0xc2, 1, 0x00, 0x64},
+			p:    &PortVLANID{ID: 100},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		p := new(PortVLANID)
+		if err := p.UnmarshalBinary(tt.b); err != nil {
+			if want, got := tt.err, err; want != got {
+				t.Fatalf("unexpected error:\n- want: %v\n-  got: %v", want, got)
+			}
+
+			continue
+		}
+
+		if want, got := tt.p, p; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected PortVLANID:\n- want: %v\n-  got: %v", want, got)
+		}
+
+		b, err := p.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want, got := tt.b, b; !bytes.Equal(want, got) {
+			t.Fatalf("unexpected PortVLANID bytes:\n- want: %v\n-  got: %v", want, got)
+		}
+
+		fp, ok := orgFrame(b).PortVLANID()
+		if !ok {
+			t.Fatal("expected Port VLAN ID TLV in Frame")
+		}
+
+		if want, got := tt.p, fp; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected Frame PortVLANID:\n- want: %v\n-  got: %v", want, got)
+		}
+	}
+}
+
+func TestPortProtocolVLANIDUnmarshalBinary(t *testing.T) {
+	var tests = []struct {
+		desc string
+		b    []byte
+		p    *PortProtocolVLANID
+		err  error
+	}{
+		{
+			desc: "nil buffer",
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "short information string",
+			b:    []byte{0x00, 0x80, 0xc2, 2, 0x06, 0x00},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "not supported",
+			b:    []byte{0x00, 0x80, 0xc2, 2, 0x00, 0x00, 0x00},
+			p:    &PortProtocolVLANID{},
+		},
+		{
+			desc: "supported and enabled",
+			b:    []byte{0x00, 0x80, 0xc2, 2, 0x06, 0x00, 0xc8},
+			p: &PortProtocolVLANID{
+				Supported: true,
+				Enabled:   true,
+				ID:        200,
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		p := new(PortProtocolVLANID)
+		if err := p.UnmarshalBinary(tt.b); err != nil {
+			if want, got := tt.err, err; want != got {
+				t.Fatalf("unexpected error:\n- want: %v\n-  got: %v", want, got)
+			}
+
+			continue
+		}
+
+		if want, got := tt.p, p; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected PortProtocolVLANID:\n- want: %v\n-  got: %v", want, got)
+		}
+
+		b, err := p.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want, got := tt.b, b; !bytes.Equal(want, got) {
+			t.Fatalf("unexpected PortProtocolVLANID bytes:\n- want: %v\n-  got: %v", want, got)
+		}
+
+		if want, got := []*PortProtocolVLANID{tt.p}, orgFrame(b).PortProtocolVLANIDs(); !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected Frame PortProtocolVLANIDs:\n- want: %v\n-  got: %v", want, got)
+		}
+	}
+}
+
+func TestVLANNameUnmarshalBinary(t *testing.T) {
+	var tests = []struct {
+		desc string
+		b    []byte
+		v    *VLANName
+		err  error
+	}{
+		{
+			desc: "nil buffer",
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "short name",
+			b:    []byte{0x00, 0x80, 0xc2, 3, 0x00, 0x64, 4, 'a', 'b'},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "name too long",
+			b:    append([]byte{0x00, 0x80, 0xc2, 3, 0x00, 0x64, 33}, make([]byte, 33)...),
+			err:  ErrInvalidTLV,
+		},
+		{
+			desc: "OK",
+			b:    []byte{0x00, 0x80, 0xc2, 3, 0x00, 0x64, 4, 'v', 'o', 'i', 'p'},
+			v: &VLANName{
+				ID:   100,
+				Name: "voip",
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		v := new(VLANName)
+		if err := v.UnmarshalBinary(tt.b); err != nil {
+			if want, got := tt.err, err; want != got {
+				t.Fatalf("unexpected error:\n- want: %v\n-  got: %v", want, got)
+			}
+
+			continue
+		}
+
+		if want, got := tt.v, v; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected VLANName:\n- want: %v\n-  got: %v", want, got)
+		}
+
+		b, err := v.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want, got := tt.b, b; !bytes.Equal(want, got) {
+			t.Fatalf("unexpected VLANName bytes:\n- want: %v\n-  got: %v", want, got)
+		}
+
+		if want, got := []*VLANName{tt.v}, orgFrame(b).VLANNames(); !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected Frame VLANNames:\n- want: %v\n-  got: %v", want, got)
+		}
+	}
+}
+
+func TestVLANMarshalBinaryInvalid(t *testing.T) {
+	for _, m := range []interface{ MarshalBinary() ([]byte, error) }{
+		&PortVLANID{ID: VLANIDMax + 1},
+		&PortProtocolVLANID{ID: VLANIDMax + 1},
+		&VLANName{ID: VLANIDMax + 1},
+		&VLANName{ID: 1, Name: string(make([]byte, VLANNameLengthMax+1))},
+	} {
+		if _, err := m.MarshalBinary(); err != ErrInvalidTLV {
+			t.Fatalf("expected ErrInvalidTLV for %#v, but got: %v", m, err)
+		}
+	}
+}
+
+// orgFrame produces a Frame carrying a single organizationally specific TLV
+// with value b.
+func orgFrame(b []byte) *Frame {
+	return &Frame{
+		Optional: []*TLV{{
+			Type:   TLVTypeOrganizationSpecific,
+			Length: uint16(len(b)),
+			Value:  b,
+		}},
+	}
+}
@@ -0,0 +1,219 @@
+package lldp
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// IEEE 802.3 organizationally specific subtypes.
+const (
+	IEEE8023SubtypeMACPHY           uint8 = 1
+	IEEE8023SubtypeMaximumFrameSize uint8 = 4
+)
+
+// A PMDCapability is a bit mask of physical media dependent (PMD)
+// auto-negotiation capabilities, as carried in a MACPHY.
+type PMDCapability uint16
+
+// List of valid PMDCapability values.
+const (
+	PMDCapabilityOther           PMDCapability = 1 << 15
+	PMDCapability10BaseT         PMDCapability = 1 << 14
+	PMDCapability10BaseTFD       PMDCapability = 1 << 13
+	PMDCapability100BaseT4       PMDCapability = 1 << 12
+	PMDCapability100BaseTX       PMDCapability = 1 << 11
+	PMDCapability100BaseTXFD     PMDCapability = 1 << 10
+	PMDCapability100BaseT2       PMDCapability = 1 << 9
+	PMDCapability100BaseT2FD     PMDCapability = 1 << 8
+	PMDCapabilityPause           PMDCapability = 1 << 7
+	PMDCapabilityAsymmetricPause PMDCapability = 1 << 6
+	PMDCapabilitySymmetricPause  PMDCapability = 1 << 5
+	PMDCapabilityAsymSymPause    PMDCapability = 1 << 4
+	PMDCapability1000BaseX       PMDCapability = 1 << 3
+	PMDCapability1000BaseXFD     PMDCapability = 1 << 2
+	PMDCapability1000BaseT       PMDCapability = 1 << 1
+	PMDCapability1000BaseTFD     PMDCapability = 1 << 0
+)
+
+// A MAUType is an IANA dot3MauType value, used to indicate the operational
+// medium attachment unit type of a link.
+type MAUType uint16
+
+// List of commonly used MAUType values.
+const (
+	MAUTypeUnknown     MAUType = 0
+	MAUType10BaseTHD   MAUType = 10
+	MAUType10BaseTFD   MAUType = 11
+	MAUType100BaseTXHD MAUType = 15
+	MAUType100BaseTXFD MAUType = 16
+	MAUType100BaseFXHD MAUType = 17
+	MAUType100BaseFXFD MAUType = 18
+	MAUType1000BaseXHD MAUType = 21
+	MAUType1000BaseXFD MAUType = 22
+	MAUType1000BaseTHD MAUType = 29
+	MAUType1000BaseTFD MAUType = 30
+	MAUType10GBaseX    MAUType = 31
+	MAUType10GBaseR    MAUType = 33
+	MAUType10GBaseT    MAUType = 54
+)
+
+// Auto-negotiation support and status bits carried in a MACPHY.
+const (
+	macphyAutonegSupported = 1 << 0
+	macphyAutonegEnabled   = 1 << 1
+)
+
+// A MACPHY is a structure parsed from an IEEE 802.3 MAC/PHY
+// Configuration/Status organizationally specific TLV.  It describes the
+// duplex and bit rate capabilities of a link, and its current settings.
+type MACPHY struct {
+	// AutonegSupported and AutonegEnabled indicate if auto-negotiation
+	// is supported and currently enabled.
+	AutonegSupported bool
+	AutonegEnabled   bool
+
+	// Advertised specifies the capabilities advertised by
+	// auto-negotiation.
+	Advertised PMDCapability
+
+	// MAUType specifies the operational medium attachment unit type,
+	// which indicates the current speed and duplex of a link.
+	MAUType MAUType
+}
+
+// MarshalBinary allocates a byte slice and marshals a MACPHY into the
+// binary form of an organizationally specific TLV value.
+//
+// MarshalBinary never returns an error.
+func (m *MACPHY) MarshalBinary() ([]byte, error) {
+	// 1 byte: auto-negotiation support/status
+	// 2 bytes: PMD auto-negotiation advertised capability
+	// 2 bytes: operational MAU type
+	info := make([]byte, 5)
+	if m.AutonegSupported {
+		info[0] |= macphyAutonegSupported
+	}
+	if m.AutonegEnabled {
+		info[0] |= macphyAutonegEnabled
+	}
+	binary.BigEndian.PutUint16(info[1:3], uint16(m.Advertised))
+	binary.BigEndian.PutUint16(info[3:5], uint16(m.MAUType))
+
+	return (&OrganizationSpecific{
+		OUI:     OUIIEEE8023,
+		Subtype: IEEE8023SubtypeMACPHY,
+		Info:    info,
+	}).MarshalBinary()
+}
+
+// UnmarshalBinary unmarshals an organizationally specific TLV value into
+// a MACPHY.
+//
+// If the byte slice does not contain enough data to unmarshal a valid
+// MACPHY, io.ErrUnexpectedEOF is returned.
+//
+// If the byte slice does not carry the IEEE 802.3 OUI and MAC/PHY subtype,
+// ErrInvalidTLV is returned.
+func (m *MACPHY) UnmarshalBinary(b []byte) error {
+	info, err := organizationSpecificInfo(b, OUIIEEE8023, IEEE8023SubtypeMACPHY)
+	if err != nil {
+		return err
+	}
+	if len(info) != 5 {
+		return io.ErrUnexpectedEOF
+	}
+
+	m.AutonegSupported = info[0]&macphyAutonegSupported != 0
+	m.AutonegEnabled = info[0]&macphyAutonegEnabled != 0
+	m.Advertised = PMDCapability(binary.BigEndian.Uint16(info[1:3]))
+	m.MAUType = MAUType(binary.BigEndian.Uint16(info[3:5]))
+
+	return nil
+}
+
+// MACPHY returns the IEEE 802.3 MAC/PHY Configuration/Status information
+// carried in a Frame's optional TLVs.
+//
+// If no MAC/PHY TLV is present, MACPHY returns nil and false.  Any errors
+// encountered while unmarshaling the TLV are also reported as false.
+func (f *Frame) MACPHY() (*MACPHY, bool) {
+	tt := f.OrganizationSpecific(OUIIEEE8023, IEEE8023SubtypeMACPHY)
+	if len(tt) == 0 {
+		return nil, false
+	}
+
+	m := new(MACPHY)
+	if err := m.UnmarshalBinary(tt[0].Value); err != nil {
+		return nil, false
+	}
+
+	return m, true
+}
+
+// A MaximumFrameSize is a structure parsed from an IEEE 802.3 Maximum Frame
+// Size organizationally specific TLV.  It indicates the maximum frame size
+// supported by a port's MAC and PHY, which is commonly used to infer its
+// MTU.
+type MaximumFrameSize struct {
+	// Size specifies the maximum frame size in bytes, including the
+	// Ethernet header and frame check sequence.
+	Size uint16
+}
+
+// MarshalBinary allocates a byte slice and marshals a MaximumFrameSize into
+// the binary form of an organizationally specific TLV value.
+//
+// MarshalBinary never returns an error.
+func (m *MaximumFrameSize) MarshalBinary() ([]byte, error) {
+	// 2 bytes: maximum frame size
+	info := make([]byte, 2)
+	binary.BigEndian.PutUint16(info, m.Size)
+
+	return (&OrganizationSpecific{
+		OUI:     OUIIEEE8023,
+		Subtype: IEEE8023SubtypeMaximumFrameSize,
+		Info:    info,
+	}).MarshalBinary()
+}
+
+// UnmarshalBinary unmarshals an organizationally specific TLV value into
+// a MaximumFrameSize.
+//
+// If the byte slice does not contain enough data to unmarshal a valid
+// MaximumFrameSize, io.ErrUnexpectedEOF is returned.
+//
+// If the byte slice does not carry the IEEE 802.3 OUI and Maximum Frame
+// Size subtype, ErrInvalidTLV is returned.
+func (m *MaximumFrameSize) UnmarshalBinary(b []byte) error {
+	info, err := organizationSpecificInfo(b, OUIIEEE8023, IEEE8023SubtypeMaximumFrameSize)
+	if err != nil {
+		return err
+	}
+	if len(info) != 2 {
+		return io.ErrUnexpectedEOF
+	}
+
+	m.Size = binary.BigEndian.Uint16(info)
+
+	return nil
+}
+
+// MaximumFrameSize returns the IEEE 802.3 Maximum Frame Size information
+// carried in a Frame's optional TLVs.
+//
+// If no Maximum Frame Size TLV is present, MaximumFrameSize returns nil and
+// false.  Any errors encountered while unmarshaling the TLV are also
+// reported as false.
+func (f *Frame) MaximumFrameSize() (*MaximumFrameSize, bool) {
+	tt := f.OrganizationSpecific(OUIIEEE8023, IEEE8023SubtypeMaximumFrameSize)
+	if len(tt) == 0 {
+		return nil, false
+	}
+
+	m := new(MaximumFrameSize)
+	if err := m.UnmarshalBinary(tt[0].Value); err != nil {
+		return nil, false
+	}
+
+	return m, true
+}
@@ -0,0 +1,164 @@
+package lldp
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestMACPHYUnmarshalBinary(t *testing.T) {
+	var tests = []struct {
+		desc string
+		b    []byte
+		m    *MACPHY
+		err  error
+	}{
+		{
+			desc: "nil buffer",
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "wrong OUI",
+			b:    []byte{0x00, 0x80, 0xc2, 1, 0x03, 0x6c, 0x01, 0x00, 0x1e},
+			err:  ErrInvalidTLV,
+		},
+		{
+			desc: "short information string",
+			b:    []byte{0x00, 0x12, 0x0f, 1, 0x03, 0x6c, 0x01},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "1000BASE-T full duplex, auto-negotiation enabled",
+			b:    []byte{0x00, 0x12, 0x0f, 1, 0x03, 0x6c, 0x01, 0x00, 0x1e},
+			m: &MACPHY{
+				AutonegSupported: true,
+				AutonegEnabled:   true,
+				Advertised: PMDCapability10BaseT | PMDCapability10BaseTFD |
+					PMDCapability100BaseTX | PMDCapability100BaseTXFD |
+					PMDCapability1000BaseTFD,
+				MAUType: MAUType1000BaseTFD,
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		m := new(MACPHY)
+		if err := m.UnmarshalBinary(tt.b); err != nil {
+			if want, got := tt.err, err; want != got {
+				t.Fatalf("unexpected error:\n- want: %v\n-  got: %v", want, got)
+			}
+
+			continue
+		}
+
+		if want, got := tt.m, m; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected MACPHY:\n- want: %v\n-  got: %v", want, got)
+		}
+
+		b, err := m.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want, got := tt.b, b; !bytes.Equal(want, got) {
+			t.Fatalf("unexpected MACPHY bytes:\n- want: %v\n-  got: %v", want, got)
+		}
+
+		f := &Frame{
+			Optional: []*TLV{{
+				Type:   TLVTypeOrganizationSpecific,
+				Length: uint16(len(b)),
+				Value:  b,
+			}},
+		}
+
+		fm, ok := f.MACPHY()
+		if !ok {
+			t.Fatal("expected MAC/PHY TLV in Frame")
+		}
+
+		if want, got := tt.m, fm; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected Frame MACPHY:\n- want: %v\n-  got: %v", want, got)
+		}
+	}
+}
+
+func TestMaximumFrameSizeUnmarshalBinary(t *testing.T) {
+	var tests = []struct {
+		desc string
+		b    []byte
+		m    *MaximumFrameSize
+		err  error
+	}{
+		{
+			desc: "nil buffer",
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "wrong subtype",
+			b:    []byte{0x00, 0x12, 0x0f, 1, 0x05, 0xee},
+			err:  ErrInvalidTLV,
+		},
+		{
+			desc: "short information string",
+			b:    []byte{0x00, 0x12, 0x0f, 4, 0x05},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "1518 bytes",
+			b:    []byte{0x00, 0x12, 0x0f, 4, 0x05, 0xee},
+			m:    &MaximumFrameSize{Size: 1518},
+		},
+		{
+			desc: "jumbo frames",
+			b:    []byte{0x00, 0x12, 0x0f, 4, 0x24, 0x00},
+			m:    &MaximumFrameSize{Size: 9216},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		m := new(MaximumFrameSize)
+		if err := m.UnmarshalBinary(tt.b); err != nil {
+			if want, got := tt.err, err; want != got {
+				t.Fatalf("unexpected error:\n- want: %v\n-  got: %v", want, got)
+			}
+
+			continue
+		}
+
+		if want, got := tt.m, m; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected MaximumFrameSize:\n- want: %v\n-  got: %v", want, got)
+		}
+
+		b, err := m.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want, got := tt.b, b; !bytes.Equal(want, got) {
+			t.Fatalf("unexpected MaximumFrameSize bytes:\n- want: %v\n-  got: %v", want, got)
+		}
+
+		f := &Frame{
+			Optional: []*TLV{{
+				Type:   TLVTypeOrganizationSpecific,
+				Length: uint16(len(b)),
+				Value:  b,
+			}},
+		}
+
+		fm, ok := f.MaximumFrameSize()
+		if !ok {
+			t.Fatal("expected Maximum Frame Size TLV in Frame")
+		}
+
+		if want, got := tt.m, fm; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected Frame MaximumFrameSize:\n- want: %v\n-  got: %v", want, got)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+// Package format formats LLDP chassis IDs, port IDs, and other values for
+// display, so that every package presents them in the same way.
+package format
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
+	"unicode"
+
+	"github.com/mdlayher/lldp"
+)
+
+// Names of chassis ID and port ID subtypes.
+var (
+	chassisIDSubtypes = map[lldp.ChassisIDSubtype]string{
+		lldp.ChassisIDSubtypeChassisComponenent: "chassis-component",
+		lldp.ChassisIDSubtypeInterfaceAlias:     "interface-alias",
+		lldp.ChassisIDSubtypePortComponent:      "port-component",
+		lldp.ChassisIDSubtypeMACAddress:         "mac-address",
+		lldp.ChassisIDSubtypeNetworkAddress:     "network-address",
+		lldp.ChassisIDSubtypeInterfaceName:      "interface-name",
+		lldp.ChassisIDSubtypeLocallyAssigned:    "local",
+	}
+
+	portIDSubtypes = map[lldp.PortIDSubtype]string{
+		lldp.PortIDSubtypeInterfaceAlias:  "interface-alias",
+		lldp.PortIDSubtypePortComponent:   "port-component",
+		lldp.PortIDSubtypeMACAddress:      "mac-address",
+		lldp.PortIDSubtypeNetworkAddress:  "network-address",
+		lldp.PortIDSubtypeInterfaceName:   "interface-name",
+		lldp.PortIDSubtypeAgentCircuitID:  "agent-circuit-id",
+		lldp.PortIDSubtypeLocallyAssigned: "local",
+	}
+)
+
+// ChassisID returns the name of a chassis ID's subtype, such as
+// "mac-address", and its value formatted as by ID.  A nil ChassisID returns
+// empty strings.
+func ChassisID(c *lldp.ChassisID) (subtype, value string) {
+	if c == nil {
+		return "", ""
+	}
+
+	subtype, ok := chassisIDSubtypes[c.Subtype]
+	if !ok {
+		subtype = fmt.Sprintf("unknown-%d", c.Subtype)
+	}
+
+	return subtype, ID(c.Subtype == lldp.ChassisIDSubtypeMACAddress, c.Subtype == lldp.ChassisIDSubtypeNetworkAddress, c.ID)
+}
+
+// PortID returns the name of a port ID's subtype, such as "interface-name",
+// and its value formatted as by ID.  A nil PortID returns empty strings.
+func PortID(p *lldp.PortID) (subtype, value string) {
+	if p == nil {
+		return "", ""
+	}
+
+	subtype, ok := portIDSubtypes[p.Subtype]
+	if !ok {
+		subtype = fmt.Sprintf("unknown-%d", p.Subtype)
+	}
+
+	return subtype, ID(p.Subtype == lldp.PortIDSubtypeMACAddress, p.Subtype == lldp.PortIDSubtypeNetworkAddress, p.ID)
+}
+
+// ChassisIDKey returns a key which identifies a chassis ID by its subtype and
+// raw value, for use in maps.  Unlike the formatted value, the key of each
+// distinct chassis ID is unique.
+func ChassisIDKey(c *lldp.ChassisID) string {
+	return key(uint8(c.Subtype), c.ID)
+}
+
+// PortIDKey returns a key which identifies a port ID by its subtype and raw
+// value, as ChassisIDKey does for chassis IDs.
+func PortIDKey(p *lldp.PortID) string {
+	return key(uint8(p.Subtype), p.ID)
+}
+
+// key concatenates a subtype and raw value.
+func key(subtype uint8, id []byte) string {
+	return string(append([]byte{subtype}, id...))
+}
+
+// ID formats a chassis ID or port ID value as a MAC address, a network
+// address, printable text, or hexadecimal.
+func ID(mac, network bool, b []byte) string {
+	switch {
+	case mac && len(b) == 6:
+		return net.HardwareAddr(b).String()
+	case network && isNetworkAddress(b):
+		return net.IP(b[1:]).String()
+	default:
+		return Text(b)
+	}
+}
+
+// Exact reports whether ID formats b as a value which no other input formats
+// to: a MAC address, a network address, or printable text when b is neither
+// kind of address.  Otherwise, distinct inputs may be formatted identically,
+// such as the printable "0a0b" and the binary 0x0a0b.
+func Exact(mac, network bool, b []byte) bool {
+	switch {
+	case mac:
+		return len(b) == 6
+	case network:
+		// An IPv4-mapped IPv6 address is formatted as an IPv4 address.
+		return isNetworkAddress(b) && (b[0] == 1 || net.IP(b[1:]).To4() == nil)
+	default:
+		return Text(b) == string(b)
+	}
+}
+
+// isNetworkAddress reports whether b is an IANA address family, IPv4 or
+// IPv6, followed by an address of that family.
+func isNetworkAddress(b []byte) bool {
+	return len(b) > 1 && (b[0] == 1 && len(b) == 5 || b[0] == 2 && len(b) == 17)
+}
+
+// Text returns b as a string if it is printable, or in hexadecimal
+// otherwise.
+func Text(b []byte) string {
+	s := string(b)
+	for _, r := range s {
+		if !unicode.IsPrint(r) || r == unicode.ReplacementChar {
+			return hex.EncodeToString(b)
+		}
+	}
+
+	return s
+}
+
+// Names of system capabilities, in bit order.
+var capabilityNames = []string{
+	"other", "repeater", "bridge", "wlan-ap", "router", "telephone",
+	"docsis", "station", "c-vlan", "s-vlan", "tpmr",
+}
+
+// Capabilities formats a Capability bit mask as a comma-separated list of
+// capability names, such as "bridge,router", or "none" if no bits are set.
+func Capabilities(c lldp.Capability) string {
+	var ss []string
+	for i, name := range capabilityNames {
+		if c&(1<<i) != 0 {
+			ss = append(ss, name)
+		}
+	}
+
+	if len(ss) == 0 {
+		return "none"
+	}
+
+	return strings.Join(ss, ",")
+}
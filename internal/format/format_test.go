@@ -0,0 +1,203 @@
+package format
+
+import (
+	"net"
+	"testing"
+
+	"github.com/mdlayher/lldp"
+)
+
+func TestChassisIDPortID(t *testing.T) {
+	var tests = []struct {
+		desc           string
+		c              *lldp.ChassisID
+		p              *lldp.PortID
+		subtype, value string
+	}{
+		{
+			desc: "nil chassis ID",
+		},
+		{
+			desc:    "MAC address chassis ID",
+			c:       &lldp.ChassisID{Subtype: lldp.ChassisIDSubtypeMACAddress, ID: []byte{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad}},
+			subtype: "mac-address",
+			value:   "de:ad:be:ef:de:ad",
+		},
+		{
+			desc:    "network address chassis ID",
+			c:       &lldp.ChassisID{Subtype: lldp.ChassisIDSubtypeNetworkAddress, ID: []byte{1, 192, 0, 2, 1}},
+			subtype: "network-address",
+			value:   "192.0.2.1",
+		},
+		{
+			desc:    "unknown chassis ID subtype",
+			c:       &lldp.ChassisID{Subtype: 9, ID: []byte("foo")},
+			subtype: "unknown-9",
+			value:   "foo",
+		},
+		{
+			desc:    "interface name port ID",
+			p:       &lldp.PortID{Subtype: lldp.PortIDSubtypeInterfaceName, ID: []byte("eth0")},
+			subtype: "interface-name",
+			value:   "eth0",
+		},
+		{
+			desc:    "binary local port ID",
+			p:       &lldp.PortID{Subtype: lldp.PortIDSubtypeLocallyAssigned, ID: []byte{0x0a, 0x0b}},
+			subtype: "local",
+			value:   "0a0b",
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		var subtype, value string
+		if tt.p != nil {
+			subtype, value = PortID(tt.p)
+		} else {
+			subtype, value = ChassisID(tt.c)
+		}
+
+		if want, got := tt.subtype, subtype; want != got {
+			t.Fatalf("unexpected subtype: %q != %q", want, got)
+		}
+		if want, got := tt.value, value; want != got {
+			t.Fatalf("unexpected value: %q != %q", want, got)
+		}
+	}
+}
+
+func TestID(t *testing.T) {
+	var tests = []struct {
+		desc         string
+		mac, network bool
+		b            []byte
+		s            string
+		exact        bool
+	}{
+		{
+			desc:  "MAC address",
+			mac:   true,
+			b:     []byte{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad},
+			s:     "de:ad:be:ef:de:ad",
+			exact: true,
+		},
+		{
+			desc: "short MAC address",
+			mac:  true,
+			b:    []byte{0x00, 0x01},
+			s:    "0001",
+		},
+		{
+			desc:    "IPv6 network address",
+			network: true,
+			b:       append([]byte{2}, net.ParseIP("2001:db8::1")...),
+			s:       "2001:db8::1",
+			exact:   true,
+		},
+		{
+			desc:    "IPv4-mapped network address",
+			network: true,
+			b:       append([]byte{2}, net.ParseIP("192.0.2.1")...),
+			s:       "192.0.2.1",
+		},
+		{
+			desc:    "text network address",
+			network: true,
+			b:       []byte("192.0.2.1"),
+			s:       "192.0.2.1",
+		},
+		{
+			desc:  "text",
+			b:     []byte("Ethernet1"),
+			s:     "Ethernet1",
+			exact: true,
+		},
+		{
+			desc: "binary",
+			b:    []byte{0x00, 0xff},
+			s:    "00ff",
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		if want, got := tt.s, ID(tt.mac, tt.network, tt.b); want != got {
+			t.Fatalf("unexpected ID: %q != %q", want, got)
+		}
+		if want, got := tt.exact, Exact(tt.mac, tt.network, tt.b); want != got {
+			t.Fatalf("unexpected exact: %v != %v", want, got)
+		}
+	}
+}
+
+func TestChassisIDKeyPortIDKey(t *testing.T) {
+	var (
+		local = &lldp.ChassisID{Subtype: lldp.ChassisIDSubtypeLocallyAssigned, ID: []byte("0a0b")}
+		name  = &lldp.PortID{Subtype: lldp.PortIDSubtypeInterfaceName, ID: []byte("0a0b")}
+	)
+
+	var tests = []struct {
+		desc string
+		a, b string
+		same bool
+	}{
+		{
+			desc: "same chassis ID",
+			a:    ChassisIDKey(local),
+			b:    ChassisIDKey(&lldp.ChassisID{Subtype: lldp.ChassisIDSubtypeLocallyAssigned, ID: []byte("0a0b")}),
+			same: true,
+		},
+		{
+			desc: "chassis IDs formatted identically",
+			a:    ChassisIDKey(local),
+			b:    ChassisIDKey(&lldp.ChassisID{Subtype: lldp.ChassisIDSubtypeLocallyAssigned, ID: []byte{0x0a, 0x0b}}),
+		},
+		{
+			desc: "different chassis ID subtype",
+			a:    ChassisIDKey(local),
+			b:    ChassisIDKey(&lldp.ChassisID{Subtype: lldp.ChassisIDSubtypeInterfaceName, ID: []byte("0a0b")}),
+		},
+		{
+			desc: "different port ID subtype",
+			a:    PortIDKey(name),
+			b:    PortIDKey(&lldp.PortID{Subtype: lldp.PortIDSubtypeLocallyAssigned, ID: []byte("0a0b")}),
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		if want, got := tt.same, tt.a == tt.b; want != got {
+			t.Fatalf("unexpected key equality: %v != %v: %q, %q", want, got, tt.a, tt.b)
+		}
+	}
+}
+
+func TestCapabilities(t *testing.T) {
+	var tests = []struct {
+		desc string
+		c    lldp.Capability
+		s    string
+	}{
+		{
+			desc: "none",
+			s:    "none",
+		},
+		{
+			desc: "bridge and router",
+			c:    lldp.CapabilityBridge | lldp.CapabilityRouter,
+			s:    "bridge,router",
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		if want, got := tt.s, Capabilities(tt.c); want != got {
+			t.Fatalf("unexpected capabilities: %q != %q", want, got)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+// Package text provides string helpers for TLV values whose length is
+// limited.
+package text
+
+import "unicode/utf8"
+
+// Truncate truncates a string to at most n bytes, without splitting
+// a multi-byte UTF-8 sequence.
+func Truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+
+	return s[:n]
+}
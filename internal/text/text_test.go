@@ -0,0 +1,64 @@
+package text
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncate(t *testing.T) {
+	var tests = []struct {
+		desc string
+		s    string
+		n    int
+		out  string
+	}{
+		{
+			desc: "short",
+			s:    "foo",
+			n:    4,
+			out:  "foo",
+		},
+		{
+			desc: "exact",
+			s:    "foo",
+			n:    3,
+			out:  "foo",
+		},
+		{
+			desc: "ASCII",
+			s:    "foobar",
+			n:    3,
+			out:  "foo",
+		},
+		{
+			desc: "multi-byte boundary",
+			s:    "aé",
+			n:    3,
+			out:  "aé",
+		},
+		{
+			desc: "inside multi-byte sequence",
+			s:    "aéb",
+			n:    2,
+			out:  "a",
+		},
+		{
+			desc: "inside four byte sequence",
+			s:    "😀",
+			n:    3,
+			out:  "",
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		got := Truncate(tt.s, tt.n)
+		if want := tt.out; want != got {
+			t.Fatalf("unexpected string: %q != %q", want, got)
+		}
+		if !utf8.ValidString(got) {
+			t.Fatalf("invalid UTF-8: %q", got)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package lldp
+
+import (
+	"encoding/binary"
+	"io"
+	"time"
+)
+
+// UnmarshalBinaryLenient unmarshals a byte slice into a Frame on a
+// best-effort basis, recovering as much information as possible from
+// a malformed LLDPDU.
+//
+// Unlike UnmarshalBinary, UnmarshalBinaryLenient tolerates the following
+// problems:
+//   - A truncated trailing TLV, which is discarded
+//   - A missing end of LLDPDU TLV
+//   - Mandatory TLVs which are out of order or duplicated
+//   - A TTL TLV whose length is not 2
+//
+// Decoding stops at the first end of LLDPDU TLV, and any bytes which follow
+// it are stored in Trailing.  TLVs which are not mandatory are stored in
+// Optional.
+//
+// Each problem encountered is reported as a *DecodeError in the returned
+// slice, in the order it was detected.  If no problems were found, the
+// returned slice is nil.  Any of ChassisID, PortID, and TTL which could
+// not be recovered are left as their zero values.
+func (f *Frame) UnmarshalBinaryLenient(b []byte) []*DecodeError {
+	var (
+		problems []*DecodeError
+		seen     = make(map[TLVType]bool, 3)
+		end      bool
+	)
+
+	f.ChassisID = nil
+	f.PortID = nil
+	f.TTL = 0
+	f.Optional = nil
+	f.Trailing = nil
+
+	problem := func(off, i int, t TLVType, r ErrorReason, err error) {
+		problems = append(problems, &DecodeError{
+			Offset: off,
+			Index:  i,
+			Type:   t,
+			Reason: r,
+			Err:    err,
+		})
+	}
+
+	var i, l int
+	for ; len(b[l:]) > 0 && !end; i++ {
+		t := new(TLV)
+		if err := t.UnmarshalBinary(b[l:]); err != nil {
+			// Discard a truncated trailing TLV, but report the problem.
+			if de, ok := err.(*DecodeError); ok {
+				de.Offset += l
+				de.Index = i
+				problems = append(problems, de)
+			}
+
+			break
+		}
+
+		off := l
+		l += 2 + int(t.Length)
+
+		switch t.Type {
+		case TLVTypeChassisID:
+			if seen[t.Type] {
+				problem(off, i, t.Type, ErrorReasonDuplicateTLV, ErrInvalidFrame)
+				continue
+			}
+			seen[t.Type] = true
+			if i != 0 {
+				problem(off, i, t.Type, ErrorReasonOutOfOrder, ErrInvalidFrame)
+			}
+
+			c := new(ChassisID)
+			if err := c.UnmarshalBinary(t.Value); err != nil {
+				problem(off, i, t.Type, ErrorReasonInvalidChassisID, err)
+				continue
+			}
+
+			f.ChassisID = c
+		case TLVTypePortID:
+			if seen[t.Type] {
+				problem(off, i, t.Type, ErrorReasonDuplicateTLV, ErrInvalidFrame)
+				continue
+			}
+			seen[t.Type] = true
+			if i != 1 {
+				problem(off, i, t.Type, ErrorReasonOutOfOrder, ErrInvalidFrame)
+			}
+
+			p := new(PortID)
+			if err := p.UnmarshalBinary(t.Value); err != nil {
+				problem(off, i, t.Type, ErrorReasonInvalidPortID, err)
+				continue
+			}
+
+			f.PortID = p
+		case TLVTypeTTL:
+			if seen[t.Type] {
+				problem(off, i, t.Type, ErrorReasonDuplicateTLV, ErrInvalidFrame)
+				continue
+			}
+			seen[t.Type] = true
+			if i != 2 {
+				problem(off, i, t.Type, ErrorReasonOutOfOrder, ErrInvalidFrame)
+			}
+
+			// Use the first two bytes of an overlong TTL, but a short
+			// TTL cannot be recovered.
+			if t.Length != 2 {
+				problem(off, i, t.Type, ErrorReasonInvalidTTLLength, ErrInvalidFrame)
+			}
+			if t.Length < 2 {
+				continue
+			}
+
+			f.TTL = time.Duration(binary.BigEndian.Uint16(t.Value[0:2])) * time.Second
+		case TLVTypeEnd:
+			if t.Length != 0 {
+				problem(off, i, t.Type, ErrorReasonInvalidEndLength, ErrInvalidFrame)
+			}
+
+			end = true
+		default:
+			f.Optional = append(f.Optional, t)
+		}
+	}
+
+	if end && len(b[l:]) > 0 {
+		f.Trailing = make([]byte, len(b[l:]))
+		copy(f.Trailing, b[l:])
+	}
+
+	// Report any mandatory TLVs which could not be found at all.
+	for _, m := range []struct {
+		t TLVType
+		r ErrorReason
+	}{
+		{t: TLVTypeChassisID, r: ErrorReasonMissingChassisID},
+		{t: TLVTypePortID, r: ErrorReasonMissingPortID},
+		{t: TLVTypeTTL, r: ErrorReasonMissingTTL},
+	} {
+		if !seen[m.t] {
+			problem(len(b), -1, 0, m.r, ErrInvalidFrame)
+		}
+	}
+	if !end {
+		problem(len(b), -1, 0, ErrorReasonMissingEnd, io.ErrUnexpectedEOF)
+	}
+
+	return problems
+}
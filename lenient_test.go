@@ -0,0 +1,139 @@
+package lldp
+
+import (
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFrameUnmarshalBinaryLenient(t *testing.T) {
+	var tests = []struct {
+		desc     string
+		b        []byte
+		f        *Frame
+		problems []*DecodeError
+	}{
+		{
+			desc: "nil buffer",
+			f:    &Frame{},
+			problems: []*DecodeError{
+				{Index: -1, Reason: ErrorReasonMissingChassisID, Err: ErrInvalidFrame},
+				{Index: -1, Reason: ErrorReasonMissingPortID, Err: ErrInvalidFrame},
+				{Index: -1, Reason: ErrorReasonMissingTTL, Err: ErrInvalidFrame},
+				{Index: -1, Reason: ErrorReasonMissingEnd, Err: io.ErrUnexpectedEOF},
+			},
+		},
+		{
+			desc: "OK Frame, no problems",
+			b: []byte{
+				0x02, 0x02, 7, 'a',
+				0x04, 0x02, 7, 'b',
+				0x06, 0x02, 0x00, 0x78,
+				0x0a, 0x01, 'c',
+				0x00, 0x00,
+			},
+			f: &Frame{
+				ChassisID: &ChassisID{Subtype: 7, ID: []byte("a")},
+				PortID:    &PortID{Subtype: 7, ID: []byte("b")},
+				TTL:       120 * time.Second,
+				Optional: []*TLV{{
+					Type:   TLVTypeSystemName,
+					Length: 1,
+					Value:  []byte("c"),
+				}},
+			},
+		},
+		{
+			desc: "truncated trailing TLV, missing end of LLDPDU",
+			b: []byte{
+				0x02, 0x02, 7, 'a',
+				0x04, 0x02, 7, 'b',
+				0x06, 0x02, 0x00, 0x78,
+				0x0a, 0x05, 'c',
+			},
+			f: &Frame{
+				ChassisID: &ChassisID{Subtype: 7, ID: []byte("a")},
+				PortID:    &PortID{Subtype: 7, ID: []byte("b")},
+				TTL:       120 * time.Second,
+			},
+			problems: []*DecodeError{
+				{
+					Offset: 14,
+					Index:  3,
+					Type:   TLVTypeSystemName,
+					Reason: ErrorReasonTruncatedValue,
+					Err:    io.ErrUnexpectedEOF,
+				},
+				{
+					Offset: 15,
+					Index:  -1,
+					Reason: ErrorReasonMissingEnd,
+					Err:    io.ErrUnexpectedEOF,
+				},
+			},
+		},
+		{
+			desc: "mandatory TLVs out of order, duplicate TTL, stop at end",
+			b: []byte{
+				0x04, 0x02, 7, 'b',
+				0x02, 0x02, 7, 'a',
+				0x06, 0x03, 0x00, 0x78, 0xff,
+				0x06, 0x02, 0x00, 0x01,
+				0x00, 0x00,
+				0x0a, 0x01, 'c',
+			},
+			f: &Frame{
+				ChassisID: &ChassisID{Subtype: 7, ID: []byte("a")},
+				PortID:    &PortID{Subtype: 7, ID: []byte("b")},
+				TTL:       120 * time.Second,
+				Trailing:  []byte{0x0a, 0x01, 'c'},
+			},
+			problems: []*DecodeError{
+				{
+					Offset: 0,
+					Index:  0,
+					Type:   TLVTypePortID,
+					Reason: ErrorReasonOutOfOrder,
+					Err:    ErrInvalidFrame,
+				},
+				{
+					Offset: 4,
+					Index:  1,
+					Type:   TLVTypeChassisID,
+					Reason: ErrorReasonOutOfOrder,
+					Err:    ErrInvalidFrame,
+				},
+				{
+					Offset: 8,
+					Index:  2,
+					Type:   TLVTypeTTL,
+					Reason: ErrorReasonInvalidTTLLength,
+					Err:    ErrInvalidFrame,
+				},
+				{
+					Offset: 13,
+					Index:  3,
+					Type:   TLVTypeTTL,
+					Reason: ErrorReasonDuplicateTLV,
+					Err:    ErrInvalidFrame,
+				},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		f := new(Frame)
+		problems := f.UnmarshalBinaryLenient(tt.b)
+
+		if want, got := tt.f, f; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected Frame:\n- want: %v\n-  got: %v", want, got)
+		}
+
+		if want, got := tt.problems, problems; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected problems:\n- want: %v\n-  got: %v", want, got)
+		}
+	}
+}
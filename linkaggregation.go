@@ -0,0 +1,127 @@
+package lldp
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+const (
+	// IEEE8021SubtypeLinkAggregation is the IEEE 802.1 organizationally
+	// specific subtype for the Link Aggregation TLV.
+	IEEE8021SubtypeLinkAggregation uint8 = 7
+
+	// IEEE8023SubtypeLinkAggregation is the IEEE 802.3 organizationally
+	// specific subtype for the legacy Link Aggregation TLV, which was
+	// superseded by the IEEE 802.1 form in IEEE 802.1AB-2009.
+	IEEE8023SubtypeLinkAggregation uint8 = 3
+)
+
+// Aggregation status bits carried in a LinkAggregation.
+const (
+	lagCapable = 1 << 0
+	lagEnabled = 1 << 1
+)
+
+// A LinkAggregation is a structure parsed from a Link Aggregation
+// organizationally specific TLV.  It indicates whether a port is capable of
+// being aggregated, whether it is currently aggregated, and the identifier
+// of the aggregation to which it belongs.
+type LinkAggregation struct {
+	// Capable and Enabled indicate if the port is capable of being
+	// aggregated, and if it is currently a member of an aggregation.
+	Capable bool
+	Enabled bool
+
+	// PortID specifies the interface index of the aggregated port, or 0
+	// if the port is not aggregated.
+	PortID uint32
+}
+
+// MarshalBinary allocates a byte slice and marshals a LinkAggregation into
+// the binary form of an IEEE 802.1 organizationally specific TLV value.
+//
+// MarshalBinary never returns an error.
+func (l *LinkAggregation) MarshalBinary() ([]byte, error) {
+	return l.marshal(OUIIEEE8021, IEEE8021SubtypeLinkAggregation)
+}
+
+// MarshalLegacy allocates a byte slice and marshals a LinkAggregation into
+// the binary form of a legacy IEEE 802.3 organizationally specific TLV
+// value, for use with peers which predate IEEE 802.1AB-2009.
+//
+// MarshalLegacy never returns an error.
+func (l *LinkAggregation) MarshalLegacy() ([]byte, error) {
+	return l.marshal(OUIIEEE8023, IEEE8023SubtypeLinkAggregation)
+}
+
+func (l *LinkAggregation) marshal(oui OUI, subtype uint8) ([]byte, error) {
+	// 1 byte: aggregation status
+	// 4 bytes: aggregated port ID
+	info := make([]byte, 5)
+	if l.Capable {
+		info[0] |= lagCapable
+	}
+	if l.Enabled {
+		info[0] |= lagEnabled
+	}
+	binary.BigEndian.PutUint32(info[1:5], l.PortID)
+
+	return (&OrganizationSpecific{
+		OUI:     oui,
+		Subtype: subtype,
+		Info:    info,
+	}).MarshalBinary()
+}
+
+// UnmarshalBinary unmarshals an organizationally specific TLV value into
+// a LinkAggregation.  Both the IEEE 802.1 and legacy IEEE 802.3 forms are
+// accepted.
+//
+// If the byte slice does not contain enough data to unmarshal a valid
+// LinkAggregation, io.ErrUnexpectedEOF is returned.
+//
+// If the byte slice does not carry either form of the Link Aggregation TLV,
+// ErrInvalidTLV is returned.
+func (l *LinkAggregation) UnmarshalBinary(b []byte) error {
+	info, err := organizationSpecificInfo(b, OUIIEEE8021, IEEE8021SubtypeLinkAggregation)
+	if errors.Is(err, ErrInvalidTLV) {
+		info, err = organizationSpecificInfo(b, OUIIEEE8023, IEEE8023SubtypeLinkAggregation)
+	}
+	if err != nil {
+		return err
+	}
+	if len(info) != 5 {
+		return io.ErrUnexpectedEOF
+	}
+
+	l.Capable = info[0]&lagCapable != 0
+	l.Enabled = info[0]&lagEnabled != 0
+	l.PortID = binary.BigEndian.Uint32(info[1:5])
+
+	return nil
+}
+
+// LinkAggregation returns the link aggregation information carried in
+// a Frame's optional TLVs.  The IEEE 802.1 form of the TLV is preferred over
+// the legacy IEEE 802.3 form.
+//
+// If no Link Aggregation TLV is present, LinkAggregation returns nil and
+// false.  Any errors encountered while unmarshaling the TLV are also
+// reported as false.
+func (f *Frame) LinkAggregation() (*LinkAggregation, bool) {
+	tt := f.OrganizationSpecific(OUIIEEE8021, IEEE8021SubtypeLinkAggregation)
+	if len(tt) == 0 {
+		tt = f.OrganizationSpecific(OUIIEEE8023, IEEE8023SubtypeLinkAggregation)
+	}
+	if len(tt) == 0 {
+		return nil, false
+	}
+
+	l := new(LinkAggregation)
+	if err := l.UnmarshalBinary(tt[0].Value); err != nil {
+		return nil, false
+	}
+
+	return l, true
+}
@@ -0,0 +1,99 @@
+package lldp
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestLinkAggregationUnmarshalBinary(t *testing.T) {
+	var tests = []struct {
+		desc   string
+		b      []byte
+		l      *LinkAggregation
+		legacy bool
+		err    error
+	}{
+		{
+			desc: "nil buffer",
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "wrong subtype",
+			b:    []byte{0x00, 0x80, 0xc2, 3, 0x03, 0x00, 0x00, 0x00, 0x05},
+			err:  ErrInvalidTLV,
+		},
+		{
+			desc: "short information string",
+			b:    []byte{0x00, 0x80, 0xc2, 7, 0x03, 0x00, 0x00},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "not aggregated",
+			b:    []byte{0x00, 0x80, 0xc2, 7, 0x01, 0x00, 0x00, 0x00, 0x00},
+			l: &LinkAggregation{
+				Capable: true,
+			},
+		},
+		{
+			desc: "IEEE 802.1 aggregated",
+			b:    []byte{0x00, 0x80, 0xc2, 7, 0x03, 0x00, 0x00, 0x00, 0x05},
+			l: &LinkAggregation{
+				Capable: true,
+				Enabled: true,
+				PortID:  5,
+			},
+		},
+		{
+			desc: "IEEE 802.3 aggregated",
+			b:    []byte{0x00, 0x12, 0x0f, 3, 0x03, 0x00, 0x00, 0x01, 0x00},
+			l: &LinkAggregation{
+				Capable: true,
+				Enabled: true,
+				PortID:  256,
+			},
+			legacy: true,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		l := new(LinkAggregation)
+		if err := l.UnmarshalBinary(tt.b); err != nil {
+			if want, got := tt.err, err; want != got {
+				t.Fatalf("unexpected error:\n- want: %v\n-  got: %v", want, got)
+			}
+
+			continue
+		}
+
+		if want, got := tt.l, l; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected LinkAggregation:\n- want: %v\n-  got: %v", want, got)
+		}
+
+		marshal := l.MarshalBinary
+		if tt.legacy {
+			marshal = l.MarshalLegacy
+		}
+
+		b, err := marshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want, got := tt.b, b; !bytes.Equal(want, got) {
+			t.Fatalf("unexpected LinkAggregation bytes:\n- want: %v\n-  got: %v", want, got)
+		}
+
+		fl, ok := orgFrame(b).LinkAggregation()
+		if !ok {
+			t.Fatal("expected Link Aggregation TLV in Frame")
+		}
+
+		if want, got := tt.l, fl; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected Frame LinkAggregation:\n- want: %v\n-  got: %v", want, got)
+		}
+	}
+}
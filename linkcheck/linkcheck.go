@@ -0,0 +1,261 @@
+// Package linkcheck compares the LLDP frames advertised by the two ends of
+// a link, and reports configuration on which they disagree.
+//
+// Check pairs the frames received from each end of a link and reports
+// native VLAN, MTU, speed, duplex, auto-negotiation, link aggregation, and
+// PFC mismatches.  Settings which only one end advertises are not compared.
+package linkcheck
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mdlayher/lldp"
+)
+
+// A FindingType is a kind of configuration mismatch between the two ends of
+// a link.
+type FindingType int
+
+// List of valid FindingType values.
+const (
+	// FindingNativeVLAN indicates that the ends advertise different port
+	// VLAN IDs, so untagged frames are bridged into different VLANs.
+	FindingNativeVLAN FindingType = iota
+
+	// FindingMTU indicates that the ends advertise different maximum frame
+	// sizes.
+	FindingMTU
+
+	// FindingSpeed indicates that the ends report different operational
+	// speeds.
+	FindingSpeed
+
+	// FindingDuplex indicates that one end reports full duplex and the
+	// other half duplex.
+	FindingDuplex
+
+	// FindingAutoneg indicates that auto-negotiation is enabled on one end
+	// but not the other.
+	FindingAutoneg
+
+	// FindingLAG indicates that one end is a member of a link aggregation
+	// but the other is not.
+	FindingLAG
+
+	// FindingPFC indicates that the ends enable PFC on different
+	// priorities, and neither is willing to accept its peer's
+	// configuration.
+	FindingPFC
+)
+
+// findingTypeNames maps FindingType values to their text form.
+var findingTypeNames = map[FindingType]string{
+	FindingNativeVLAN: "native-vlan",
+	FindingMTU:        "mtu",
+	FindingSpeed:      "speed",
+	FindingDuplex:     "duplex",
+	FindingAutoneg:    "autoneg",
+	FindingLAG:        "lag",
+	FindingPFC:        "pfc",
+}
+
+// String returns the text form of a FindingType.
+func (t FindingType) String() string {
+	if n, ok := findingTypeNames[t]; ok {
+		return n
+	}
+
+	return fmt.Sprintf("FindingType(%d)", int(t))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (t FindingType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// A Finding is a configuration mismatch between the two ends of a link.
+type Finding struct {
+	Type FindingType `json:"type"`
+
+	// A and B describe the setting advertised by each end of the link, in
+	// the order the frames were passed to Check, such as "1500" and
+	// "9216" for FindingMTU.
+	A string `json:"a"`
+	B string `json:"b"`
+}
+
+// String returns a description of a Finding, such as
+// "mtu mismatch: 1518 != 9216".
+func (f Finding) String() string {
+	return fmt.Sprintf("%s mismatch: %s != %s", f.Type, f.A, f.B)
+}
+
+// Check compares the frames advertised by the two ends of a link, and
+// returns their mismatches, sorted by type.  Check returns no findings if
+// the ends agree on every setting which both of them advertise.
+func Check(a, b *lldp.Frame) []Finding {
+	var ff []Finding
+	add := func(t FindingType, a, b string) {
+		ff = append(ff, Finding{Type: t, A: a, B: b})
+	}
+
+	// A port VLAN ID of 0 indicates that the port does not support
+	// port-based VLANs.
+	if pa, pb, ok := portVLANIDs(a, b); ok && pa.ID != 0 && pb.ID != 0 && pa.ID != pb.ID {
+		add(FindingNativeVLAN, strconv.Itoa(int(pa.ID)), strconv.Itoa(int(pb.ID)))
+	}
+
+	if ma, mb, ok := maximumFrameSizes(a, b); ok && ma.Size != mb.Size {
+		add(FindingMTU, strconv.Itoa(int(ma.Size)), strconv.Itoa(int(mb.Size)))
+	}
+
+	if ma, mb, ok := macphys(a, b); ok {
+		ta, oka := mauTypes[ma.MAUType]
+		tb, okb := mauTypes[mb.MAUType]
+		if oka && okb {
+			if ta.speed != tb.speed {
+				add(FindingSpeed, formatSpeed(ta.speed), formatSpeed(tb.speed))
+			}
+			if ta.full != tb.full {
+				add(FindingDuplex, formatDuplex(ta.full), formatDuplex(tb.full))
+			}
+		}
+
+		if ma.AutonegEnabled != mb.AutonegEnabled {
+			add(FindingAutoneg, formatEnabled(ma.AutonegEnabled), formatEnabled(mb.AutonegEnabled))
+		}
+	}
+
+	if la, lb, ok := linkAggregations(a, b); ok && la.Enabled != lb.Enabled {
+		add(FindingLAG, formatAggregated(la.Enabled), formatAggregated(lb.Enabled))
+	}
+
+	// A willing port is expected to adopt its peer's configuration, so its
+	// advertised configuration may differ without consequence.
+	if pa, pb, ok := pfcs(a, b); ok && !pa.Willing && !pb.Willing && pa.Enabled != pb.Enabled {
+		add(FindingPFC, formatPriorities(pa.Enabled), formatPriorities(pb.Enabled))
+	}
+
+	sort.SliceStable(ff, func(i, j int) bool {
+		return ff[i].Type < ff[j].Type
+	})
+
+	return ff
+}
+
+// portVLANIDs returns the PortVLANIDs of both frames, if both carry one.
+func portVLANIDs(a, b *lldp.Frame) (*lldp.PortVLANID, *lldp.PortVLANID, bool) {
+	pa, oka := a.PortVLANID()
+	pb, okb := b.PortVLANID()
+	return pa, pb, oka && okb
+}
+
+// maximumFrameSizes returns the MaximumFrameSizes of both frames, if both
+// carry one.
+func maximumFrameSizes(a, b *lldp.Frame) (*lldp.MaximumFrameSize, *lldp.MaximumFrameSize, bool) {
+	ma, oka := a.MaximumFrameSize()
+	mb, okb := b.MaximumFrameSize()
+	return ma, mb, oka && okb
+}
+
+// macphys returns the MACPHYs of both frames, if both carry one.
+func macphys(a, b *lldp.Frame) (*lldp.MACPHY, *lldp.MACPHY, bool) {
+	ma, oka := a.MACPHY()
+	mb, okb := b.MACPHY()
+	return ma, mb, oka && okb
+}
+
+// linkAggregations returns the LinkAggregations of both frames, if both
+// carry one.
+func linkAggregations(a, b *lldp.Frame) (*lldp.LinkAggregation, *lldp.LinkAggregation, bool) {
+	la, oka := a.LinkAggregation()
+	lb, okb := b.LinkAggregation()
+	return la, lb, oka && okb
+}
+
+// pfcs returns the PFCs of both frames, if both carry one.
+func pfcs(a, b *lldp.Frame) (*lldp.PFC, *lldp.PFC, bool) {
+	pa, oka := a.PFC()
+	pb, okb := b.PFC()
+	return pa, pb, oka && okb
+}
+
+// A mau is the speed and duplex of a MAU type.
+type mau struct {
+	speed int // Mb/s
+	full  bool
+}
+
+// mauTypes maps the MAU types known to package lldp to their speed and
+// duplex.  Speed and duplex are not compared for other MAU types.
+var mauTypes = map[lldp.MAUType]mau{
+	lldp.MAUType10BaseTHD:   {speed: 10},
+	lldp.MAUType10BaseTFD:   {speed: 10, full: true},
+	lldp.MAUType100BaseTXHD: {speed: 100},
+	lldp.MAUType100BaseTXFD: {speed: 100, full: true},
+	lldp.MAUType100BaseFXHD: {speed: 100},
+	lldp.MAUType100BaseFXFD: {speed: 100, full: true},
+	lldp.MAUType1000BaseXHD: {speed: 1000},
+	lldp.MAUType1000BaseXFD: {speed: 1000, full: true},
+	lldp.MAUType1000BaseTHD: {speed: 1000},
+	lldp.MAUType1000BaseTFD: {speed: 1000, full: true},
+	lldp.MAUType10GBaseX:    {speed: 10000, full: true},
+	lldp.MAUType10GBaseR:    {speed: 10000, full: true},
+	lldp.MAUType10GBaseT:    {speed: 10000, full: true},
+}
+
+// formatSpeed formats a speed in Mb/s, such as "100Mb/s" or "10Gb/s".
+func formatSpeed(mbps int) string {
+	if mbps >= 1000 && mbps%1000 == 0 {
+		return strconv.Itoa(mbps/1000) + "Gb/s"
+	}
+
+	return strconv.Itoa(mbps) + "Mb/s"
+}
+
+// formatDuplex formats a duplex mode.
+func formatDuplex(full bool) string {
+	if full {
+		return "full"
+	}
+
+	return "half"
+}
+
+// formatEnabled formats whether auto-negotiation is enabled.
+func formatEnabled(enabled bool) string {
+	if enabled {
+		return "enabled"
+	}
+
+	return "disabled"
+}
+
+// formatAggregated formats whether a port is aggregated.
+func formatAggregated(enabled bool) string {
+	if enabled {
+		return "aggregated"
+	}
+
+	return "not aggregated"
+}
+
+// formatPriorities formats a PFC enable bit mask as a list of priorities,
+// such as "3,4", or "none".
+func formatPriorities(enabled uint8) string {
+	var ss []string
+	for p := 0; p < 8; p++ {
+		if enabled&(1<<p) != 0 {
+			ss = append(ss, strconv.Itoa(p))
+		}
+	}
+
+	if len(ss) == 0 {
+		return "none"
+	}
+
+	return strings.Join(ss, ",")
+}
@@ -0,0 +1,169 @@
+package linkcheck
+
+import (
+	"encoding"
+	"reflect"
+	"testing"
+
+	"github.com/mdlayher/lldp"
+)
+
+func TestCheck(t *testing.T) {
+	var tests = []struct {
+		desc string
+		a, b []encoding.BinaryMarshaler
+		ff   []Finding
+	}{
+		{
+			desc: "no optional TLVs",
+		},
+		{
+			desc: "one end only",
+			a: []encoding.BinaryMarshaler{
+				&lldp.PortVLANID{ID: 10},
+				&lldp.MaximumFrameSize{Size: 9216},
+			},
+		},
+		{
+			desc: "matching",
+			a: []encoding.BinaryMarshaler{
+				&lldp.PortVLANID{ID: 10},
+				&lldp.MaximumFrameSize{Size: 9216},
+				&lldp.MACPHY{AutonegSupported: true, AutonegEnabled: true, MAUType: lldp.MAUType1000BaseTFD},
+				&lldp.LinkAggregation{Capable: true, Enabled: true, PortID: 100},
+				&lldp.PFC{Capability: 8, Enabled: 1 << 3},
+			},
+			b: []encoding.BinaryMarshaler{
+				&lldp.PortVLANID{ID: 10},
+				&lldp.MaximumFrameSize{Size: 9216},
+				&lldp.MACPHY{AutonegEnabled: true, MAUType: lldp.MAUType1000BaseTFD},
+				&lldp.LinkAggregation{Capable: true, Enabled: true, PortID: 200},
+				&lldp.PFC{Capability: 4, Enabled: 1 << 3},
+			},
+		},
+		{
+			desc: "native VLAN",
+			a:    []encoding.BinaryMarshaler{&lldp.PortVLANID{ID: 10}},
+			b:    []encoding.BinaryMarshaler{&lldp.PortVLANID{ID: 20}},
+			ff:   []Finding{{Type: FindingNativeVLAN, A: "10", B: "20"}},
+		},
+		{
+			desc: "native VLAN unsupported",
+			a:    []encoding.BinaryMarshaler{&lldp.PortVLANID{ID: 10}},
+			b:    []encoding.BinaryMarshaler{&lldp.PortVLANID{ID: 0}},
+		},
+		{
+			desc: "MTU",
+			a:    []encoding.BinaryMarshaler{&lldp.MaximumFrameSize{Size: 1518}},
+			b:    []encoding.BinaryMarshaler{&lldp.MaximumFrameSize{Size: 9216}},
+			ff:   []Finding{{Type: FindingMTU, A: "1518", B: "9216"}},
+		},
+		{
+			desc: "speed and duplex",
+			a:    []encoding.BinaryMarshaler{&lldp.MACPHY{MAUType: lldp.MAUType1000BaseTFD}},
+			b:    []encoding.BinaryMarshaler{&lldp.MACPHY{MAUType: lldp.MAUType100BaseTXHD}},
+			ff: []Finding{
+				{Type: FindingSpeed, A: "1Gb/s", B: "100Mb/s"},
+				{Type: FindingDuplex, A: "full", B: "half"},
+			},
+		},
+		{
+			desc: "speed only",
+			a:    []encoding.BinaryMarshaler{&lldp.MACPHY{MAUType: lldp.MAUType10GBaseR}},
+			b:    []encoding.BinaryMarshaler{&lldp.MACPHY{MAUType: lldp.MAUType1000BaseXFD}},
+			ff:   []Finding{{Type: FindingSpeed, A: "10Gb/s", B: "1Gb/s"}},
+		},
+		{
+			desc: "same speed, different media",
+			a:    []encoding.BinaryMarshaler{&lldp.MACPHY{MAUType: lldp.MAUType10GBaseR}},
+			b:    []encoding.BinaryMarshaler{&lldp.MACPHY{MAUType: lldp.MAUType10GBaseT}},
+		},
+		{
+			desc: "unknown MAU type",
+			a:    []encoding.BinaryMarshaler{&lldp.MACPHY{MAUType: lldp.MAUTypeUnknown}},
+			b:    []encoding.BinaryMarshaler{&lldp.MACPHY{MAUType: lldp.MAUType100BaseTXHD}},
+		},
+		{
+			desc: "autoneg",
+			a: []encoding.BinaryMarshaler{
+				&lldp.MACPHY{AutonegSupported: true, AutonegEnabled: true, MAUType: lldp.MAUType1000BaseTFD},
+			},
+			b: []encoding.BinaryMarshaler{
+				&lldp.MACPHY{AutonegSupported: true, MAUType: lldp.MAUType1000BaseTFD},
+			},
+			ff: []Finding{{Type: FindingAutoneg, A: "enabled", B: "disabled"}},
+		},
+		{
+			desc: "LAG",
+			a:    []encoding.BinaryMarshaler{&lldp.LinkAggregation{Capable: true}},
+			b:    []encoding.BinaryMarshaler{&lldp.LinkAggregation{Capable: true, Enabled: true, PortID: 100}},
+			ff:   []Finding{{Type: FindingLAG, A: "not aggregated", B: "aggregated"}},
+		},
+		{
+			desc: "PFC",
+			a:    []encoding.BinaryMarshaler{&lldp.PFC{Capability: 8, Enabled: 1<<3 | 1<<4}},
+			b:    []encoding.BinaryMarshaler{&lldp.PFC{Capability: 8}},
+			ff:   []Finding{{Type: FindingPFC, A: "3,4", B: "none"}},
+		},
+		{
+			desc: "PFC willing",
+			a:    []encoding.BinaryMarshaler{&lldp.PFC{Capability: 8, Enabled: 1 << 3}},
+			b:    []encoding.BinaryMarshaler{&lldp.PFC{Willing: true, Capability: 8}},
+		},
+		{
+			desc: "sorted by type",
+			a: []encoding.BinaryMarshaler{
+				&lldp.PFC{Enabled: 1},
+				&lldp.MaximumFrameSize{Size: 1518},
+				&lldp.PortVLANID{ID: 1},
+			},
+			b: []encoding.BinaryMarshaler{
+				&lldp.PortVLANID{ID: 2},
+				&lldp.PFC{Enabled: 2},
+				&lldp.MaximumFrameSize{Size: 1522},
+			},
+			ff: []Finding{
+				{Type: FindingNativeVLAN, A: "1", B: "2"},
+				{Type: FindingMTU, A: "1518", B: "1522"},
+				{Type: FindingPFC, A: "0", B: "1"},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		if want, got := tt.ff, Check(testFrame(t, tt.a), testFrame(t, tt.b)); !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected findings:\n- want: %v\n-  got: %v", want, got)
+		}
+	}
+}
+
+func TestFindingString(t *testing.T) {
+	f := Finding{Type: FindingMTU, A: "1518", B: "9216"}
+	if want, got := "mtu mismatch: 1518 != 9216", f.String(); want != got {
+		t.Fatalf("unexpected string:\n- want: %q\n-  got: %q", want, got)
+	}
+}
+
+// testFrame builds a Frame carrying the organizationally specific TLVs
+// marshaled from mm.
+func testFrame(t *testing.T, mm []encoding.BinaryMarshaler) *lldp.Frame {
+	t.Helper()
+
+	f := new(lldp.Frame)
+	for _, m := range mm {
+		b, err := m.MarshalBinary()
+		if err != nil {
+			t.Fatalf("failed to marshal TLV: %v", err)
+		}
+
+		f.Optional = append(f.Optional, &lldp.TLV{
+			Type:   lldp.TLVTypeOrganizationSpecific,
+			Length: uint16(len(b)),
+			Value:  b,
+		})
+	}
+
+	return f
+}
@@ -55,14 +55,20 @@ type Frame struct {
 
 	// Optional specifies zero or more optional TLV values in raw format.
 	Optional []*TLV
+
+	// Trailing specifies any bytes which followed the end of LLDPDU TLV
+	// when a Frame was unmarshaled, such as Ethernet padding.  Trailing
+	// is ignored by MarshalBinary.
+	Trailing []byte
 }
 
 // MarshalBinary allocates a byte slice and marshals a Frame into binary form.
 //
 // If ChassisID or Port ID are nil, or TTL is greater than 65535 seconds,
-// ErrInvalidFrame is returned.
+// an *EncodeError wrapping ErrInvalidFrame is returned.
 //
-// If any problems are detected with TLVs, ErrInvalidTLV is returned.
+// If any problems are detected with TLVs, an *EncodeError wrapping
+// ErrInvalidTLV is returned.
 func (f *Frame) MarshalBinary() ([]byte, error) {
 	// TODO(mdlayher): optimize to reduce allocations
 
@@ -71,16 +77,16 @@ func (f *Frame) MarshalBinary() ([]byte, error) {
 
 	// Sanity checks to avoid panics
 	if f.ChassisID == nil {
-		return nil, ErrInvalidFrame
+		return nil, frameEncodeError(ErrorReasonMissingChassisID)
 	}
 	if f.PortID == nil {
-		return nil, ErrInvalidFrame
+		return nil, frameEncodeError(ErrorReasonMissingPortID)
 	}
 
 	// Ensure TTL fits in a uint16
 	tTTL := f.TTL / time.Second
 	if tTTL > math.MaxUint16 {
-		return nil, ErrInvalidFrame
+		return nil, frameEncodeError(ErrorReasonTTLTooLarge)
 	}
 	ttl := uint16(tTTL)
 
@@ -101,7 +107,7 @@ func (f *Frame) MarshalBinary() ([]byte, error) {
 	}
 	cbb, err := cTLV.MarshalBinary()
 	if err != nil {
-		return nil, err
+		return nil, withIndex(err, 0)
 	}
 
 	n += len(cbb)
@@ -119,7 +125,7 @@ func (f *Frame) MarshalBinary() ([]byte, error) {
 	}
 	pbb, err := pTLV.MarshalBinary()
 	if err != nil {
-		return nil, err
+		return nil, withIndex(err, 1)
 	}
 
 	copy(b[n:n+len(pbb)], pbb)
@@ -135,17 +141,17 @@ func (f *Frame) MarshalBinary() ([]byte, error) {
 	}
 	tbb, err := tTLV.MarshalBinary()
 	if err != nil {
-		return nil, err
+		return nil, withIndex(err, 2)
 	}
 
 	copy(b[n:n+len(tbb)], tbb)
 	n += len(tbb)
 
 	// Store any optional TLVs
-	for _, t := range f.Optional {
+	for i, t := range f.Optional {
 		tb, err := t.MarshalBinary()
 		if err != nil {
-			return nil, err
+			return nil, withIndex(err, 3+i)
 		}
 
 		copy(b[n:n+len(tb)], tb)
@@ -158,61 +164,87 @@ func (f *Frame) MarshalBinary() ([]byte, error) {
 // UnmarshalBinary unmarshals a byte slice into a Frame.
 //
 // If the byte slice does not contain enough data to unmarshal a valid Frame,
-// io.ErrUnexpectedEOF is returned.
+// a *DecodeError wrapping io.ErrUnexpectedEOF is returned.
 //
 // If the four mandatory TLV values chassis ID, port ID, TTL, and end of
-// LLDPDU, are missing or do not appear in order, ErrInvalidFrame is returned.
+// LLDPDU, are missing or do not appear in order, a *DecodeError wrapping
+// ErrInvalidFrame is returned.
+//
+// Decoding stops at the first end of LLDPDU TLV.  Any bytes which follow it
+// are stored in Trailing.
 func (f *Frame) UnmarshalBinary(b []byte) error {
-	// Iterate and keep creating TLVs as long as bytes remain
-	var tt []*TLV
-	for l := 0; len(b[l:]) > 0; {
-		// Unmarshal a single TLV
-		t := new(TLV)
-		if err := t.UnmarshalBinary(b[l:]); err != nil {
-			return err
-		}
+	tt, offs, trailing, err := parseTLVs(b)
+	if err != nil {
+		return err
+	}
 
-		// Advance to next TLV and keep looping
-		l += 2 + int(t.Length)
-		tt = append(tt, t)
+	// Must have at least four mandatory TLVs, unless an early end of
+	// LLDPDU indicates that some are missing entirely
+	if len(tt) < 4 && (len(tt) == 0 || tt[len(tt)-1].Type != TLVTypeEnd) {
+		return &DecodeError{
+			Offset: len(b),
+			Index:  -1,
+			Reason: ErrorReasonTooFewTLVs,
+			Err:    io.ErrUnexpectedEOF,
+		}
 	}
 
-	// Must have at least four mandatory TLVs
-	if len(tt) < 4 {
-		return io.ErrUnexpectedEOF
+	// decodeError creates a *DecodeError for the TLV at index i
+	decodeError := func(i int, r ErrorReason, err error) error {
+		return &DecodeError{
+			Offset: offs[i],
+			Index:  i,
+			Type:   tt[i].Type,
+			Reason: r,
+			Err:    err,
+		}
 	}
 
 	// First TLV must be Chassis ID
 	if tt[0].Type != TLVTypeChassisID {
-		return ErrInvalidFrame
+		return decodeError(0, ErrorReasonMissingChassisID, ErrInvalidFrame)
 	}
 	f.ChassisID = new(ChassisID)
 	if err := f.ChassisID.UnmarshalBinary(tt[0].Value); err != nil {
-		return err
+		return decodeError(0, ErrorReasonInvalidChassisID, err)
 	}
 
 	// Second TLV must be Port ID
 	if tt[1].Type != TLVTypePortID {
-		return ErrInvalidFrame
+		return decodeError(1, ErrorReasonMissingPortID, ErrInvalidFrame)
 	}
 	f.PortID = new(PortID)
 	if err := f.PortID.UnmarshalBinary(tt[1].Value); err != nil {
-		return err
+		return decodeError(1, ErrorReasonInvalidPortID, err)
 	}
 
 	// Third TLV must be TTL and uint16 value
-	if tt[2].Type != TLVTypeTTL || tt[2].Length != 2 {
-		return ErrInvalidFrame
+	if tt[2].Type != TLVTypeTTL {
+		return decodeError(2, ErrorReasonMissingTTL, ErrInvalidFrame)
+	}
+	if tt[2].Length != 2 {
+		return decodeError(2, ErrorReasonInvalidTTLLength, ErrInvalidFrame)
 	}
 	f.TTL = time.Duration(binary.BigEndian.Uint16(tt[2].Value)) * time.Second
 
 	// Final TLV must be end of LLDPDU with length 0
-	if tt[len(tt)-1].Type != TLVTypeEnd || tt[len(tt)-1].Length != 0 {
-		return ErrInvalidFrame
+	last := len(tt) - 1
+	if tt[last].Type != TLVTypeEnd {
+		return decodeError(last, ErrorReasonMissingEnd, ErrInvalidFrame)
+	}
+	if tt[last].Length != 0 {
+		return decodeError(last, ErrorReasonInvalidEndLength, ErrInvalidFrame)
 	}
 
 	// Optional TLVs resliced from middle
-	f.Optional = tt[3 : len(tt)-1]
+	f.Optional = tt[3:last]
+
+	// Retain any padding or other data after end of LLDPDU
+	f.Trailing = nil
+	if len(trailing) > 0 {
+		f.Trailing = make([]byte, len(trailing))
+		copy(f.Trailing, trailing)
+	}
 
 	return nil
 }
@@ -236,3 +268,21 @@ func (f *Frame) length() int {
 
 	return n
 }
+
+// frameEncodeError creates an *EncodeError for a Frame with the input reason.
+func frameEncodeError(r ErrorReason) error {
+	return &EncodeError{
+		Index:  -1,
+		Reason: r,
+		Err:    ErrInvalidFrame,
+	}
+}
+
+// withIndex adds the index of a TLV within a Frame to an *EncodeError.
+func withIndex(err error, i int) error {
+	if ee, ok := err.(*EncodeError); ok {
+		ee.Index = i
+	}
+
+	return err
+}
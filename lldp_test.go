@@ -2,6 +2,7 @@ package lldp
 
 import (
 	"bytes"
+	"errors"
 	"io"
 	"log"
 	"math"
@@ -99,7 +100,7 @@ func TestFrameMarshalBinary(t *testing.T) {
 
 		b, err := tt.f.MarshalBinary()
 		if err != nil {
-			if want, got := tt.err, err; want != got {
+			if want, got := tt.err, err; !errors.Is(got, want) {
 				t.Fatalf("unexpected error:\n- want: %v\n-  got: %v", want, got)
 			}
 
@@ -250,7 +251,7 @@ func TestFrameUnmarshalBinary(t *testing.T) {
 
 		f := new(Frame)
 		if err := f.UnmarshalBinary(tt.b); err != nil {
-			if want, got := tt.err, err; want != got {
+			if want, got := tt.err, err; !errors.Is(got, want) {
 				t.Fatalf("unexpected error:\n- want: %v\n-  got: %v", want, got)
 			}
 
@@ -267,3 +268,29 @@ func TestFrameUnmarshalBinary(t *testing.T) {
 		}
 	}
 }
+
+func TestFrameUnmarshalBinaryTrailing(t *testing.T) {
+	b := []byte{
+		0x02, 0x02, 7, 'a',
+		0x04, 0x02, 7, 'b',
+		0x06, 0x02, 0x00, 0x78,
+		0x00, 0x00,
+		// Padding which could be mistaken for TLVs.
+		0x00, 0x00,
+		0x0a, 0x01, 'c',
+		0xff,
+	}
+
+	f := new(Frame)
+	if err := f.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := 0, len(f.Optional); want != got {
+		t.Fatalf("unexpected number of optional TLVs: %d != %d", want, got)
+	}
+
+	if want, got := b[14:], f.Trailing; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected trailing bytes:\n- want: %v\n-  got: %v", want, got)
+	}
+}
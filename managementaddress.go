@@ -0,0 +1,147 @@
+package lldp
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// An AddressFamily is an IANA address family number, used to indicate the
+// type of address carried in a ManagementAddress.
+type AddressFamily uint8
+
+// List of commonly used AddressFamily values.
+const (
+	AddressFamilyIPv4 AddressFamily = 1
+	AddressFamilyIPv6 AddressFamily = 2
+	AddressFamily802  AddressFamily = 6
+)
+
+// An InterfaceNumbering is a value used to indicate the numbering method
+// used for the interface number carried in a ManagementAddress.
+type InterfaceNumbering uint8
+
+// List of valid InterfaceNumbering values.
+const (
+	InterfaceNumberingUnknown          InterfaceNumbering = 1
+	InterfaceNumberingIfIndex          InterfaceNumbering = 2
+	InterfaceNumberingSystemPortNumber InterfaceNumbering = 3
+)
+
+const (
+	// managementAddressMax is the maximum length of an address carried
+	// in a ManagementAddress.
+	managementAddressMax = 31
+
+	// managementOIDMax is the maximum length of an object identifier
+	// carried in a ManagementAddress.
+	managementOIDMax = 128
+)
+
+// A ManagementAddress is a structure parsed from a management address TLV.
+// It identifies an address which can be used to reach a higher layer
+// management entity for a system.
+type ManagementAddress struct {
+	// Family specifies the type of address carried in Address.
+	Family AddressFamily
+
+	// Address specifies the management address in binary form.
+	Address []byte
+
+	// InterfaceNumbering specifies the numbering method used for
+	// InterfaceNumber.
+	InterfaceNumbering InterfaceNumbering
+
+	// InterfaceNumber specifies the interface associated with Address.
+	InterfaceNumber uint32
+
+	// OID optionally specifies an ASN.1 BER encoded object identifier for
+	// the hardware or protocol entity associated with Address.
+	OID []byte
+}
+
+// MarshalBinary allocates a byte slice and marshals a ManagementAddress into
+// binary form.
+//
+// If Family is zero, Address is empty or longer than 31 bytes,
+// InterfaceNumbering is not a valid InterfaceNumbering value, or OID is
+// longer than 128 bytes, ErrInvalidTLV is returned.
+func (m *ManagementAddress) MarshalBinary() ([]byte, error) {
+	if m.check() != ErrorReasonUnknown {
+		return nil, ErrInvalidTLV
+	}
+
+	//  1 byte: address string length
+	//  1 byte: address subtype
+	// N bytes: address
+	//  1 byte: interface numbering subtype
+	//  4 bytes: interface number
+	//  1 byte: OID string length
+	// N bytes: OID
+	b := make([]byte, 2+len(m.Address)+5+1+len(m.OID))
+	b[0] = byte(1 + len(m.Address))
+	b[1] = byte(m.Family)
+	n := 2 + copy(b[2:], m.Address)
+
+	b[n] = byte(m.InterfaceNumbering)
+	binary.BigEndian.PutUint32(b[n+1:n+5], m.InterfaceNumber)
+	b[n+5] = byte(len(m.OID))
+	copy(b[n+6:], m.OID)
+
+	return b, nil
+}
+
+// check validates a ManagementAddress for marshaling, returning the reason it
+// is invalid, or ErrorReasonUnknown if it is valid.
+func (m *ManagementAddress) check() ErrorReason {
+	switch {
+	case m.Family == 0:
+		// Address family number 0 is reserved.
+		return ErrorReasonInvalidAddressFamily
+	case len(m.Address) == 0 || len(m.Address) > managementAddressMax:
+		return ErrorReasonInvalidAddressLength
+	case m.InterfaceNumbering < InterfaceNumberingUnknown || m.InterfaceNumbering > InterfaceNumberingSystemPortNumber:
+		return ErrorReasonInvalidInterfaceNumbering
+	case len(m.OID) > managementOIDMax:
+		return ErrorReasonOIDTooLarge
+	default:
+		return ErrorReasonUnknown
+	}
+}
+
+// UnmarshalBinary unmarshals a byte slice into a ManagementAddress.
+//
+// If the byte slice does not contain enough data to unmarshal a valid
+// ManagementAddress, io.ErrUnexpectedEOF is returned.
+//
+// If the address string length is out of range, ErrInvalidTLV is returned.
+func (m *ManagementAddress) UnmarshalBinary(b []byte) error {
+	if len(b) < 2 {
+		return io.ErrUnexpectedEOF
+	}
+
+	// Address string length includes the subtype
+	al := int(b[0])
+	if al < 2 || al > 1+managementAddressMax {
+		return ErrInvalidTLV
+	}
+	if len(b[1:]) < al+5+1 {
+		return io.ErrUnexpectedEOF
+	}
+
+	m.Family = AddressFamily(b[1])
+	m.Address = make([]byte, al-1)
+	copy(m.Address, b[2:1+al])
+
+	n := 1 + al
+	m.InterfaceNumbering = InterfaceNumbering(b[n])
+	m.InterfaceNumber = binary.BigEndian.Uint32(b[n+1 : n+5])
+
+	ol := int(b[n+5])
+	if len(b[n+6:]) < ol {
+		return io.ErrUnexpectedEOF
+	}
+	m.OID = make([]byte, ol)
+	copy(m.OID, b[n+6:n+6+ol])
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package lldp
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestManagementAddressMarshalBinary(t *testing.T) {
+	var tests = []struct {
+		desc string
+		m    *ManagementAddress
+		b    []byte
+		err  error
+	}{
+		{
+			desc: "no family",
+			m: &ManagementAddress{
+				Address:            []byte{192, 0, 2, 1},
+				InterfaceNumbering: InterfaceNumberingUnknown,
+			},
+			err: ErrInvalidTLV,
+		},
+		{
+			desc: "no address",
+			m: &ManagementAddress{
+				Family:             AddressFamilyIPv4,
+				InterfaceNumbering: InterfaceNumberingUnknown,
+			},
+			err: ErrInvalidTLV,
+		},
+		{
+			desc: "address too long",
+			m: &ManagementAddress{
+				Family:             AddressFamilyIPv4,
+				Address:            make([]byte, managementAddressMax+1),
+				InterfaceNumbering: InterfaceNumberingUnknown,
+			},
+			err: ErrInvalidTLV,
+		},
+		{
+			desc: "invalid interface numbering",
+			m: &ManagementAddress{
+				Family:             AddressFamilyIPv4,
+				Address:            []byte{192, 0, 2, 1},
+				InterfaceNumbering: InterfaceNumberingSystemPortNumber + 1,
+			},
+			err: ErrInvalidTLV,
+		},
+		{
+			desc: "OID too long",
+			m: &ManagementAddress{
+				Family:             AddressFamilyIPv4,
+				Address:            []byte{192, 0, 2, 1},
+				InterfaceNumbering: InterfaceNumberingUnknown,
+				OID:                make([]byte, managementOIDMax+1),
+			},
+			err: ErrInvalidTLV,
+		},
+		{
+			desc: "IPv4, ifIndex",
+			m: &ManagementAddress{
+				Family:             AddressFamilyIPv4,
+				Address:            []byte{192, 0, 2, 1},
+				InterfaceNumbering: InterfaceNumberingIfIndex,
+				InterfaceNumber:    2,
+			},
+			b: []byte{
+				5, 1, 192, 0, 2, 1,
+				2, 0, 0, 0, 2,
+				0,
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		b, err := tt.m.MarshalBinary()
+		if err != nil {
+			if want, got := tt.err, err; want != got {
+				t.Fatalf("unexpected error:\n- want: %v\n-  got: %v", want, got)
+			}
+
+			continue
+		}
+
+		if want, got := tt.b, b; !bytes.Equal(want, got) {
+			t.Fatalf("unexpected ManagementAddress bytes:\n- want: %v\n-  got: %v", want, got)
+		}
+	}
+}
+
+func TestManagementAddressUnmarshalBinary(t *testing.T) {
+	var tests = []struct {
+		desc string
+		b    []byte
+		m    *ManagementAddress
+		err  error
+	}{
+		{
+			desc: "nil buffer",
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "address string length too short",
+			b:    []byte{1, 1},
+			err:  ErrInvalidTLV,
+		},
+		{
+			desc: "short interface number",
+			b:    []byte{5, 1, 192, 0, 2, 1, 2, 0},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "short OID",
+			b: []byte{
+				5, 1, 192, 0, 2, 1,
+				2, 0, 0, 0, 2,
+				2, 0x2b,
+			},
+			err: io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "IPv4, ifIndex, OID",
+			b: []byte{
+				5, 1, 192, 0, 2, 1,
+				2, 0, 0, 0, 2,
+				2, 0x2b, 0x06,
+			},
+			m: &ManagementAddress{
+				Family:             AddressFamilyIPv4,
+				Address:            []byte{192, 0, 2, 1},
+				InterfaceNumbering: InterfaceNumberingIfIndex,
+				InterfaceNumber:    2,
+				OID:                []byte{0x2b, 0x06},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		m := new(ManagementAddress)
+		if err := m.UnmarshalBinary(tt.b); err != nil {
+			if want, got := tt.err, err; want != got {
+				t.Fatalf("unexpected error:\n- want: %v\n-  got: %v", want, got)
+			}
+
+			continue
+		}
+
+		if want, got := tt.m, m; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected ManagementAddress:\n- want: %v\n-  got: %v", want, got)
+		}
+	}
+}
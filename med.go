@@ -0,0 +1,186 @@
+package lldp
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/mdlayher/lldp/internal/text"
+)
+
+// TIA LLDP-MED organizationally specific subtypes.
+const (
+	TIASubtypeCapabilities     uint8 = 1
+	TIASubtypeHardwareRevision uint8 = 5
+	TIASubtypeFirmwareRevision uint8 = 6
+	TIASubtypeSoftwareRevision uint8 = 7
+	TIASubtypeSerialNumber     uint8 = 8
+	TIASubtypeManufacturerName uint8 = 9
+	TIASubtypeModelName        uint8 = 10
+	TIASubtypeAssetID          uint8 = 11
+)
+
+// InventoryLengthMax is the maximum length of each field carried in an
+// Inventory.
+const InventoryLengthMax = 32
+
+// A MEDCapability is a bit mask of LLDP-MED capabilities, as carried in
+// a MEDCapabilities.
+type MEDCapability uint16
+
+// List of valid MEDCapability values.
+const (
+	MEDCapabilityCapabilities     MEDCapability = 1 << 0
+	MEDCapabilityNetworkPolicy    MEDCapability = 1 << 1
+	MEDCapabilityLocation         MEDCapability = 1 << 2
+	MEDCapabilityExtendedPowerPSE MEDCapability = 1 << 3
+	MEDCapabilityExtendedPowerPD  MEDCapability = 1 << 4
+	MEDCapabilityInventory        MEDCapability = 1 << 5
+)
+
+// A MEDDeviceType is a value used to indicate the LLDP-MED device type of
+// a system.
+type MEDDeviceType uint8
+
+// List of valid MEDDeviceType values.
+const (
+	MEDDeviceTypeNotDefined          MEDDeviceType = 0
+	MEDDeviceTypeEndpointClassI      MEDDeviceType = 1
+	MEDDeviceTypeEndpointClassII     MEDDeviceType = 2
+	MEDDeviceTypeEndpointClassIII    MEDDeviceType = 3
+	MEDDeviceTypeNetworkConnectivity MEDDeviceType = 4
+)
+
+// A MEDCapabilities is a structure parsed from an LLDP-MED capabilities TLV.
+// It must be present in any Frame which carries other LLDP-MED TLVs.
+type MEDCapabilities struct {
+	// Capabilities specifies the LLDP-MED TLVs supported by a system.
+	Capabilities MEDCapability
+
+	// DeviceType specifies the LLDP-MED device type of a system.
+	DeviceType MEDDeviceType
+}
+
+// MarshalBinary allocates a byte slice and marshals a MEDCapabilities into
+// the binary form of an organizationally specific TLV value.
+//
+// MarshalBinary never returns an error.
+func (m *MEDCapabilities) MarshalBinary() ([]byte, error) {
+	// 2 bytes: capabilities
+	// 1 byte: device type
+	info := make([]byte, 3)
+	binary.BigEndian.PutUint16(info[0:2], uint16(m.Capabilities))
+	info[2] = byte(m.DeviceType)
+
+	return (&OrganizationSpecific{
+		OUI:     OUITIA,
+		Subtype: TIASubtypeCapabilities,
+		Info:    info,
+	}).MarshalBinary()
+}
+
+// UnmarshalBinary unmarshals an organizationally specific TLV value into
+// a MEDCapabilities.
+//
+// If the byte slice does not contain enough data to unmarshal a valid
+// MEDCapabilities, io.ErrUnexpectedEOF is returned.
+//
+// If the byte slice does not carry the TIA OUI and capabilities subtype,
+// ErrInvalidTLV is returned.
+func (m *MEDCapabilities) UnmarshalBinary(b []byte) error {
+	info, err := organizationSpecificInfo(b, OUITIA, TIASubtypeCapabilities)
+	if err != nil {
+		return err
+	}
+	if len(info) != 3 {
+		return io.ErrUnexpectedEOF
+	}
+
+	m.Capabilities = MEDCapability(binary.BigEndian.Uint16(info[0:2]))
+	m.DeviceType = MEDDeviceType(info[2])
+
+	return nil
+}
+
+// An Inventory is a structure parsed from the LLDP-MED inventory management
+// TLVs.  Each field is carried in its own TLV, and may be no longer than
+// InventoryLengthMax bytes.
+type Inventory struct {
+	HardwareRevision string
+	FirmwareRevision string
+	SoftwareRevision string
+	SerialNumber     string
+	ManufacturerName string
+	ModelName        string
+	AssetID          string
+}
+
+// TLVs produces an LLDP-MED inventory TLV for each non-empty field of an
+// Inventory, in subtype order.  Fields longer than InventoryLengthMax bytes
+// are truncated.
+func (inv *Inventory) TLVs() []*TLV {
+	var tt []*TLV
+	for _, f := range inv.fields() {
+		if *f.s == "" {
+			continue
+		}
+
+		v, _ := (&OrganizationSpecific{
+			OUI:     OUITIA,
+			Subtype: f.subtype,
+			Info:    []byte(text.Truncate(*f.s, InventoryLengthMax)),
+		}).MarshalBinary()
+
+		tt = append(tt, &TLV{
+			Type:   TLVTypeOrganizationSpecific,
+			Length: uint16(len(v)),
+			Value:  v,
+		})
+	}
+
+	return tt
+}
+
+// Inventory returns the LLDP-MED inventory information carried in a Frame's
+// optional TLVs.
+//
+// If no LLDP-MED inventory TLVs are present, Inventory returns nil and
+// false.
+func (f *Frame) Inventory() (*Inventory, bool) {
+	inv := new(Inventory)
+
+	var found bool
+	for _, fi := range inv.fields() {
+		tt := f.OrganizationSpecific(OUITIA, fi.subtype)
+		if len(tt) == 0 {
+			continue
+		}
+
+		found = true
+		*fi.s = string(tt[0].Value[4:])
+	}
+
+	if !found {
+		return nil, false
+	}
+
+	return inv, true
+}
+
+// inventoryField associates an Inventory field with its LLDP-MED subtype.
+type inventoryField struct {
+	subtype uint8
+	s       *string
+}
+
+// fields returns the fields of an Inventory in subtype order.
+func (inv *Inventory) fields() []inventoryField {
+	return []inventoryField{
+		{subtype: TIASubtypeHardwareRevision, s: &inv.HardwareRevision},
+		{subtype: TIASubtypeFirmwareRevision, s: &inv.FirmwareRevision},
+		{subtype: TIASubtypeSoftwareRevision, s: &inv.SoftwareRevision},
+		{subtype: TIASubtypeSerialNumber, s: &inv.SerialNumber},
+		{subtype: TIASubtypeManufacturerName, s: &inv.ManufacturerName},
+		{subtype: TIASubtypeModelName, s: &inv.ModelName},
+		{subtype: TIASubtypeAssetID, s: &inv.AssetID},
+	}
+}
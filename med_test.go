@@ -0,0 +1,118 @@
+package lldp
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMEDCapabilitiesUnmarshalBinary(t *testing.T) {
+	var tests = []struct {
+		desc string
+		b    []byte
+		m    *MEDCapabilities
+		err  error
+	}{
+		{
+			desc: "nil buffer",
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "wrong subtype",
+			b:    []byte{0x00, 0x12, 0xbb, 2, 0x00, 0x21, 1},
+			err:  ErrInvalidTLV,
+		},
+		{
+			desc: "short information string",
+			b:    []byte{0x00, 0x12, 0xbb, 1, 0x00},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "OK",
+			b:    []byte{0x00, 0x12, 0xbb, 1, 0x00, 0x21, 1},
+			m: &MEDCapabilities{
+				Capabilities: MEDCapabilityCapabilities | MEDCapabilityInventory,
+				DeviceType:   MEDDeviceTypeEndpointClassI,
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		m := new(MEDCapabilities)
+		if err := m.UnmarshalBinary(tt.b); err != nil {
+			if want, got := tt.err, err; want != got {
+				t.Fatalf("unexpected error:\n- want: %v\n-  got: %v", want, got)
+			}
+
+			continue
+		}
+
+		if want, got := tt.m, m; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected MEDCapabilities:\n- want: %v\n-  got: %v", want, got)
+		}
+
+		b, err := m.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want, got := tt.b, b; !bytes.Equal(want, got) {
+			t.Fatalf("unexpected MEDCapabilities bytes:\n- want: %v\n-  got: %v", want, got)
+		}
+	}
+}
+
+func TestInventoryTLVs(t *testing.T) {
+	inv := &Inventory{
+		FirmwareRevision: "1.2.3",
+		SerialNumber:     "ABC123",
+		ModelName:        strings.Repeat("x", InventoryLengthMax-1) + "é",
+	}
+
+	tt := inv.TLVs()
+
+	want := []*TLV{
+		{
+			Type:   TLVTypeOrganizationSpecific,
+			Length: 9,
+			Value:  []byte{0x00, 0x12, 0xbb, 6, '1', '.', '2', '.', '3'},
+		},
+		{
+			Type:   TLVTypeOrganizationSpecific,
+			Length: 10,
+			Value:  []byte{0x00, 0x12, 0xbb, 8, 'A', 'B', 'C', '1', '2', '3'},
+		},
+		{
+			// Multi-byte rune which crosses the limit is dropped.
+			Type:   TLVTypeOrganizationSpecific,
+			Length: 4 + InventoryLengthMax - 1,
+			Value: append(
+				[]byte{0x00, 0x12, 0xbb, 10},
+				strings.Repeat("x", InventoryLengthMax-1)...,
+			),
+		},
+	}
+
+	if !reflect.DeepEqual(want, tt) {
+		t.Fatalf("unexpected TLVs:\n- want: %v\n-  got: %v", want, tt)
+	}
+
+	f := &Frame{Optional: tt}
+	got, ok := f.Inventory()
+	if !ok {
+		t.Fatal("expected inventory in Frame")
+	}
+
+	inv.ModelName = strings.Repeat("x", InventoryLengthMax-1)
+	if !reflect.DeepEqual(inv, got) {
+		t.Fatalf("unexpected Inventory:\n- want: %v\n-  got: %v", inv, got)
+	}
+
+	if _, ok := (&Frame{}).Inventory(); ok {
+		t.Fatal("unexpected inventory in empty Frame")
+	}
+}
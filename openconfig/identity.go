@@ -0,0 +1,113 @@
+package openconfig
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mdlayher/lldp"
+)
+
+// typesModule is the name of the YANG module which defines the LLDP
+// identities, used to qualify identityref values in RFC 7951 JSON.
+const typesModule = "openconfig-lldp-types"
+
+// Names of the chassis-id-type and port-id-type enumerations.
+var (
+	chassisIDTypes = map[lldp.ChassisIDSubtype]string{
+		lldp.ChassisIDSubtypeChassisComponenent: "CHASSIS_COMPONENT",
+		lldp.ChassisIDSubtypeInterfaceAlias:     "INTERFACE_ALIAS",
+		lldp.ChassisIDSubtypePortComponent:      "PORT_COMPONENT",
+		lldp.ChassisIDSubtypeMACAddress:         "MAC_ADDRESS",
+		lldp.ChassisIDSubtypeNetworkAddress:     "NETWORK_ADDRESS",
+		lldp.ChassisIDSubtypeInterfaceName:      "INTERFACE_NAME",
+		lldp.ChassisIDSubtypeLocallyAssigned:    "LOCAL",
+	}
+
+	portIDTypes = map[lldp.PortIDSubtype]string{
+		lldp.PortIDSubtypeInterfaceAlias:  "INTERFACE_ALIAS",
+		lldp.PortIDSubtypePortComponent:   "PORT_COMPONENT",
+		lldp.PortIDSubtypeMACAddress:      "MAC_ADDRESS",
+		lldp.PortIDSubtypeNetworkAddress:  "NETWORK_ADDRESS",
+		lldp.PortIDSubtypeInterfaceName:   "INTERFACE_NAME",
+		lldp.PortIDSubtypeAgentCircuitID:  "AGENT_CIRCUIT_ID",
+		lldp.PortIDSubtypeLocallyAssigned: "LOCAL",
+	}
+)
+
+// ChassisIDType returns the name of the chassis-id-type enumeration value
+// for a ChassisIDSubtype, such as "MAC_ADDRESS".  Reserved subtypes have no
+// name, and return an empty string.
+func ChassisIDType(s lldp.ChassisIDSubtype) string {
+	return chassisIDTypes[s]
+}
+
+// ParseChassisIDType parses the name of a chassis-id-type enumeration value.
+func ParseChassisIDType(name string) (lldp.ChassisIDSubtype, error) {
+	for s, n := range chassisIDTypes {
+		if n == name {
+			return s, nil
+		}
+	}
+
+	return 0, fmt.Errorf("openconfig: unknown chassis ID type %q", name)
+}
+
+// PortIDType returns the name of the port-id-type enumeration value for a
+// PortIDSubtype, such as "INTERFACE_NAME".  Reserved subtypes have no name,
+// and return an empty string.
+func PortIDType(s lldp.PortIDSubtype) string {
+	return portIDTypes[s]
+}
+
+// ParsePortIDType parses the name of a port-id-type enumeration value.
+func ParsePortIDType(name string) (lldp.PortIDSubtype, error) {
+	for s, n := range portIDTypes {
+		if n == name {
+			return s, nil
+		}
+	}
+
+	return 0, fmt.Errorf("openconfig: unknown port ID type %q", name)
+}
+
+// Names of the LLDP_SYSTEM_CAPABILITY identities, in bit order.
+var capabilityNames = []string{
+	"OTHER",
+	"REPEATER",
+	"MAC_BRIDGE",
+	"WLAN_ACCESS_POINT",
+	"ROUTER",
+	"TELEPHONE",
+	"DOCSIS_CABLE_DEVICE",
+	"STATION_ONLY",
+	"C_VLAN",
+	"S_VLAN",
+	"TWO_PORT_MAC_RELAY",
+}
+
+// CapabilityName returns the module-qualified name of the
+// LLDP_SYSTEM_CAPABILITY identity for a single Capability bit, such as
+// "openconfig-lldp-types:MAC_BRIDGE".  Unknown capabilities return an empty
+// string.
+func CapabilityName(c lldp.Capability) string {
+	for i, name := range capabilityNames {
+		if c == 1<<i {
+			return typesModule + ":" + name
+		}
+	}
+
+	return ""
+}
+
+// ParseCapability parses the name of an LLDP_SYSTEM_CAPABILITY identity,
+// with or without its module prefix.
+func ParseCapability(name string) (lldp.Capability, error) {
+	bare := strings.TrimPrefix(name, typesModule+":")
+	for i, n := range capabilityNames {
+		if n == bare {
+			return 1 << i, nil
+		}
+	}
+
+	return 0, fmt.Errorf("openconfig: unknown system capability %q", name)
+}
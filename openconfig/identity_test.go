@@ -0,0 +1,99 @@
+package openconfig
+
+import (
+	"testing"
+
+	"github.com/mdlayher/lldp"
+)
+
+func TestIDTypesRoundTrip(t *testing.T) {
+	for s := range chassisIDTypes {
+		got, err := ParseChassisIDType(ChassisIDType(s))
+		if err != nil {
+			t.Fatalf("failed to parse chassis ID type: %v", err)
+		}
+		if want := s; want != got {
+			t.Fatalf("unexpected chassis ID subtype: %v != %v", want, got)
+		}
+	}
+
+	for s := range portIDTypes {
+		got, err := ParsePortIDType(PortIDType(s))
+		if err != nil {
+			t.Fatalf("failed to parse port ID type: %v", err)
+		}
+		if want := s; want != got {
+			t.Fatalf("unexpected port ID subtype: %v != %v", want, got)
+		}
+	}
+
+	if _, err := ParseChassisIDType("MAC"); err == nil {
+		t.Fatal("expected an error, but none occurred")
+	}
+	if want, got := "", PortIDType(0); want != got {
+		t.Fatalf("unexpected reserved port ID type: %q != %q", want, got)
+	}
+}
+
+func TestCapability(t *testing.T) {
+	var tests = []struct {
+		desc string
+		name string
+		c    lldp.Capability
+		ok   bool
+	}{
+		{
+			desc: "qualified",
+			name: "openconfig-lldp-types:MAC_BRIDGE",
+			c:    lldp.CapabilityBridge,
+			ok:   true,
+		},
+		{
+			desc: "unqualified",
+			name: "ROUTER",
+			c:    lldp.CapabilityRouter,
+			ok:   true,
+		},
+		{
+			desc: "last capability",
+			name: "openconfig-lldp-types:TWO_PORT_MAC_RELAY",
+			c:    1 << 10,
+			ok:   true,
+		},
+		{
+			desc: "wrong module",
+			name: "openconfig-interfaces:ROUTER",
+		},
+		{
+			desc: "unknown",
+			name: "FOO",
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		c, err := ParseCapability(tt.name)
+		if !tt.ok {
+			if err == nil {
+				t.Fatal("expected an error, but none occurred")
+			}
+
+			continue
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if want, got := tt.c, c; want != got {
+			t.Fatalf("unexpected capability: %v != %v", want, got)
+		}
+		if name := CapabilityName(c); name != tt.name && name != typesModule+":"+tt.name {
+			t.Fatalf("unexpected capability name: %q", name)
+		}
+	}
+
+	if want, got := "", CapabilityName(lldp.CapabilityBridge|lldp.CapabilityRouter); want != got {
+		t.Fatalf("unexpected name for multiple capabilities: %q != %q", want, got)
+	}
+}
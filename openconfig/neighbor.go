@@ -0,0 +1,363 @@
+package openconfig
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mdlayher/lldp"
+	"github.com/mdlayher/lldp/agent"
+	"github.com/mdlayher/lldp/internal/format"
+)
+
+// A Neighbor is an entry in the neighbors list of an interface.
+type Neighbor struct {
+	ID           string         `json:"id"`
+	State        *NeighborState `json:"state,omitempty"`
+	Capabilities *Capabilities  `json:"capabilities,omitempty"`
+	CustomTLVs   *CustomTLVs    `json:"custom-tlvs,omitempty"`
+}
+
+// NeighborState is the state container of a Neighbor.
+//
+// Age specifies the number of seconds since the neighbor was discovered, and
+// LastUpdate specifies when its most recent frame was received, in
+// nanoseconds since the Unix epoch.
+type NeighborState struct {
+	ID                    string `json:"id"`
+	Age                   uint64 `json:"age,string"`
+	LastUpdate            int64  `json:"last-update,string"`
+	TTL                   uint16 `json:"ttl"`
+	ChassisID             string `json:"chassis-id,omitempty"`
+	ChassisIDType         string `json:"chassis-id-type,omitempty"`
+	PortID                string `json:"port-id,omitempty"`
+	PortIDType            string `json:"port-id-type,omitempty"`
+	PortDescription       string `json:"port-description,omitempty"`
+	SystemName            string `json:"system-name,omitempty"`
+	SystemDescription     string `json:"system-description,omitempty"`
+	ManagementAddress     string `json:"management-address,omitempty"`
+	ManagementAddressType string `json:"management-address-type,omitempty"`
+}
+
+// Capabilities is the capabilities container of a Neighbor.
+type Capabilities struct {
+	Capability []Capability `json:"capability"`
+}
+
+// A Capability is a system capability supported by a neighbor.
+type Capability struct {
+	Name  string           `json:"name"`
+	State *CapabilityState `json:"state,omitempty"`
+}
+
+// CapabilityState is the state container of a Capability.
+type CapabilityState struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+}
+
+// CustomTLVs is the custom-tlvs container of a Neighbor.
+type CustomTLVs struct {
+	TLV []CustomTLV `json:"tlv"`
+}
+
+// A CustomTLV is an organizationally specific TLV advertised by a neighbor.
+type CustomTLV struct {
+	Type       int32           `json:"type"`
+	OUI        string          `json:"oui"`
+	OUISubtype string          `json:"oui-subtype"`
+	State      *CustomTLVState `json:"state,omitempty"`
+}
+
+// CustomTLVState is the state container of a CustomTLV.  Value holds the
+// TLV's information string, and is encoded in base64.
+type CustomTLVState struct {
+	Type       int32  `json:"type"`
+	OUI        string `json:"oui"`
+	OUISubtype string `json:"oui-subtype"`
+	Value      []byte `json:"value"`
+}
+
+// Names of the management-address-type enumeration values.
+const (
+	addressTypeIPv4 = "IPV4"
+	addressTypeIPv6 = "IPV6"
+)
+
+// NewNeighbor converts an agent.Neighbor into a Neighbor, using now to
+// compute the neighbor's age.
+//
+// The neighbor's ID is formed from its chassis ID and port ID, separated by
+// a vertical bar.  Chassis IDs and port IDs which hold MAC addresses or
+// network addresses are formatted as such, and others are formatted as text
+// if printable, or in hexadecimal otherwise.
+func NewNeighbor(n *agent.Neighbor, now time.Time) Neighbor {
+	f := n.Frame
+
+	st := &NeighborState{
+		TTL:        uint16(f.TTL / time.Second),
+		LastUpdate: n.Updated.UnixNano(),
+	}
+	if !n.Discovered.IsZero() && now.After(n.Discovered) {
+		st.Age = uint64(now.Sub(n.Discovered) / time.Second)
+	}
+	if c := f.ChassisID; c != nil {
+		_, st.ChassisID = format.ChassisID(c)
+		st.ChassisIDType = ChassisIDType(c.Subtype)
+	}
+	if p := f.PortID; p != nil {
+		_, st.PortID = format.PortID(p)
+		st.PortIDType = PortIDType(p.Subtype)
+	}
+	st.ID = st.ChassisID + "|" + st.PortID
+
+	out := Neighbor{
+		ID:    st.ID,
+		State: st,
+	}
+
+	for _, t := range f.Optional {
+		switch t.Type {
+		case lldp.TLVTypePortDescription:
+			st.PortDescription = string(t.Value)
+		case lldp.TLVTypeSystemName:
+			st.SystemName = string(t.Value)
+		case lldp.TLVTypeSystemDescription:
+			st.SystemDescription = string(t.Value)
+		case lldp.TLVTypeSystemCapabilities:
+			sc := new(lldp.SystemCapabilities)
+			if err := sc.UnmarshalBinary(t.Value); err == nil {
+				out.Capabilities = newCapabilities(sc)
+			}
+		case lldp.TLVTypeManagementAddress:
+			if st.ManagementAddress != "" {
+				// Only the first IP management address is reported.
+				continue
+			}
+
+			m := new(lldp.ManagementAddress)
+			if err := m.UnmarshalBinary(t.Value); err != nil {
+				continue
+			}
+
+			switch {
+			case m.Family == lldp.AddressFamilyIPv4 && len(m.Address) == net.IPv4len:
+				st.ManagementAddressType = addressTypeIPv4
+			case m.Family == lldp.AddressFamilyIPv6 && len(m.Address) == net.IPv6len:
+				st.ManagementAddressType = addressTypeIPv6
+			default:
+				continue
+			}
+			st.ManagementAddress = net.IP(m.Address).String()
+		case lldp.TLVTypeOrganizationSpecific:
+			o := new(lldp.OrganizationSpecific)
+			if err := o.UnmarshalBinary(t.Value); err != nil {
+				continue
+			}
+
+			if out.CustomTLVs == nil {
+				out.CustomTLVs = new(CustomTLVs)
+			}
+			out.CustomTLVs.TLV = append(out.CustomTLVs.TLV, newCustomTLV(o))
+		}
+	}
+
+	return out
+}
+
+// newCapabilities converts SystemCapabilities into a list of the supported
+// capabilities.
+func newCapabilities(sc *lldp.SystemCapabilities) *Capabilities {
+	cc := new(Capabilities)
+	for i := range capabilityNames {
+		c := lldp.Capability(1 << i)
+		if sc.System&c == 0 {
+			continue
+		}
+
+		name := CapabilityName(c)
+		cc.Capability = append(cc.Capability, Capability{
+			Name: name,
+			State: &CapabilityState{
+				Name:    name,
+				Enabled: sc.Enabled&c != 0,
+			},
+		})
+	}
+
+	return cc
+}
+
+// newCustomTLV converts an organizationally specific TLV into a CustomTLV.
+func newCustomTLV(o *lldp.OrganizationSpecific) CustomTLV {
+	var (
+		oui     = formatOUI(o.OUI)
+		subtype = strconv.Itoa(int(o.Subtype))
+	)
+
+	return CustomTLV{
+		Type:       int32(lldp.TLVTypeOrganizationSpecific),
+		OUI:        oui,
+		OUISubtype: subtype,
+		State: &CustomTLVState{
+			Type:       int32(lldp.TLVTypeOrganizationSpecific),
+			OUI:        oui,
+			OUISubtype: subtype,
+			Value:      o.Info,
+		},
+	}
+}
+
+// Frame converts a Neighbor back into an lldp.Frame.
+//
+// Chassis IDs and port IDs are parsed according to their types, so IDs which
+// were formatted in hexadecimal because they were not printable are not
+// restored to their original form.
+func (n *Neighbor) Frame() (*lldp.Frame, error) {
+	st := n.State
+	if st == nil {
+		return nil, errors.New("openconfig: neighbor has no state")
+	}
+
+	cs, err := ParseChassisIDType(st.ChassisIDType)
+	if err != nil {
+		return nil, err
+	}
+	cid, err := parseID(cs == lldp.ChassisIDSubtypeMACAddress,
+		cs == lldp.ChassisIDSubtypeNetworkAddress, st.ChassisID)
+	if err != nil {
+		return nil, fmt.Errorf("openconfig: invalid chassis ID: %w", err)
+	}
+
+	ps, err := ParsePortIDType(st.PortIDType)
+	if err != nil {
+		return nil, err
+	}
+	pid, err := parseID(ps == lldp.PortIDSubtypeMACAddress,
+		ps == lldp.PortIDSubtypeNetworkAddress, st.PortID)
+	if err != nil {
+		return nil, fmt.Errorf("openconfig: invalid port ID: %w", err)
+	}
+
+	b := lldp.NewFrameBuilder().
+		ChassisID(cs, cid).
+		PortID(ps, pid).
+		TTL(time.Duration(st.TTL) * time.Second)
+
+	if st.PortDescription != "" {
+		b.PortDescription(st.PortDescription)
+	}
+	if st.SystemName != "" {
+		b.SystemName(st.SystemName)
+	}
+	if st.SystemDescription != "" {
+		b.SystemDescription(st.SystemDescription)
+	}
+
+	if n.Capabilities != nil {
+		var system, enabled lldp.Capability
+		for _, c := range n.Capabilities.Capability {
+			v, err := ParseCapability(c.Name)
+			if err != nil {
+				return nil, err
+			}
+
+			system |= v
+			if c.State != nil && c.State.Enabled {
+				enabled |= v
+			}
+		}
+
+		b.SystemCapabilities(system, enabled)
+	}
+
+	if st.ManagementAddress != "" {
+		ip := net.ParseIP(st.ManagementAddress)
+		if ip == nil {
+			return nil, fmt.Errorf("openconfig: invalid management address %q", st.ManagementAddress)
+		}
+
+		m := &lldp.ManagementAddress{
+			Family:             lldp.AddressFamilyIPv6,
+			Address:            ip.To16(),
+			InterfaceNumbering: lldp.InterfaceNumberingUnknown,
+		}
+		if ip4 := ip.To4(); ip4 != nil && st.ManagementAddressType != addressTypeIPv6 {
+			m.Family = lldp.AddressFamilyIPv4
+			m.Address = ip4
+		}
+
+		b.ManagementAddress(m)
+	}
+
+	if n.CustomTLVs != nil {
+		for _, t := range n.CustomTLVs.TLV {
+			oui, err := parseOUI(t.OUI)
+			if err != nil {
+				return nil, err
+			}
+
+			subtype, err := strconv.ParseUint(t.OUISubtype, 10, 8)
+			if err != nil {
+				return nil, fmt.Errorf("openconfig: invalid OUI subtype %q", t.OUISubtype)
+			}
+
+			var info []byte
+			if t.State != nil {
+				info = t.State.Value
+			}
+
+			b.OrgSpecific(oui, uint8(subtype), info)
+		}
+	}
+
+	return b.Frame()
+}
+
+// parseID parses a chassis ID or port ID value formatted by format.ID.
+func parseID(mac, network bool, s string) ([]byte, error) {
+	switch {
+	case mac:
+		hw, err := net.ParseMAC(s)
+		if err != nil {
+			return nil, err
+		}
+
+		return hw, nil
+	case network:
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid network address %q", s)
+		}
+		if ip4 := ip.To4(); ip4 != nil {
+			return append([]byte{1}, ip4...), nil
+		}
+
+		return append([]byte{2}, ip...), nil
+	default:
+		return []byte(s), nil
+	}
+}
+
+// formatOUI formats an OUI as hyphen-separated hexadecimal octets, such as
+// "00-80-C2".
+func formatOUI(oui lldp.OUI) string {
+	return fmt.Sprintf("%02X-%02X-%02X", oui[0], oui[1], oui[2])
+}
+
+// parseOUI parses an OUI formatted by formatOUI.
+func parseOUI(s string) (lldp.OUI, error) {
+	var oui lldp.OUI
+
+	b, err := hex.DecodeString(strings.ReplaceAll(s, "-", ""))
+	if err != nil || len(b) != len(oui) {
+		return oui, fmt.Errorf("openconfig: invalid OUI %q", s)
+	}
+	copy(oui[:], b)
+
+	return oui, nil
+}
@@ -0,0 +1,230 @@
+package openconfig
+
+import (
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/lldp"
+	"github.com/mdlayher/lldp/agent"
+)
+
+func TestNewNeighbor(t *testing.T) {
+	var (
+		discovered = time.Unix(1000, 0)
+		updated    = time.Unix(1090, 0)
+	)
+
+	n := &agent.Neighbor{
+		Interface:  "eth0",
+		Frame:      testNeighborFrame(t),
+		Discovered: discovered,
+		Updated:    updated,
+	}
+
+	want := Neighbor{
+		ID: "de:ad:be:ef:de:ad|Ethernet1",
+		State: &NeighborState{
+			ID:                    "de:ad:be:ef:de:ad|Ethernet1",
+			Age:                   100,
+			LastUpdate:            updated.UnixNano(),
+			TTL:                   120,
+			ChassisID:             "de:ad:be:ef:de:ad",
+			ChassisIDType:         "MAC_ADDRESS",
+			PortID:                "Ethernet1",
+			PortIDType:            "INTERFACE_NAME",
+			PortDescription:       "uplink",
+			SystemName:            "switch1",
+			SystemDescription:     "Switch OS 1.0",
+			ManagementAddress:     "2001:db8::1",
+			ManagementAddressType: "IPV6",
+		},
+		Capabilities: &Capabilities{Capability: []Capability{
+			{
+				Name: "openconfig-lldp-types:MAC_BRIDGE",
+				State: &CapabilityState{
+					Name:    "openconfig-lldp-types:MAC_BRIDGE",
+					Enabled: true,
+				},
+			},
+			{
+				Name: "openconfig-lldp-types:ROUTER",
+				State: &CapabilityState{
+					Name: "openconfig-lldp-types:ROUTER",
+				},
+			},
+		}},
+		CustomTLVs: &CustomTLVs{TLV: []CustomTLV{{
+			Type:       127,
+			OUI:        "00-80-C2",
+			OUISubtype: "1",
+			State: &CustomTLVState{
+				Type:       127,
+				OUI:        "00-80-C2",
+				OUISubtype: "1",
+				Value:      []byte{0x00, 0x0a},
+			},
+		}}},
+	}
+
+	if got := NewNeighbor(n, discovered.Add(100*time.Second)); !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected neighbor:\n- want: %+v\n-  got: %+v", want, got)
+	}
+}
+
+func TestNeighborFrameRoundTrip(t *testing.T) {
+	var tests = []struct {
+		desc string
+		f    *lldp.Frame
+	}{
+		{
+			desc: "all fields",
+			f:    testNeighborFrame(t),
+		},
+		{
+			desc: "IPv4 network address IDs",
+			f: mustFrame(t, lldp.NewFrameBuilder().
+				ChassisID(lldp.ChassisIDSubtypeNetworkAddress, []byte{1, 192, 0, 2, 1}).
+				PortID(lldp.PortIDSubtypeNetworkAddress, []byte{1, 192, 0, 2, 2}).
+				TTL(time.Minute).
+				ManagementAddress(&lldp.ManagementAddress{
+					Family:             lldp.AddressFamilyIPv4,
+					Address:            []byte{192, 0, 2, 1},
+					InterfaceNumbering: lldp.InterfaceNumberingUnknown,
+				})),
+		},
+		{
+			desc: "local IDs",
+			f: mustFrame(t, lldp.NewFrameBuilder().
+				ChassisLocal("sw1").
+				PortID(lldp.PortIDSubtypeLocallyAssigned, []byte("1/1")).
+				TTL(0)),
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		n := NewNeighbor(&agent.Neighbor{Frame: tt.f}, time.Now())
+
+		f, err := n.Frame()
+		if err != nil {
+			t.Fatalf("failed to convert neighbor: %v", err)
+		}
+
+		if want, got := tt.f, f; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected frame:\n- want: %+v\n-  got: %+v", want, got)
+		}
+	}
+}
+
+func TestNeighborFrameErrors(t *testing.T) {
+	valid := func() *NeighborState {
+		return &NeighborState{
+			ChassisID:     "sw1",
+			ChassisIDType: "LOCAL",
+			PortID:        "Ethernet1",
+			PortIDType:    "INTERFACE_NAME",
+		}
+	}
+
+	var tests = []struct {
+		desc string
+		n    Neighbor
+	}{
+		{
+			desc: "no state",
+		},
+		{
+			desc: "unknown chassis ID type",
+			n: Neighbor{State: func() *NeighborState {
+				st := valid()
+				st.ChassisIDType = "FOO"
+				return st
+			}()},
+		},
+		{
+			desc: "invalid MAC address port ID",
+			n: Neighbor{State: func() *NeighborState {
+				st := valid()
+				st.PortIDType = "MAC_ADDRESS"
+				return st
+			}()},
+		},
+		{
+			desc: "invalid management address",
+			n: Neighbor{State: func() *NeighborState {
+				st := valid()
+				st.ManagementAddress = "foo"
+				return st
+			}()},
+		},
+		{
+			desc: "unknown capability",
+			n: Neighbor{
+				State: valid(),
+				Capabilities: &Capabilities{Capability: []Capability{
+					{Name: "openconfig-lldp-types:FOO"},
+				}},
+			},
+		},
+		{
+			desc: "invalid OUI",
+			n: Neighbor{
+				State: valid(),
+				CustomTLVs: &CustomTLVs{TLV: []CustomTLV{
+					{Type: 127, OUI: "00-80", OUISubtype: "1"},
+				}},
+			},
+		},
+		{
+			desc: "invalid OUI subtype",
+			n: Neighbor{
+				State: valid(),
+				CustomTLVs: &CustomTLVs{TLV: []CustomTLV{
+					{Type: 127, OUI: "00-80-C2", OUISubtype: "256"},
+				}},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		if _, err := tt.n.Frame(); err == nil {
+			t.Fatal("expected an error, but none occurred")
+		}
+	}
+}
+
+// testNeighborFrame returns a frame which uses every field of a Neighbor.
+func testNeighborFrame(t *testing.T) *lldp.Frame {
+	t.Helper()
+
+	return mustFrame(t, lldp.NewFrameBuilder().
+		ChassisMAC(net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad}).
+		PortName("Ethernet1").
+		TTL(2*time.Minute).
+		PortDescription("uplink").
+		SystemName("switch1").
+		SystemDescription("Switch OS 1.0").
+		SystemCapabilities(lldp.CapabilityBridge|lldp.CapabilityRouter, lldp.CapabilityBridge).
+		ManagementAddress(&lldp.ManagementAddress{
+			Family:             lldp.AddressFamilyIPv6,
+			Address:            net.ParseIP("2001:db8::1"),
+			InterfaceNumbering: lldp.InterfaceNumberingUnknown,
+		}).
+		OrgSpecific(lldp.OUIIEEE8021, 1, []byte{0x00, 0x0a}))
+}
+
+func mustFrame(t *testing.T, b *lldp.FrameBuilder) *lldp.Frame {
+	t.Helper()
+
+	f, err := b.Frame()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return f
+}
@@ -0,0 +1,212 @@
+// Package openconfig converts the state of an LLDP agent to and from the
+// openconfig-lldp YANG model, encoded as RFC 7951 JSON.
+//
+// The types in this package mirror the containers and lists of the model,
+// so a Document may be encoded with encoding/json and served directly by a
+// gNMI server.  64-bit counters are encoded as strings, as required by
+// RFC 7951.
+package openconfig
+
+import (
+	"sort"
+	"time"
+
+	"github.com/mdlayher/lldp"
+	"github.com/mdlayher/lldp/agent"
+	"github.com/mdlayher/lldp/internal/format"
+)
+
+// A Source provides the state converted by New.  It is implemented by
+// *agent.Agent.
+type Source interface {
+	Neighbors() *agent.NeighborTable
+	LocalFrames() map[string]*lldp.Frame
+	Stats() map[string]agent.PortStats
+	AdminStatus() map[string]agent.AdminStatus
+}
+
+var _ Source = &agent.Agent{}
+
+// A Document is the root of an openconfig-lldp data tree.
+type Document struct {
+	LLDP *LLDP `json:"openconfig-lldp:lldp"`
+}
+
+// LLDP is the top-level lldp container.
+type LLDP struct {
+	Config     *SystemConfig `json:"config,omitempty"`
+	State      *SystemState  `json:"state,omitempty"`
+	Interfaces *Interfaces   `json:"interfaces,omitempty"`
+}
+
+// SystemConfig is the config container of the lldp container.
+type SystemConfig struct {
+	Enabled           bool   `json:"enabled"`
+	SystemName        string `json:"system-name,omitempty"`
+	SystemDescription string `json:"system-description,omitempty"`
+	ChassisID         string `json:"chassis-id,omitempty"`
+	ChassisIDType     string `json:"chassis-id-type,omitempty"`
+}
+
+// SystemState is the state container of the lldp container.
+type SystemState struct {
+	SystemConfig
+	Counters *SystemCounters `json:"counters,omitempty"`
+}
+
+// Counters are the statistics of an interface.
+type Counters struct {
+	FrameIn      uint64 `json:"frame-in,string"`
+	FrameOut     uint64 `json:"frame-out,string"`
+	FrameErrorIn uint64 `json:"frame-error-in,string"`
+	FrameDiscard uint64 `json:"frame-discard,string"`
+	TLVDiscard   uint64 `json:"tlv-discard,string"`
+	TLVUnknown   uint64 `json:"tlv-unknown,string"`
+}
+
+// SystemCounters are the statistics of all interfaces.
+type SystemCounters struct {
+	Counters
+	EntriesAgedOut uint64 `json:"entries-aged-out,string"`
+}
+
+// Interfaces is the interfaces container of the lldp container.
+type Interfaces struct {
+	Interface []Interface `json:"interface"`
+}
+
+// An Interface is an entry in the interfaces list.
+type Interface struct {
+	Name      string           `json:"name"`
+	Config    *InterfaceConfig `json:"config,omitempty"`
+	State     *InterfaceState  `json:"state,omitempty"`
+	Neighbors *Neighbors       `json:"neighbors,omitempty"`
+}
+
+// InterfaceConfig is the config container of an Interface.
+type InterfaceConfig struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+}
+
+// InterfaceState is the state container of an Interface.
+type InterfaceState struct {
+	InterfaceConfig
+	Counters *Counters `json:"counters,omitempty"`
+}
+
+// Neighbors is the neighbors container of an Interface.
+type Neighbors struct {
+	Neighbor []Neighbor `json:"neighbor"`
+}
+
+// New converts the state of src into a Document, using now to compute the
+// age of each neighbor.
+//
+// The document contains an interface for each port configured in src, in
+// lexical order.  A port is enabled unless its AdminStatus is
+// agent.AdminStatusDisabled, and LLDP is enabled if any port is enabled.
+// The system-wide chassis ID, system name, and system description are taken
+// from the local frame of the first port which has one.
+func New(src Source, now time.Time) *Document {
+	var (
+		status = src.AdminStatus()
+		frames = src.LocalFrames()
+		stats  = src.Stats()
+	)
+
+	names := make([]string, 0, len(status))
+	for name := range status {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	neighbors := make(map[string][]Neighbor)
+	for _, n := range src.Neighbors().Neighbors() {
+		neighbors[n.Interface] = append(neighbors[n.Interface], NewNeighbor(n, now))
+	}
+
+	var (
+		sys      SystemConfig
+		counters SystemCounters
+		local    bool
+	)
+
+	ifis := make([]Interface, 0, len(names))
+	for _, name := range names {
+		cfg := InterfaceConfig{
+			Name:    name,
+			Enabled: status[name] != agent.AdminStatusDisabled,
+		}
+		sys.Enabled = sys.Enabled || cfg.Enabled
+
+		if f, ok := frames[name]; ok && !local {
+			local = true
+			setSystem(&sys, f)
+		}
+
+		st := stats[name]
+		c := Counters{
+			FrameIn:      st.FramesInTotal,
+			FrameOut:     st.FramesOutTotal,
+			FrameErrorIn: st.FramesInErrorsTotal,
+			FrameDiscard: st.FramesDiscardedTotal,
+			TLVDiscard:   st.TLVsDiscardedTotal,
+			TLVUnknown:   st.TLVsUnrecognizedTotal,
+		}
+		counters.add(c)
+		counters.EntriesAgedOut += st.AgeoutsTotal
+
+		ifi := Interface{
+			Name:   name,
+			Config: &cfg,
+			State: &InterfaceState{
+				InterfaceConfig: cfg,
+				Counters:        &c,
+			},
+		}
+		if nn := neighbors[name]; len(nn) > 0 {
+			ifi.Neighbors = &Neighbors{Neighbor: nn}
+		}
+
+		ifis = append(ifis, ifi)
+	}
+
+	return &Document{
+		LLDP: &LLDP{
+			Config: &sys,
+			State: &SystemState{
+				SystemConfig: sys,
+				Counters:     &counters,
+			},
+			Interfaces: &Interfaces{Interface: ifis},
+		},
+	}
+}
+
+// setSystem sets the system-wide identity in c from a local frame.
+func setSystem(c *SystemConfig, f *lldp.Frame) {
+	if id := f.ChassisID; id != nil {
+		_, c.ChassisID = format.ChassisID(id)
+		c.ChassisIDType = ChassisIDType(id.Subtype)
+	}
+
+	for _, t := range f.Optional {
+		switch t.Type {
+		case lldp.TLVTypeSystemName:
+			c.SystemName = string(t.Value)
+		case lldp.TLVTypeSystemDescription:
+			c.SystemDescription = string(t.Value)
+		}
+	}
+}
+
+// add adds the counters of a single interface to c.
+func (c *SystemCounters) add(ic Counters) {
+	c.FrameIn += ic.FrameIn
+	c.FrameOut += ic.FrameOut
+	c.FrameErrorIn += ic.FrameErrorIn
+	c.FrameDiscard += ic.FrameDiscard
+	c.TLVDiscard += ic.TLVDiscard
+	c.TLVUnknown += ic.TLVUnknown
+}
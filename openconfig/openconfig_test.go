@@ -0,0 +1,194 @@
+package openconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/lldp"
+	"github.com/mdlayher/lldp/agent"
+)
+
+func TestNewJSON(t *testing.T) {
+	src := newTestSource(t)
+
+	b, err := json.MarshalIndent(New(src, time.Now()), "", "  ")
+	if err != nil {
+		t.Fatalf("failed to marshal document: %v", err)
+	}
+
+	want := `{
+  "openconfig-lldp:lldp": {
+    "config": {
+      "enabled": true,
+      "system-name": "host1",
+      "system-description": "Linux host1",
+      "chassis-id": "host1",
+      "chassis-id-type": "LOCAL"
+    },
+    "state": {
+      "enabled": true,
+      "system-name": "host1",
+      "system-description": "Linux host1",
+      "chassis-id": "host1",
+      "chassis-id-type": "LOCAL",
+      "counters": {
+        "frame-in": "8",
+        "frame-out": "12",
+        "frame-error-in": "1",
+        "frame-discard": "1",
+        "tlv-discard": "2",
+        "tlv-unknown": "3",
+        "entries-aged-out": "4"
+      }
+    },
+    "interfaces": {
+      "interface": [
+        {
+          "name": "eth0",
+          "config": {
+            "name": "eth0",
+            "enabled": true
+          },
+          "state": {
+            "name": "eth0",
+            "enabled": true,
+            "counters": {
+              "frame-in": "8",
+              "frame-out": "10",
+              "frame-error-in": "1",
+              "frame-discard": "1",
+              "tlv-discard": "2",
+              "tlv-unknown": "3"
+            }
+          }
+        },
+        {
+          "name": "eth1",
+          "config": {
+            "name": "eth1",
+            "enabled": false
+          },
+          "state": {
+            "name": "eth1",
+            "enabled": false,
+            "counters": {
+              "frame-in": "0",
+              "frame-out": "2",
+              "frame-error-in": "0",
+              "frame-discard": "0",
+              "tlv-discard": "0",
+              "tlv-unknown": "0"
+            }
+          }
+        }
+      ]
+    }
+  }
+}`
+
+	if got := string(b); want != got {
+		t.Fatalf("unexpected JSON:\n- want: %s\n-  got: %s", want, got)
+	}
+}
+
+func TestNewNeighbors(t *testing.T) {
+	src := newTestSource(t)
+	src.nt.Update("eth0", &lldp.EthernetFrame{
+		Source: net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad},
+		Frame:  testNeighborFrame(t),
+	})
+
+	n := src.nt.Neighbors()[0]
+	d := New(src, n.Discovered.Add(30*time.Second))
+
+	ifis := d.LLDP.Interfaces.Interface
+	if ifis[0].Neighbors == nil || len(ifis[0].Neighbors.Neighbor) != 1 {
+		t.Fatalf("expected one neighbor on eth0, but got: %+v", ifis[0].Neighbors)
+	}
+	if ifis[1].Neighbors != nil {
+		t.Fatalf("expected no neighbors on eth1, but got: %+v", ifis[1].Neighbors)
+	}
+
+	got := ifis[0].Neighbors.Neighbor[0]
+	if want := NewNeighbor(n, n.Discovered.Add(30*time.Second)); want.ID != got.ID || *want.State != *got.State {
+		t.Fatalf("unexpected neighbor:\n- want: %+v\n-  got: %+v", want.State, got.State)
+	}
+	if want, got := uint64(30), got.State.Age; want != got {
+		t.Fatalf("unexpected neighbor age: %d != %d", want, got)
+	}
+
+	// The document must decode back into the same neighbor.
+	b, err := json.Marshal(d)
+	if err != nil {
+		t.Fatalf("failed to marshal document: %v", err)
+	}
+	if !bytes.Contains(b, []byte(`"value":"AAo="`)) {
+		t.Fatalf("custom TLV value is not base64 encoded: %s", b)
+	}
+
+	var out Document
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("failed to unmarshal document: %v", err)
+	}
+
+	f, err := out.LLDP.Interfaces.Interface[0].Neighbors.Neighbor[0].Frame()
+	if err != nil {
+		t.Fatalf("failed to convert neighbor: %v", err)
+	}
+	if !reflect.DeepEqual(n.Frame, f) {
+		t.Fatalf("unexpected frame:\n- want: %+v\n-  got: %+v", n.Frame, f)
+	}
+}
+
+// A testSource is a Source for a host with two interfaces, eth0 and eth1,
+// where eth1 is disabled.
+type testSource struct {
+	nt    *agent.NeighborTable
+	local *lldp.Frame
+}
+
+func newTestSource(t *testing.T) *testSource {
+	t.Helper()
+
+	return &testSource{
+		nt: agent.NewNeighborTable(),
+		local: mustFrame(t, lldp.NewFrameBuilder().
+			ChassisLocal("host1").
+			PortName("eth0").
+			TTL(2*time.Minute).
+			SystemName("host1").
+			SystemDescription("Linux host1")),
+	}
+}
+
+func (s *testSource) Neighbors() *agent.NeighborTable { return s.nt }
+
+func (s *testSource) LocalFrames() map[string]*lldp.Frame {
+	return map[string]*lldp.Frame{"eth0": s.local}
+}
+
+func (s *testSource) Stats() map[string]agent.PortStats {
+	return map[string]agent.PortStats{
+		"eth0": {
+			FramesOutTotal:        10,
+			FramesInTotal:         8,
+			FramesDiscardedTotal:  1,
+			FramesInErrorsTotal:   1,
+			TLVsDiscardedTotal:    2,
+			TLVsUnrecognizedTotal: 3,
+			AgeoutsTotal:          4,
+		},
+		"eth1": {FramesOutTotal: 2},
+	}
+}
+
+func (s *testSource) AdminStatus() map[string]agent.AdminStatus {
+	return map[string]agent.AdminStatus{
+		"eth0": agent.AdminStatusTxRx,
+		"eth1": agent.AdminStatusDisabled,
+	}
+}
@@ -0,0 +1,61 @@
+package lldp
+
+// Unrecognized reports whether a TLV has a type reserved for future
+// standardization (types 9 through 126), which a receiver cannot interpret.
+func (t *TLV) Unrecognized() bool {
+	return t.Type > TLVTypeManagementAddress && t.Type < TLVTypeOrganizationSpecific
+}
+
+// CheckOptional sorts a Frame's optional TLVs as a receiving LLDP agent
+// would, for use when storing a neighbor's information and maintaining
+// statistics:
+//   - valid TLVs are well-formed TLVs of a known type, or organizationally
+//     specific TLVs
+//   - unrecognized TLVs have a reserved type, as reported by Unrecognized
+//   - discarded TLVs are malformed, or repeat a TLV which may only appear
+//     once in a Frame
+//
+// Each TLV appears in exactly one of the returned slices, in its original
+// order.  The Frame is not modified.
+func (f *Frame) CheckOptional() (valid, unrecognized, discarded []*TLV) {
+	seen := make(map[TLVType]bool)
+	for _, t := range f.Optional {
+		switch {
+		case t.Unrecognized():
+			unrecognized = append(unrecognized, t)
+		case !wellFormed(t):
+			discarded = append(discarded, t)
+		case t.Type != TLVTypeManagementAddress && t.Type != TLVTypeOrganizationSpecific && seen[t.Type]:
+			// Only management address and organizationally specific TLVs
+			// may appear more than once.
+			discarded = append(discarded, t)
+		default:
+			seen[t.Type] = true
+			valid = append(valid, t)
+		}
+	}
+
+	return valid, unrecognized, discarded
+}
+
+// wellFormed determines if the value of an optional TLV can be interpreted
+// according to its type.
+func wellFormed(t *TLV) bool {
+	if int(t.Length) != len(t.Value) {
+		return false
+	}
+
+	switch t.Type {
+	case TLVTypePortDescription, TLVTypeSystemName, TLVTypeSystemDescription:
+		return true
+	case TLVTypeSystemCapabilities:
+		return (&SystemCapabilities{}).UnmarshalBinary(t.Value) == nil
+	case TLVTypeManagementAddress:
+		return (&ManagementAddress{}).UnmarshalBinary(t.Value) == nil
+	case TLVTypeOrganizationSpecific:
+		return (&OrganizationSpecific{}).UnmarshalBinary(t.Value) == nil
+	default:
+		// Mandatory TLVs and end of LLDPDU TLVs are never optional.
+		return false
+	}
+}
@@ -0,0 +1,81 @@
+package lldp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFrameCheckOptional(t *testing.T) {
+	tlv := func(typ TLVType, v ...byte) *TLV {
+		return &TLV{Type: typ, Length: uint16(len(v)), Value: v}
+	}
+
+	var (
+		portDesc  = tlv(TLVTypePortDescription, 'e', 't', 'h')
+		portDesc2 = tlv(TLVTypePortDescription, 'x')
+		sysCaps   = tlv(TLVTypeSystemCapabilities, 0x00, 0x14, 0x00, 0x04)
+		badCaps   = tlv(TLVTypeSystemCapabilities, 0x00, 0x14)
+		mgmt      = tlv(TLVTypeManagementAddress, 0x05, 0x01, 192, 0, 2, 1, 0x02, 0, 0, 0, 2, 0)
+		mgmt2     = tlv(TLVTypeManagementAddress, 0x05, 0x01, 192, 0, 2, 2, 0x02, 0, 0, 0, 2, 0)
+		badMgmt   = tlv(TLVTypeManagementAddress, 0x05)
+		org       = tlv(TLVTypeOrganizationSpecific, 0x00, 0x80, 0xc2, 1, 0x00, 0x01)
+		org2      = tlv(TLVTypeOrganizationSpecific, 0x00, 0x80, 0xc2, 3, 0x00, 0x01, 0)
+		badOrg    = tlv(TLVTypeOrganizationSpecific, 0x00, 0x80)
+		reserved  = tlv(9, 0x01)
+		reserved2 = tlv(126)
+		mandatory = tlv(TLVTypeTTL, 0x00, 0x78)
+		badLength = &TLV{Type: TLVTypeSystemName, Length: 4, Value: []byte("sw")}
+	)
+
+	var tests = []struct {
+		desc         string
+		optional     []*TLV
+		valid        []*TLV
+		unrecognized []*TLV
+		discarded    []*TLV
+	}{
+		{
+			desc: "no TLVs",
+		},
+		{
+			desc:     "all valid",
+			optional: []*TLV{portDesc, sysCaps, mgmt, mgmt2, org, org2},
+			valid:    []*TLV{portDesc, sysCaps, mgmt, mgmt2, org, org2},
+		},
+		{
+			desc:         "reserved types",
+			optional:     []*TLV{reserved, portDesc, reserved2},
+			valid:        []*TLV{portDesc},
+			unrecognized: []*TLV{reserved, reserved2},
+		},
+		{
+			desc:      "malformed",
+			optional:  []*TLV{badCaps, badMgmt, badOrg, mandatory, badLength, org},
+			valid:     []*TLV{org},
+			discarded: []*TLV{badCaps, badMgmt, badOrg, mandatory, badLength},
+		},
+		{
+			desc:      "duplicate",
+			optional:  []*TLV{portDesc, portDesc2, badCaps, sysCaps, sysCaps},
+			valid:     []*TLV{portDesc, sysCaps},
+			discarded: []*TLV{portDesc2, badCaps, sysCaps},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		f := &Frame{Optional: tt.optional}
+		valid, unrecognized, discarded := f.CheckOptional()
+
+		if want, got := tt.valid, valid; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected valid TLVs:\n- want: %v\n-  got: %v", want, got)
+		}
+		if want, got := tt.unrecognized, unrecognized; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected unrecognized TLVs:\n- want: %v\n-  got: %v", want, got)
+		}
+		if want, got := tt.discarded, discarded; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected discarded TLVs:\n- want: %v\n-  got: %v", want, got)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package lldp
+
+import (
+	"io"
+)
+
+// An OUI is an IEEE organizationally unique identifier, used to identify
+// the organization which defines the format of an organizationally
+// specific TLV.
+type OUI [3]byte
+
+// List of well-known OUI values used in organizationally specific TLVs.
+var (
+	// OUIIEEE8021 is the OUI used by IEEE 802.1 organizationally specific
+	// TLVs.
+	OUIIEEE8021 = OUI{0x00, 0x80, 0xc2}
+
+	// OUIIEEE8023 is the OUI used by IEEE 802.3 organizationally specific
+	// TLVs.
+	OUIIEEE8023 = OUI{0x00, 0x12, 0x0f}
+
+	// OUITIA is the OUI used by TIA (LLDP-MED) organizationally specific
+	// TLVs.
+	OUITIA = OUI{0x00, 0x12, 0xbb}
+)
+
+// An OrganizationSpecific is a structure parsed from an organizationally
+// specific TLV.  It contains information defined by the organization
+// identified by its OUI.
+type OrganizationSpecific struct {
+	// OUI specifies the organization which defines the format of this
+	// OrganizationSpecific.
+	OUI OUI
+
+	// Subtype specifies the organization-defined type of information
+	// carried in this OrganizationSpecific.
+	Subtype uint8
+
+	// Info specifies raw bytes containing organization-defined information.
+	Info []byte
+}
+
+// MarshalBinary allocates a byte slice and marshals an OrganizationSpecific
+// into binary form.
+//
+// MarshalBinary never returns an error.
+func (o *OrganizationSpecific) MarshalBinary() ([]byte, error) {
+	//  3 bytes: OUI
+	//  1 byte: subtype
+	// N bytes: information string
+	b := make([]byte, 4+len(o.Info))
+	copy(b[0:3], o.OUI[:])
+	b[3] = o.Subtype
+	copy(b[4:], o.Info)
+
+	return b, nil
+}
+
+// UnmarshalBinary unmarshals a byte slice into an OrganizationSpecific.
+//
+// If the byte slice does not contain enough data to unmarshal a valid
+// OrganizationSpecific, io.ErrUnexpectedEOF is returned.
+func (o *OrganizationSpecific) UnmarshalBinary(b []byte) error {
+	// Must indicate at least an OUI and subtype.
+	if len(b) < 4 {
+		return io.ErrUnexpectedEOF
+	}
+
+	copy(o.OUI[:], b[0:3])
+	o.Subtype = b[3]
+	o.Info = make([]byte, len(b[4:]))
+	copy(o.Info, b[4:])
+
+	return nil
+}
+
+// OrganizationSpecific returns all organizationally specific TLVs in a
+// Frame's optional TLVs which match the input OUI and subtype, in the order
+// in which they appear.
+func (f *Frame) OrganizationSpecific(oui OUI, subtype uint8) []*TLV {
+	var tt []*TLV
+	for _, t := range f.Optional {
+		if isOrganizationSpecific(t, oui, subtype) {
+			tt = append(tt, t)
+		}
+	}
+
+	return tt
+}
+
+// isOrganizationSpecific determines if a TLV is an organizationally specific
+// TLV with the input OUI and subtype.
+func isOrganizationSpecific(t *TLV, oui OUI, subtype uint8) bool {
+	if t.Type != TLVTypeOrganizationSpecific || len(t.Value) < 4 {
+		return false
+	}
+
+	return OUI{t.Value[0], t.Value[1], t.Value[2]} == oui && t.Value[3] == subtype
+}
+
+// organizationSpecificInfo unmarshals an organizationally specific TLV value
+// and returns its information string, verifying that it carries the input
+// OUI and subtype.
+func organizationSpecificInfo(b []byte, oui OUI, subtype uint8) ([]byte, error) {
+	o := new(OrganizationSpecific)
+	if err := o.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	if o.OUI != oui || o.Subtype != subtype {
+		return nil, ErrInvalidTLV
+	}
+
+	return o.Info, nil
+}
@@ -0,0 +1,139 @@
+package lldp
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestOrganizationSpecificMarshalBinary(t *testing.T) {
+	var tests = []struct {
+		desc string
+		o    *OrganizationSpecific
+		b    []byte
+	}{
+		{
+			desc: "empty OrganizationSpecific",
+			o:    &OrganizationSpecific{},
+			b:    []byte{0, 0, 0, 0},
+		},
+		{
+			desc: "IEEE 802.1, subtype 1, info",
+			o: &OrganizationSpecific{
+				OUI:     OUIIEEE8021,
+				Subtype: 1,
+				Info:    []byte{0x00, 0x01},
+			},
+			b: []byte{0x00, 0x80, 0xc2, 1, 0x00, 0x01},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		b, err := tt.o.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want, got := tt.b, b; !bytes.Equal(want, got) {
+			t.Fatalf("unexpected OrganizationSpecific bytes:\n- want: %v\n-  got: %v", want, got)
+		}
+	}
+}
+
+func TestOrganizationSpecificUnmarshalBinary(t *testing.T) {
+	var tests = []struct {
+		desc string
+		b    []byte
+		o    *OrganizationSpecific
+		err  error
+	}{
+		{
+			desc: "nil buffer",
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "OUI only",
+			b:    []byte{0x00, 0x80, 0xc2},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "IEEE 802.3, subtype 4, no info",
+			b:    []byte{0x00, 0x12, 0x0f, 4},
+			o: &OrganizationSpecific{
+				OUI:     OUIIEEE8023,
+				Subtype: 4,
+				Info:    []byte{},
+			},
+		},
+		{
+			desc: "TIA, subtype 5, info",
+			b:    []byte{0x00, 0x12, 0xbb, 5, 'f', 'o', 'o'},
+			o: &OrganizationSpecific{
+				OUI:     OUITIA,
+				Subtype: 5,
+				Info:    []byte("foo"),
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		o := new(OrganizationSpecific)
+		if err := o.UnmarshalBinary(tt.b); err != nil {
+			if want, got := tt.err, err; want != got {
+				t.Fatalf("unexpected error:\n- want: %v\n-  got: %v", want, got)
+			}
+
+			continue
+		}
+
+		if want, got := tt.o, o; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected OrganizationSpecific:\n- want: %v\n-  got: %v", want, got)
+		}
+	}
+}
+
+func TestFrameOrganizationSpecific(t *testing.T) {
+	f := &Frame{
+		Optional: []*TLV{
+			{
+				Type:   TLVTypeSystemName,
+				Length: 3,
+				Value:  []byte{0x00, 0x80, 0xc2},
+			},
+			{
+				Type:   TLVTypeOrganizationSpecific,
+				Length: 5,
+				Value:  []byte{0x00, 0x80, 0xc2, 1, 0xff},
+			},
+			{
+				Type:   TLVTypeOrganizationSpecific,
+				Length: 4,
+				Value:  []byte{0x00, 0x12, 0x0f, 1},
+			},
+			{
+				Type:   TLVTypeOrganizationSpecific,
+				Length: 3,
+				Value:  []byte{0x00, 0x80, 0xc2},
+			},
+			{
+				Type:   TLVTypeOrganizationSpecific,
+				Length: 5,
+				Value:  []byte{0x00, 0x80, 0xc2, 1, 0xfe},
+			},
+		},
+	}
+
+	tt := f.OrganizationSpecific(OUIIEEE8021, 1)
+	if want, got := 2, len(tt); want != got {
+		t.Fatalf("unexpected number of TLVs: %d != %d", want, got)
+	}
+
+	if want, got := []*TLV{f.Optional[1], f.Optional[4]}, tt; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected TLVs:\n- want: %v\n-  got: %v", want, got)
+	}
+}
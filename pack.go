@@ -0,0 +1,105 @@
+package lldp
+
+import (
+	"sort"
+)
+
+// MarshalBinaryMax allocates a byte slice and marshals a Frame into binary
+// form, no larger than max bytes.
+//
+// The mandatory TLVs are always included.  Optional TLVs are then selected
+// in the priority order recommended by IEEE 802.1AB, skipping any TLV which
+// does not fit in the remaining space:
+//   - Basic management TLVs
+//   - IEEE 802.1 organizationally specific TLVs
+//   - IEEE 802.3 organizationally specific TLVs
+//   - LLDP-MED organizationally specific TLVs
+//   - Other organizationally specific TLVs, and TLVs of unknown type
+//
+// Optional TLVs which are selected appear in the same relative order as in
+// Optional.  Any optional TLVs which did not fit are returned in dropped,
+// in the order they appear in Optional.
+//
+// If the mandatory TLVs alone do not fit in max bytes, an *EncodeError
+// wrapping ErrInvalidFrame is returned.  Otherwise, MarshalBinaryMax returns
+// the same errors as MarshalBinary.
+func (f *Frame) MarshalBinaryMax(max int) (b []byte, dropped []*TLV, err error) {
+	mandatory := &Frame{
+		ChassisID: f.ChassisID,
+		PortID:    f.PortID,
+		TTL:       f.TTL,
+	}
+
+	mb, err := mandatory.MarshalBinary()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(mb) > max {
+		return nil, nil, frameEncodeError(ErrorReasonFrameTooLarge)
+	}
+
+	// Visit optional TLVs by priority, retaining their original order
+	// within each priority.
+	order := make([]int, len(f.Optional))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return tlvPriority(f.Optional[order[i]]) < tlvPriority(f.Optional[order[j]])
+	})
+
+	keep := make([]bool, len(f.Optional))
+	n := len(mb)
+	for _, i := range order {
+		l := 2 + len(f.Optional[i].Value)
+		if n+l > max {
+			continue
+		}
+
+		keep[i] = true
+		n += l
+	}
+
+	packed := &Frame{
+		ChassisID: f.ChassisID,
+		PortID:    f.PortID,
+		TTL:       f.TTL,
+	}
+	for i, t := range f.Optional {
+		if !keep[i] {
+			dropped = append(dropped, t)
+			continue
+		}
+
+		packed.Optional = append(packed.Optional, t)
+	}
+
+	b, err = packed.MarshalBinary()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return b, dropped, nil
+}
+
+// tlvPriority returns the transmit priority of an optional TLV, where lower
+// values indicate a higher priority.
+func tlvPriority(t *TLV) int {
+	switch {
+	case t.Type >= TLVTypePortDescription && t.Type <= TLVTypeManagementAddress:
+		return 0
+	case t.Type != TLVTypeOrganizationSpecific || len(t.Value) < 3:
+		return 4
+	}
+
+	switch (OUI{t.Value[0], t.Value[1], t.Value[2]}) {
+	case OUIIEEE8021:
+		return 1
+	case OUIIEEE8023:
+		return 2
+	case OUITIA:
+		return 3
+	default:
+		return 4
+	}
+}
@@ -0,0 +1,115 @@
+package lldp
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFrameMarshalBinaryMax(t *testing.T) {
+	orgTLV := func(oui OUI, n int) *TLV {
+		v := append(oui[:], make([]byte, n-3)...)
+		return &TLV{
+			Type:   TLVTypeOrganizationSpecific,
+			Length: uint16(len(v)),
+			Value:  v,
+		}
+	}
+
+	var (
+		name  = &TLV{Type: TLVTypeSystemName, Length: 4, Value: []byte("host")}
+		vlan1 = orgTLV(OUIIEEE8021, 10)
+		vlan2 = orgTLV(OUIIEEE8021, 20)
+		mac   = orgTLV(OUIIEEE8023, 9)
+		med   = orgTLV(OUITIA, 8)
+		other = orgTLV(OUI{0x00, 0x00, 0x0c}, 4)
+	)
+
+	base := Frame{
+		ChassisID: &ChassisID{Subtype: 7, ID: []byte("a")},
+		PortID:    &PortID{Subtype: 7, ID: []byte("b")},
+		TTL:       120 * time.Second,
+	}
+
+	// Mandatory TLVs and end of LLDPDU.
+	const mandatory = 4 + 4 + 4 + 2
+
+	var tests = []struct {
+		desc     string
+		optional []*TLV
+		max      int
+		packed   []*TLV
+		dropped  []*TLV
+		err      error
+	}{
+		{
+			desc: "mandatory TLVs too large",
+			max:  mandatory - 1,
+			err:  ErrInvalidFrame,
+		},
+		{
+			desc:     "everything fits",
+			optional: []*TLV{other, med, mac, vlan1, name},
+			max:      1500,
+			packed:   []*TLV{other, med, mac, vlan1, name},
+		},
+		{
+			desc:     "only basic management fits",
+			optional: []*TLV{other, med, mac, vlan1, name},
+			max:      mandatory + 6,
+			packed:   []*TLV{name},
+			dropped:  []*TLV{other, med, mac, vlan1},
+		},
+		{
+			desc:     "IEEE 802.1 before IEEE 802.3",
+			optional: []*TLV{mac, vlan1, vlan2, name},
+			max:      mandatory + 6 + 12 + 22,
+			packed:   []*TLV{vlan1, vlan2, name},
+			dropped:  []*TLV{mac},
+		},
+		{
+			desc:     "smaller lower priority TLV fills remaining space",
+			optional: []*TLV{other, vlan1, vlan2, med},
+			max:      mandatory + 12 + 10 + 6,
+			packed:   []*TLV{other, vlan1, med},
+			dropped:  []*TLV{vlan2},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		f := base
+		f.Optional = tt.optional
+
+		b, dropped, err := f.MarshalBinaryMax(tt.max)
+		if err != nil {
+			if want, got := tt.err, err; !errors.Is(got, want) {
+				t.Fatalf("unexpected error:\n- want: %v\n-  got: %v", want, got)
+			}
+
+			continue
+		}
+
+		if len(b) > tt.max {
+			t.Fatalf("Frame exceeds maximum size: %d > %d", len(b), tt.max)
+		}
+
+		want := base
+		want.Optional = tt.packed
+		wb, err := want.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(wb, b) {
+			t.Fatalf("unexpected Frame bytes:\n- want: %v\n-  got: %v", wb, b)
+		}
+
+		if want, got := tt.dropped, dropped; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected dropped TLVs:\n- want: %v\n-  got: %v", want, got)
+		}
+	}
+}
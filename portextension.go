@@ -0,0 +1,127 @@
+package lldp
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+)
+
+const (
+	// IEEE8021SubtypePortExtension is the IEEE 802.1 organizationally
+	// specific subtype for the IEEE 802.1BR Port Extension TLV.
+	IEEE8021SubtypePortExtension uint8 = 0x0f
+
+	// ECIDMax is the maximum possible value for an E-CID carried in
+	// a PortExtension.
+	ECIDMax = 0x3fff
+)
+
+// Port indicator bits carried in a PortExtension.
+const (
+	peUpstreamPort = 1 << 7
+	peCascadePort  = 1 << 6
+)
+
+// A PortExtension is a structure parsed from an IEEE 802.1BR Port Extension
+// organizationally specific TLV.  It is advertised by bridge port extenders
+// and controlling bridges, and identifies an extended port within the
+// context of its controlling bridge.
+type PortExtension struct {
+	// UpstreamPort indicates if this port is the upstream port of a port
+	// extender, facing its controlling bridge.
+	UpstreamPort bool
+
+	// CascadePort indicates if this port is a cascade port which connects
+	// to another port extender.
+	CascadePort bool
+
+	// CSPAddress specifies the MAC address of the port extender control
+	// and status protocol (PE CSP) endpoint for this port.
+	CSPAddress net.HardwareAddr
+
+	// ECID specifies the E-channel identifier assigned to this port by
+	// its controlling bridge.
+	ECID uint16
+}
+
+// MarshalBinary allocates a byte slice and marshals a PortExtension into
+// the binary form of an organizationally specific TLV value.
+//
+// If CSPAddress is not a 6 byte MAC address or ECID is greater than ECIDMax,
+// ErrInvalidTLV is returned.
+func (p *PortExtension) MarshalBinary() ([]byte, error) {
+	if len(p.CSPAddress) != 6 || p.ECID > ECIDMax {
+		return nil, ErrInvalidTLV
+	}
+
+	info := make([]byte, 9)
+
+	//  1 byte: port indicators
+	//  6 bytes: PE CSP address
+	//  2 bytes: E-CID
+	if p.UpstreamPort {
+		info[0] |= peUpstreamPort
+	}
+	if p.CascadePort {
+		info[0] |= peCascadePort
+	}
+	copy(info[1:7], p.CSPAddress)
+	binary.BigEndian.PutUint16(info[7:9], p.ECID)
+
+	return (&OrganizationSpecific{
+		OUI:     OUIIEEE8021,
+		Subtype: IEEE8021SubtypePortExtension,
+		Info:    info,
+	}).MarshalBinary()
+}
+
+// UnmarshalBinary unmarshals an organizationally specific TLV value into
+// a PortExtension.
+//
+// If the byte slice does not contain enough data to unmarshal a valid
+// PortExtension, io.ErrUnexpectedEOF is returned.
+//
+// If the byte slice does not carry the IEEE 802.1 OUI and Port Extension
+// subtype, or the E-CID is greater than ECIDMax, ErrInvalidTLV is returned.
+func (p *PortExtension) UnmarshalBinary(b []byte) error {
+	info, err := organizationSpecificInfo(b, OUIIEEE8021, IEEE8021SubtypePortExtension)
+	if err != nil {
+		return err
+	}
+	if len(info) < 9 {
+		return io.ErrUnexpectedEOF
+	}
+
+	ecid := binary.BigEndian.Uint16(info[7:9])
+	if ecid > ECIDMax {
+		return ErrInvalidTLV
+	}
+
+	p.UpstreamPort = info[0]&peUpstreamPort != 0
+	p.CascadePort = info[0]&peCascadePort != 0
+	p.CSPAddress = make(net.HardwareAddr, 6)
+	copy(p.CSPAddress, info[1:7])
+	p.ECID = ecid
+
+	return nil
+}
+
+// PortExtension returns the IEEE 802.1BR Port Extension information carried
+// in a Frame's optional TLVs.
+//
+// If no Port Extension TLV is present, PortExtension returns nil and false.
+// Any errors encountered while unmarshaling the TLV are also reported as
+// false.
+func (f *Frame) PortExtension() (*PortExtension, bool) {
+	tt := f.OrganizationSpecific(OUIIEEE8021, IEEE8021SubtypePortExtension)
+	if len(tt) == 0 {
+		return nil, false
+	}
+
+	p := new(PortExtension)
+	if err := p.UnmarshalBinary(tt[0].Value); err != nil {
+		return nil, false
+	}
+
+	return p, true
+}
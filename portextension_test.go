@@ -0,0 +1,186 @@
+package lldp
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestPortExtensionMarshalBinary(t *testing.T) {
+	var tests = []struct {
+		desc string
+		p    *PortExtension
+		b    []byte
+		err  error
+	}{
+		{
+			desc: "no CSP address",
+			p:    &PortExtension{},
+			err:  ErrInvalidTLV,
+		},
+		{
+			desc: "E-CID too large",
+			p: &PortExtension{
+				CSPAddress: net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad},
+				ECID:       ECIDMax + 1,
+			},
+			err: ErrInvalidTLV,
+		},
+		{
+			desc: "OK, upstream port",
+			p: &PortExtension{
+				UpstreamPort: true,
+				CSPAddress:   net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad},
+				ECID:         0x0102,
+			},
+			b: []byte{
+				0x00, 0x80, 0xc2, 0x0f,
+				0x80,
+				0xde, 0xad, 0xbe, 0xef, 0xde, 0xad,
+				0x01, 0x02,
+			},
+		},
+		{
+			desc: "OK, cascade port",
+			p: &PortExtension{
+				CascadePort: true,
+				CSPAddress:  net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad},
+				ECID:        ECIDMax,
+			},
+			b: []byte{
+				0x00, 0x80, 0xc2, 0x0f,
+				0x40,
+				0xde, 0xad, 0xbe, 0xef, 0xde, 0xad,
+				0x3f, 0xff,
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		b, err := tt.p.MarshalBinary()
+		if err != nil {
+			if want, got := tt.err, err; want != got {
+				t.Fatalf("unexpected error:\n- want: %v\n-  got: %v", want, got)
+			}
+
+			continue
+		}
+
+		if want, got := tt.b, b; !bytes.Equal(want, got) {
+			t.Fatalf("unexpected PortExtension bytes:\n- want: %v\n-  got: %v", want, got)
+		}
+	}
+}
+
+func TestPortExtensionUnmarshalBinary(t *testing.T) {
+	var tests = []struct {
+		desc string
+		b    []byte
+		p    *PortExtension
+		err  error
+	}{
+		{
+			desc: "nil buffer",
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "wrong OUI",
+			b:    []byte{0x00, 0x12, 0x0f, 0x0f},
+			err:  ErrInvalidTLV,
+		},
+		{
+			desc: "wrong subtype",
+			b:    []byte{0x00, 0x80, 0xc2, 0x01},
+			err:  ErrInvalidTLV,
+		},
+		{
+			desc: "short information string",
+			b: []byte{
+				0x00, 0x80, 0xc2, 0x0f,
+				0x80,
+				0xde, 0xad, 0xbe,
+			},
+			err: io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "E-CID too large",
+			b: []byte{
+				0x00, 0x80, 0xc2, 0x0f,
+				0x80,
+				0xde, 0xad, 0xbe, 0xef, 0xde, 0xad,
+				0x40, 0x00,
+			},
+			err: ErrInvalidTLV,
+		},
+		{
+			desc: "OK",
+			b: []byte{
+				0x00, 0x80, 0xc2, 0x0f,
+				0xc0,
+				0xde, 0xad, 0xbe, 0xef, 0xde, 0xad,
+				0x00, 0x10,
+			},
+			p: &PortExtension{
+				UpstreamPort: true,
+				CascadePort:  true,
+				CSPAddress:   net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad},
+				ECID:         0x10,
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		p := new(PortExtension)
+		if err := p.UnmarshalBinary(tt.b); err != nil {
+			if want, got := tt.err, err; want != got {
+				t.Fatalf("unexpected error:\n- want: %v\n-  got: %v", want, got)
+			}
+
+			continue
+		}
+
+		if want, got := tt.p, p; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected PortExtension:\n- want: %v\n-  got: %v", want, got)
+		}
+	}
+}
+
+func TestFramePortExtension(t *testing.T) {
+	want := &PortExtension{
+		UpstreamPort: true,
+		CSPAddress:   net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad},
+		ECID:         1,
+	}
+
+	pb, err := want.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := &Frame{
+		Optional: []*TLV{{
+			Type:   TLVTypeOrganizationSpecific,
+			Length: uint16(len(pb)),
+			Value:  pb,
+		}},
+	}
+
+	got, ok := f.PortExtension()
+	if !ok {
+		t.Fatal("expected Port Extension TLV in Frame")
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected PortExtension:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	if _, ok := (&Frame{}).PortExtension(); ok {
+		t.Fatal("unexpected Port Extension TLV in empty Frame")
+	}
+}
@@ -0,0 +1,313 @@
+// Package portname parses and normalizes the interface names used by
+// network operating systems from different vendors.
+//
+// The same interface is often spelled several ways: a Cisco switch may
+// advertise "GigabitEthernet1/0/1" as its port ID and "Gi1/0/1" in its port
+// description, and an Arista switch may use "Ethernet1" and "Et1".  Parse
+// expands vendor abbreviations into a canonical type, and splits the rest
+// of the name into its numeric components, so that names may be compared
+// with Equal.
+package portname
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mdlayher/lldp"
+)
+
+// A Name is a parsed interface name.
+type Name struct {
+	// Type specifies the canonical interface type, such as
+	// "GigabitEthernet", "Port-channel", or "ge" for Junos.
+	Type string
+
+	// Ports specifies the numeric components of the name, such as
+	// slot, module, and port, in the order they appear.
+	Ports []int
+
+	// Breakout specifies the breakout channel of the interface, such as 2
+	// for "Ethernet1/1:2" or "swp1s2", or -1 if the interface is not a
+	// breakout.
+	Breakout int
+
+	// Unit specifies the subinterface or logical unit, such as 100 for
+	// "Gi1/0/1.100", or -1 if none.
+	Unit int
+}
+
+// ErrUnknown is returned when an interface name does not begin with a known
+// interface type followed by a number.
+var ErrUnknown = errors.New("portname: unknown interface name")
+
+// A style is a vendor's convention for formatting the components of an
+// interface name.
+type style int
+
+const (
+	// styleSlash separates the type and ports with nothing, and ports with
+	// slashes, as in "GigabitEthernet1/0/1:2.100".
+	styleSlash style = iota
+
+	// styleJunos separates the type and ports with a hyphen, as in
+	// "ge-0/0/1:2.0".
+	styleJunos
+
+	// styleCumulus appends the breakout channel with an "s", as in
+	// "swp1s2".
+	styleCumulus
+)
+
+// A kind is a canonical interface type and the abbreviations and vendor
+// spellings which expand to it.
+type kind struct {
+	name    string
+	aliases []string
+	style   style
+}
+
+// kinds lists the known interface types.
+var kinds = []kind{
+	// Ethernet is used by Arista EOS, Cisco NX-OS, SONiC, and Dell OS10,
+	// and by Linux as "eth".
+	{name: "Ethernet", aliases: []string{"Eth", "Et"}},
+	{name: "FastEthernet", aliases: []string{"Fa", "FastEth"}},
+	{name: "GigabitEthernet", aliases: []string{"Gi", "Gig", "GigE", "GE"}},
+	{name: "TwoGigabitEthernet", aliases: []string{"Tw", "TwoGigE"}},
+	{name: "FiveGigabitEthernet", aliases: []string{"Fi", "FiveGigE"}},
+	// Huawei VRP uses XGigabitEthernet for 10G interfaces.
+	{name: "TenGigabitEthernet", aliases: []string{"Te", "Ten", "TenGigE", "XGigabitEthernet", "XGE"}},
+	{name: "TwentyFiveGigE", aliases: []string{"Twe", "TwentyFiveGigabitEthernet", "25GE"}},
+	{name: "FortyGigabitEthernet", aliases: []string{"Fo", "FortyGigE", "40GE"}},
+	{name: "HundredGigE", aliases: []string{"Hu", "HundredGigabitEthernet", "100GE"}},
+	{name: "FourHundredGigE", aliases: []string{"FH", "FourHundredGigabitEthernet", "400GE"}},
+	// Port channels are called Bundle-Ether by IOS XR, Eth-Trunk by
+	// Huawei VRP, PortChannel by SONiC, and ae by Junos.
+	{name: "Port-channel", aliases: []string{"Po", "PortChannel", "Port-Channel", "Bundle-Ether", "BE", "Eth-Trunk", "ae"}},
+	// fxp, me, and em are Junos management interfaces.
+	{name: "Management", aliases: []string{"Mgmt", "Ma", "MgmtEth", "fxp", "me", "em"}},
+	{name: "Loopback", aliases: []string{"Lo"}},
+	{name: "Vlan", aliases: []string{"Vl", "Vlanif"}},
+	{name: "Tunnel", aliases: []string{"Tu"}},
+	// Junos physical interfaces.
+	{name: "fe", style: styleJunos},
+	{name: "ge", style: styleJunos},
+	{name: "xe", style: styleJunos},
+	{name: "et", style: styleJunos},
+	// Cumulus Linux switch ports.
+	{name: "swp", style: styleCumulus},
+}
+
+// A prefix is a spelling of an interface type, as matched by Parse.
+type prefix struct {
+	s    string
+	kind *kind
+}
+
+// prefixes lists every spelling of every interface type, longest first so
+// that Parse matches "GigabitEthernet" before "Gi".
+var prefixes = func() []prefix {
+	var pp []prefix
+	for i := range kinds {
+		k := &kinds[i]
+		for _, s := range append([]string{k.name}, k.aliases...) {
+			pp = append(pp, prefix{s: s, kind: k})
+		}
+	}
+
+	sort.SliceStable(pp, func(i, j int) bool {
+		return len(pp[i].s) > len(pp[j].s)
+	})
+
+	return pp
+}()
+
+// Parse parses an interface name, such as "Gi1/0/1", "ge-0/0/1.0", or
+// "swp1s0".  Interface types are matched without regard to case, and may be
+// separated from their numbers by spaces.  If the name is not recognized,
+// an error wrapping ErrUnknown is returned.
+func Parse(s string) (Name, error) {
+	s = strings.TrimSpace(s)
+
+	for _, p := range prefixes {
+		if len(s) <= len(p.s) || !strings.EqualFold(s[:len(p.s)], p.s) {
+			continue
+		}
+
+		rest := s[len(p.s):]
+		if p.kind.style == styleJunos {
+			if rest[0] != '-' {
+				continue
+			}
+			rest = rest[1:]
+		}
+		rest = strings.TrimLeft(rest, " ")
+
+		if n, ok := parseNumbers(rest, p.kind.style); ok {
+			n.Type = p.kind.name
+			return n, nil
+		}
+	}
+
+	return Name{}, fmt.Errorf("%w: %q", ErrUnknown, s)
+}
+
+// parseNumbers parses the numeric components which follow an interface
+// type.
+func parseNumbers(s string, st style) (Name, bool) {
+	n := Name{Breakout: -1, Unit: -1}
+
+	if i := strings.LastIndexByte(s, '.'); i != -1 {
+		unit, ok := number(s[i+1:])
+		if !ok {
+			return Name{}, false
+		}
+
+		n.Unit = unit
+		s = s[:i]
+	}
+
+	sep := ":"
+	if st == styleCumulus {
+		sep = "s"
+		s = strings.ToLower(s)
+	}
+	if i := strings.LastIndex(s, sep); i != -1 {
+		b, ok := number(s[i+1:])
+		if !ok {
+			return Name{}, false
+		}
+
+		n.Breakout = b
+		s = s[:i]
+	}
+
+	for _, f := range strings.Split(s, "/") {
+		v, ok := number(f)
+		if !ok {
+			return Name{}, false
+		}
+
+		n.Ports = append(n.Ports, v)
+	}
+
+	if st == styleCumulus && len(n.Ports) != 1 {
+		return Name{}, false
+	}
+
+	return n, true
+}
+
+// number parses a non-negative decimal number.
+func number(s string) (int, bool) {
+	if s == "" || s[0] < '0' || s[0] > '9' {
+		return 0, false
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, false
+	}
+
+	return v, true
+}
+
+// String returns the canonical spelling of a Name, such as
+// "GigabitEthernet1/0/1".
+func (n Name) String() string {
+	var st style
+	for _, k := range kinds {
+		if k.name == n.Type {
+			st = k.style
+			break
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(n.Type)
+	if st == styleJunos {
+		b.WriteByte('-')
+	}
+
+	for i, p := range n.Ports {
+		if i > 0 {
+			b.WriteByte('/')
+		}
+		b.WriteString(strconv.Itoa(p))
+	}
+
+	if n.Breakout >= 0 {
+		if st == styleCumulus {
+			b.WriteByte('s')
+		} else {
+			b.WriteByte(':')
+		}
+		b.WriteString(strconv.Itoa(n.Breakout))
+	}
+	if n.Unit >= 0 {
+		b.WriteByte('.')
+		b.WriteString(strconv.Itoa(n.Unit))
+	}
+
+	return b.String()
+}
+
+// Equal reports whether two Names refer to the same interface.
+func (n Name) Equal(m Name) bool {
+	if n.Type != m.Type || n.Breakout != m.Breakout || n.Unit != m.Unit || len(n.Ports) != len(m.Ports) {
+		return false
+	}
+
+	for i := range n.Ports {
+		if n.Ports[i] != m.Ports[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Normalize returns the canonical spelling of an interface name, such as
+// "GigabitEthernet1/0/1" for "gi 1/0/1".  If the name is not recognized, it
+// is returned with surrounding space removed.
+func Normalize(s string) string {
+	n, err := Parse(s)
+	if err != nil {
+		return strings.TrimSpace(s)
+	}
+
+	return n.String()
+}
+
+// Equal reports whether two interface names refer to the same interface.
+// Names which are not recognized by Parse are compared without regard to
+// case.
+func Equal(a, b string) bool {
+	na, erra := Parse(a)
+	nb, errb := Parse(b)
+	if erra == nil && errb == nil {
+		return na.Equal(nb)
+	}
+
+	return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+}
+
+// EqualPortID reports whether a PortID refers to the interface called
+// name.  Only port IDs with the interface name, interface alias, and
+// locally assigned subtypes are compared, as other subtypes do not carry
+// interface names.
+func EqualPortID(p *lldp.PortID, name string) bool {
+	if p == nil {
+		return false
+	}
+
+	switch p.Subtype {
+	case lldp.PortIDSubtypeInterfaceName, lldp.PortIDSubtypeInterfaceAlias, lldp.PortIDSubtypeLocallyAssigned:
+		return Equal(string(p.ID), name)
+	default:
+		return false
+	}
+}
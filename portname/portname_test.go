@@ -0,0 +1,205 @@
+package portname
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/mdlayher/lldp"
+)
+
+func TestParse(t *testing.T) {
+	var tests = []struct {
+		desc string
+		s    string
+		n    Name
+		ok   bool
+	}{
+		{
+			desc: "Cisco abbreviation",
+			s:    "Gi1/0/1",
+			n:    Name{Type: "GigabitEthernet", Ports: []int{1, 0, 1}, Breakout: -1, Unit: -1},
+			ok:   true,
+		},
+		{
+			desc: "Cisco full name with subinterface",
+			s:    "GigabitEthernet1/0/1.100",
+			n:    Name{Type: "GigabitEthernet", Ports: []int{1, 0, 1}, Breakout: -1, Unit: 100},
+			ok:   true,
+		},
+		{
+			desc: "space and lower case",
+			s:    " te 0/1 ",
+			n:    Name{Type: "TenGigabitEthernet", Ports: []int{0, 1}, Breakout: -1, Unit: -1},
+			ok:   true,
+		},
+		{
+			desc: "Arista breakout",
+			s:    "Et1/1:2",
+			n:    Name{Type: "Ethernet", Ports: []int{1, 1}, Breakout: 2, Unit: -1},
+			ok:   true,
+		},
+		{
+			desc: "Junos",
+			s:    "ge-0/0/1.0",
+			n:    Name{Type: "ge", Ports: []int{0, 0, 1}, Breakout: -1, Unit: 0},
+			ok:   true,
+		},
+		{
+			desc: "Junos channelized",
+			s:    "et-0/0/48:3",
+			n:    Name{Type: "et", Ports: []int{0, 0, 48}, Breakout: 3, Unit: -1},
+			ok:   true,
+		},
+		{
+			desc: "Junos aggregate",
+			s:    "ae10",
+			n:    Name{Type: "Port-channel", Ports: []int{10}, Breakout: -1, Unit: -1},
+			ok:   true,
+		},
+		{
+			desc: "Cumulus breakout",
+			s:    "swp1s0",
+			n:    Name{Type: "swp", Ports: []int{1}, Breakout: 0, Unit: -1},
+			ok:   true,
+		},
+		{
+			desc: "Huawei 10G",
+			s:    "XGE0/0/1",
+			n:    Name{Type: "TenGigabitEthernet", Ports: []int{0, 0, 1}, Breakout: -1, Unit: -1},
+			ok:   true,
+		},
+		{
+			desc: "SONiC port channel",
+			s:    "PortChannel0001",
+			n:    Name{Type: "Port-channel", Ports: []int{1}, Breakout: -1, Unit: -1},
+			ok:   true,
+		},
+		{
+			desc: "Linux",
+			s:    "eth0",
+			n:    Name{Type: "Ethernet", Ports: []int{0}, Breakout: -1, Unit: -1},
+			ok:   true,
+		},
+		{
+			desc: "no number",
+			s:    "Ethernet",
+		},
+		{
+			desc: "Junos without hyphen",
+			s:    "xe0/0/1",
+		},
+		{
+			desc: "unknown type",
+			s:    "enp1s0",
+		},
+		{
+			desc: "trailing junk",
+			s:    "Gi1/0/1x",
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		n, err := Parse(tt.s)
+		if !tt.ok {
+			if !errors.Is(err, ErrUnknown) {
+				t.Fatalf("expected unknown name error, but got: %v", err)
+			}
+
+			continue
+		}
+		if err != nil {
+			t.Fatalf("failed to parse: %v", err)
+		}
+
+		if want, got := tt.n, n; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected name:\n- want: %+v\n-  got: %+v", want, got)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	var tests = []struct {
+		in, out string
+	}{
+		{in: "Gi1/0/1", out: "GigabitEthernet1/0/1"},
+		{in: "hu0/0/0/1", out: "HundredGigE0/0/0/1"},
+		{in: "Po10", out: "Port-channel10"},
+		{in: "Eth1/1:4", out: "Ethernet1/1:4"},
+		{in: "xe-0/0/1.0", out: "xe-0/0/1.0"},
+		{in: "SWP12S3", out: "swp12s3"},
+		{in: " uplink ", out: "uplink"},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.in)
+
+		if want, got := tt.out, Normalize(tt.in); want != got {
+			t.Fatalf("unexpected name: %q != %q", want, got)
+		}
+	}
+}
+
+func TestEqual(t *testing.T) {
+	var tests = []struct {
+		a, b string
+		ok   bool
+	}{
+		{a: "Gi1/0/1", b: "GigabitEthernet1/0/1", ok: true},
+		{a: "Et1", b: "Ethernet1", ok: true},
+		{a: "Eth1/1", b: "ethernet 1/1", ok: true},
+		{a: "Ethernet1/1", b: "Ethernet1/1:1"},
+		{a: "Gi1/0/1", b: "Te1/0/1"},
+		{a: "Gi1/0/1", b: "Gi1/0/1.100"},
+		{a: "ge-0/0/1", b: "Gi0/0/1"},
+		{a: "uplink", b: "UPLINK", ok: true},
+		{a: "uplink", b: "Gi1/0/1"},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q == %q", i, tt.a, tt.b)
+
+		if want, got := tt.ok, Equal(tt.a, tt.b); want != got {
+			t.Fatalf("unexpected equality: %v != %v", want, got)
+		}
+		if want, got := tt.ok, Equal(tt.b, tt.a); want != got {
+			t.Fatalf("unexpected reversed equality: %v != %v", want, got)
+		}
+	}
+}
+
+func TestEqualPortID(t *testing.T) {
+	var tests = []struct {
+		desc string
+		p    *lldp.PortID
+		ok   bool
+	}{
+		{
+			desc: "nil",
+		},
+		{
+			desc: "interface name",
+			p:    &lldp.PortID{Subtype: lldp.PortIDSubtypeInterfaceName, ID: []byte("GigabitEthernet1/0/1")},
+			ok:   true,
+		},
+		{
+			desc: "locally assigned",
+			p:    &lldp.PortID{Subtype: lldp.PortIDSubtypeLocallyAssigned, ID: []byte("gi1/0/1")},
+			ok:   true,
+		},
+		{
+			desc: "MAC address",
+			p:    &lldp.PortID{Subtype: lldp.PortIDSubtypeMACAddress, ID: []byte("Gi1/0/1")},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		if want, got := tt.ok, EqualPortID(tt.p, "Gi1/0/1"); want != got {
+			t.Fatalf("unexpected equality: %v != %v", want, got)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+// Package prometheus implements a Prometheus collector which exposes the
+// neighbors discovered by an LLDP agent, and the agent's statistics.
+package prometheus
+
+import (
+	"time"
+
+	"github.com/mdlayher/lldp"
+	"github.com/mdlayher/lldp/agent"
+	"github.com/mdlayher/lldp/control"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// A Source provides the neighbors and statistics exposed by a collector.
+// It is implemented by *agent.Agent.
+type Source interface {
+	Neighbors() *agent.NeighborTable
+	Stats() map[string]agent.PortStats
+}
+
+var _ Source = &agent.Agent{}
+
+// A collector is a prometheus.Collector for a Source.
+type collector struct {
+	src Source
+	now func() time.Time
+
+	Neighbors       *prometheus.Desc
+	NeighborInfo    *prometheus.Desc
+	NeighborTTL     *prometheus.Desc
+	Ports           []portMetric
+	TableInserts    *prometheus.Desc
+	TableDeletes    *prometheus.Desc
+	TableDrops      *prometheus.Desc
+	TableAgeouts    *prometheus.Desc
+	TableLastChange *prometheus.Desc
+}
+
+// A portMetric is a per-port statistics counter and the function which
+// selects its value from agent.PortStats.
+type portMetric struct {
+	desc  *prometheus.Desc
+	value func(s agent.PortStats) uint64
+}
+
+// New creates a prometheus.Collector which exposes the neighbors and
+// statistics of src.
+func New(src Source) prometheus.Collector {
+	return newCollector(src, time.Now)
+}
+
+// newCollector creates a collector which uses now to compute the time
+// remaining until each neighbor expires.
+func newCollector(src Source, now func() time.Time) *collector {
+	var (
+		labels   = []string{"interface"}
+		neighbor = []string{"interface", "chassis_id", "port_id"}
+	)
+
+	port := func(name, help string, value func(s agent.PortStats) uint64) portMetric {
+		return portMetric{
+			desc:  prometheus.NewDesc("lldp_port_"+name, help, labels, nil),
+			value: value,
+		}
+	}
+
+	table := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc("lldp_neighbor_table_"+name, help, nil, nil)
+	}
+
+	return &collector{
+		src: src,
+		now: now,
+
+		Neighbors: prometheus.NewDesc(
+			"lldp_neighbors",
+			"Number of LLDP neighbors discovered on each interface.",
+			labels, nil,
+		),
+
+		NeighborInfo: prometheus.NewDesc(
+			"lldp_neighbor_info",
+			"Information about each LLDP neighbor, with a constant value of 1.",
+			append(neighbor, "system_name", "capabilities"), nil,
+		),
+
+		NeighborTTL: prometheus.NewDesc(
+			"lldp_neighbor_ttl_remaining_seconds",
+			"Number of seconds until the information advertised by each LLDP neighbor expires.",
+			neighbor, nil,
+		),
+
+		Ports: []portMetric{
+			port("frames_out_total", "Number of LLDP frames transmitted.",
+				func(s agent.PortStats) uint64 { return s.FramesOutTotal }),
+			port("frames_in_total", "Number of valid LLDP frames received.",
+				func(s agent.PortStats) uint64 { return s.FramesInTotal }),
+			port("frames_discarded_total", "Number of received LLDP frames discarded for any reason.",
+				func(s agent.PortStats) uint64 { return s.FramesDiscardedTotal }),
+			port("frames_in_errors_total", "Number of received LLDP frames which could not be decoded.",
+				func(s agent.PortStats) uint64 { return s.FramesInErrorsTotal }),
+			port("tlvs_discarded_total", "Number of malformed or duplicate TLVs discarded from received LLDP frames.",
+				func(s agent.PortStats) uint64 { return s.TLVsDiscardedTotal }),
+			port("tlvs_unrecognized_total", "Number of TLVs with reserved types received in LLDP frames.",
+				func(s agent.PortStats) uint64 { return s.TLVsUnrecognizedTotal }),
+			port("ageouts_total", "Number of LLDP neighbors whose information expired.",
+				func(s agent.PortStats) uint64 { return s.AgeoutsTotal }),
+		},
+
+		TableInserts: table("inserts_total", "Number of LLDP neighbors inserted into the neighbor table."),
+		TableDeletes: table("deletes_total", "Number of LLDP neighbors deleted from the neighbor table."),
+		TableDrops:   table("drops_total", "Number of LLDP neighbors dropped because the neighbor table was full."),
+		TableAgeouts: table("ageouts_total", "Number of LLDP neighbors aged out of the neighbor table."),
+		TableLastChange: table("last_change_timestamp_seconds",
+			"UNIX timestamp of the most recent change to the neighbor table."),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+	ds := []*prometheus.Desc{
+		c.Neighbors,
+		c.NeighborInfo,
+		c.NeighborTTL,
+		c.TableInserts,
+		c.TableDeletes,
+		c.TableDrops,
+		c.TableAgeouts,
+		c.TableLastChange,
+	}
+	for _, p := range c.Ports {
+		ds = append(ds, p.desc)
+	}
+
+	for _, d := range ds {
+		ch <- d
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.src.Stats()
+	nt := c.src.Neighbors()
+
+	// Report a count for every port, even those with no neighbors.
+	counts := make(map[string]int, len(stats))
+	for ifname, s := range stats {
+		counts[ifname] = 0
+
+		for _, p := range c.Ports {
+			ch <- prometheus.MustNewConstMetric(p.desc, prometheus.CounterValue,
+				float64(p.value(s)), ifname)
+		}
+	}
+
+	now := c.now()
+	for _, n := range nt.Neighbors() {
+		counts[n.Interface]++
+		c.collectNeighbor(ch, n, now)
+	}
+
+	for ifname, n := range counts {
+		ch <- prometheus.MustNewConstMetric(c.Neighbors, prometheus.GaugeValue, float64(n), ifname)
+	}
+
+	ts := nt.Stats()
+	for _, m := range []struct {
+		d *prometheus.Desc
+		v uint64
+	}{
+		{d: c.TableInserts, v: ts.Inserts},
+		{d: c.TableDeletes, v: ts.Deletes},
+		{d: c.TableDrops, v: ts.Drops},
+		{d: c.TableAgeouts, v: ts.Ageouts},
+	} {
+		ch <- prometheus.MustNewConstMetric(m.d, prometheus.CounterValue, float64(m.v))
+	}
+
+	if !ts.LastChangeTime.IsZero() {
+		ch <- prometheus.MustNewConstMetric(c.TableLastChange, prometheus.GaugeValue,
+			float64(ts.LastChangeTime.UnixNano())/float64(time.Second))
+	}
+}
+
+// collectNeighbor collects the metrics for a single neighbor.
+func (c *collector) collectNeighbor(ch chan<- prometheus.Metric, n *agent.Neighbor, now time.Time) {
+	f := control.NewFrame(n.Frame)
+
+	var sysName, caps string
+	for _, t := range f.TLVs {
+		if t.Type == "system-name" {
+			sysName = t.Value
+		}
+	}
+	for _, t := range n.Frame.Optional {
+		if t.Type != lldp.TLVTypeSystemCapabilities {
+			continue
+		}
+
+		sc := new(lldp.SystemCapabilities)
+		if err := sc.UnmarshalBinary(t.Value); err == nil {
+			caps = control.Capabilities(sc.Enabled)
+		}
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.NeighborInfo, prometheus.GaugeValue, 1,
+		n.Interface, f.ChassisID.Value, f.PortID.Value, sysName, caps)
+
+	ch <- prometheus.MustNewConstMetric(c.NeighborTTL, prometheus.GaugeValue,
+		n.Expires.Sub(now).Seconds(),
+		n.Interface, f.ChassisID.Value, f.PortID.Value)
+}
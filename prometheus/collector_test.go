@@ -0,0 +1,107 @@
+package prometheus
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/lldp"
+	"github.com/mdlayher/lldp/agent"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCollector(t *testing.T) {
+	src := &testSource{
+		nt: agent.NewNeighborTable(),
+		stats: map[string]agent.PortStats{
+			"eth0": {FramesOutTotal: 10, FramesInTotal: 8, TLVsUnrecognizedTotal: 1},
+			"eth1": {FramesOutTotal: 10, FramesDiscardedTotal: 2, FramesInErrorsTotal: 2},
+		},
+	}
+
+	f, err := lldp.NewFrameBuilder().
+		ChassisLocal("sw1").
+		PortName("Ethernet1").
+		TTL(2*time.Minute).
+		SystemName("switch1").
+		SystemCapabilities(lldp.CapabilityBridge|lldp.CapabilityRouter, lldp.CapabilityBridge).
+		Frame()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src.nt.Update("eth0", &lldp.EthernetFrame{
+		Source: net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad},
+		Frame:  f,
+	})
+
+	// Fix the current time 30 seconds after the neighbor was discovered.
+	updated := src.nt.Neighbors()[0].Updated
+	c := newCollector(src, func() time.Time { return updated.Add(30 * time.Second) })
+
+	const want = `
+# HELP lldp_neighbors Number of LLDP neighbors discovered on each interface.
+# TYPE lldp_neighbors gauge
+lldp_neighbors{interface="eth0"} 1
+lldp_neighbors{interface="eth1"} 0
+# HELP lldp_neighbor_info Information about each LLDP neighbor, with a constant value of 1.
+# TYPE lldp_neighbor_info gauge
+lldp_neighbor_info{capabilities="bridge",chassis_id="sw1",interface="eth0",port_id="Ethernet1",system_name="switch1"} 1
+# HELP lldp_neighbor_ttl_remaining_seconds Number of seconds until the information advertised by each LLDP neighbor expires.
+# TYPE lldp_neighbor_ttl_remaining_seconds gauge
+lldp_neighbor_ttl_remaining_seconds{chassis_id="sw1",interface="eth0",port_id="Ethernet1"} 90
+# HELP lldp_port_frames_out_total Number of LLDP frames transmitted.
+# TYPE lldp_port_frames_out_total counter
+lldp_port_frames_out_total{interface="eth0"} 10
+lldp_port_frames_out_total{interface="eth1"} 10
+# HELP lldp_port_frames_discarded_total Number of received LLDP frames discarded for any reason.
+# TYPE lldp_port_frames_discarded_total counter
+lldp_port_frames_discarded_total{interface="eth0"} 0
+lldp_port_frames_discarded_total{interface="eth1"} 2
+# HELP lldp_port_tlvs_unrecognized_total Number of TLVs with reserved types received in LLDP frames.
+# TYPE lldp_port_tlvs_unrecognized_total counter
+lldp_port_tlvs_unrecognized_total{interface="eth0"} 1
+lldp_port_tlvs_unrecognized_total{interface="eth1"} 0
+# HELP lldp_neighbor_table_inserts_total Number of LLDP neighbors inserted into the neighbor table.
+# TYPE lldp_neighbor_table_inserts_total counter
+lldp_neighbor_table_inserts_total 1
+`
+
+	err = testutil.CollectAndCompare(c, strings.NewReader(want),
+		"lldp_neighbors",
+		"lldp_neighbor_info",
+		"lldp_neighbor_ttl_remaining_seconds",
+		"lldp_port_frames_out_total",
+		"lldp_port_frames_discarded_total",
+		"lldp_port_tlvs_unrecognized_total",
+		"lldp_neighbor_table_inserts_total",
+	)
+	if err != nil {
+		t.Fatalf("unexpected metrics: %v", err)
+	}
+
+	// 2 interfaces * 7 port counters, 2 neighbor counts, 2 neighbor
+	// metrics, 4 table counters, and the table change timestamp.
+	if want, got := 23, testutil.CollectAndCount(c); want != got {
+		t.Fatalf("unexpected number of metrics: %d != %d", want, got)
+	}
+}
+
+func TestCollectorEmpty(t *testing.T) {
+	c := New(&testSource{nt: agent.NewNeighborTable()})
+
+	// Only the neighbor table counters are reported.
+	if want, got := 4, testutil.CollectAndCount(c); want != got {
+		t.Fatalf("unexpected number of metrics: %d != %d", want, got)
+	}
+}
+
+// A testSource is a Source with fixed statistics.
+type testSource struct {
+	nt    *agent.NeighborTable
+	stats map[string]agent.PortStats
+}
+
+func (s *testSource) Neighbors() *agent.NeighborTable   { return s.nt }
+func (s *testSource) Stats() map[string]agent.PortStats { return s.stats }
@@ -63,20 +63,20 @@ type TLV struct {
 // MarshalBinary allocates a byte slice and marshals a TLV into binary form.
 //
 // If Type is too large (greater than 127), Length is too large (greater than
-// 511), or Length does not match the actual length of Value, ErrInvalidTLV is
-// returned.
+// 511), or Length does not match the actual length of Value, an *EncodeError
+// wrapping ErrInvalidTLV is returned.
 func (t *TLV) MarshalBinary() ([]byte, error) {
 	// Must check upper limit for Type and Length
 	if t.Type > TLVTypeMax {
-		return nil, ErrInvalidTLV
+		return nil, t.encodeError(ErrorReasonTypeTooLarge)
 	}
 	if t.Length > TLVLengthMax {
-		return nil, ErrInvalidTLV
+		return nil, t.encodeError(ErrorReasonLengthTooLarge)
 	}
 
 	// Length must match actual length of Value
 	if int(t.Length) != len(t.Value) {
-		return nil, ErrInvalidTLV
+		return nil, t.encodeError(ErrorReasonLengthMismatch)
 	}
 
 	b := make([]byte, 2+len(t.Value))
@@ -96,11 +96,15 @@ func (t *TLV) MarshalBinary() ([]byte, error) {
 // UnmarshalBinary unmarshals a byte slice into a TLV.
 //
 // If the byte slice does not contain enough data to unmarshal a valid TLV,
-// io.ErrUnexpectedEOF is returned.
+// a *DecodeError wrapping io.ErrUnexpectedEOF is returned.
 func (t *TLV) UnmarshalBinary(b []byte) error {
 	// Must contain type and length values
 	if len(b) < 2 {
-		return io.ErrUnexpectedEOF
+		return &DecodeError{
+			Offset: len(b),
+			Reason: ErrorReasonTruncatedHeader,
+			Err:    io.ErrUnexpectedEOF,
+		}
 	}
 
 	//  7 bits: type
@@ -111,7 +115,12 @@ func (t *TLV) UnmarshalBinary(b []byte) error {
 
 	// Must contain at least enough bytes as indicated by length
 	if len(b[2:]) < int(t.Length) {
-		return io.ErrUnexpectedEOF
+		return &DecodeError{
+			Offset: 2,
+			Type:   t.Type,
+			Reason: ErrorReasonTruncatedValue,
+			Err:    io.ErrUnexpectedEOF,
+		}
 	}
 
 	// Copy value directly into TLV
@@ -120,3 +129,49 @@ func (t *TLV) UnmarshalBinary(b []byte) error {
 
 	return nil
 }
+
+// encodeError creates an *EncodeError for a TLV with the input reason.
+func (t *TLV) encodeError(r ErrorReason) error {
+	return &EncodeError{
+		Type:   t.Type,
+		Reason: r,
+		Err:    ErrInvalidTLV,
+	}
+}
+
+// parseTLVs unmarshals a byte slice into a series of TLVs, returning each
+// TLV along with its byte offset into the input.  Parsing stops after the
+// first end of LLDPDU TLV, and any bytes which follow it are returned as
+// trailing bytes.
+func parseTLVs(b []byte) ([]*TLV, []int, []byte, error) {
+	var (
+		tt   []*TLV
+		offs []int
+	)
+
+	// Iterate and keep creating TLVs as long as bytes remain
+	l := 0
+	for len(b[l:]) > 0 {
+		// Unmarshal a single TLV, adding context to any errors
+		t := new(TLV)
+		if err := t.UnmarshalBinary(b[l:]); err != nil {
+			if de, ok := err.(*DecodeError); ok {
+				de.Offset += l
+				de.Index = len(tt)
+			}
+
+			return nil, nil, nil, err
+		}
+
+		// Advance to next TLV and keep looping until end of LLDPDU
+		tt = append(tt, t)
+		offs = append(offs, l)
+		l += 2 + int(t.Length)
+
+		if t.Type == TLVTypeEnd {
+			break
+		}
+	}
+
+	return tt, offs, b[l:], nil
+}
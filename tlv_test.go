@@ -2,6 +2,7 @@ package lldp
 
 import (
 	"bytes"
+	"errors"
 	"io"
 	"reflect"
 	"testing"
@@ -61,7 +62,7 @@ func TestTLVMarshalBinary(t *testing.T) {
 
 		b, err := tt.tlv.MarshalBinary()
 		if err != nil {
-			if want, got := tt.err, err; want != got {
+			if want, got := tt.err, err; !errors.Is(got, want) {
 				t.Fatalf("unexpected error:\n- want: %v\n-  got: %v", want, got)
 			}
 
@@ -129,7 +130,7 @@ func TestTLVUnmarshalBinary(t *testing.T) {
 
 		tlv := new(TLV)
 		if err := tlv.UnmarshalBinary(tt.b); err != nil {
-			if want, got := tt.err, err; want != got {
+			if want, got := tt.err, err; !errors.Is(got, want) {
 				t.Fatalf("unexpected error:\n- want: %v\n-  got: %v", want, got)
 			}
 
@@ -0,0 +1,299 @@
+package topology
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// A Diff describes the changes between two Graphs.  Each list in a Diff is
+// sorted, so a Diff is stable for a given pair of Graphs.
+type Diff struct {
+	AddedNodes   []*Node      `json:"added_nodes,omitempty"`
+	RemovedNodes []*Node      `json:"removed_nodes,omitempty"`
+	ChangedNodes []NodeChange `json:"changed_nodes,omitempty"`
+	AddedLinks   []*Link      `json:"added_links,omitempty"`
+	RemovedLinks []*Link      `json:"removed_links,omitempty"`
+	ChangedLinks []LinkChange `json:"changed_links,omitempty"`
+	ChangedPorts []PortChange `json:"changed_ports,omitempty"`
+}
+
+// A NodeChange is a change to a single field of a Node which appears in
+// both Graphs.
+type NodeChange struct {
+	Node  string `json:"node"`
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// A LinkChange is a change to the state of a Link which appears in both
+// Graphs.
+type LinkChange struct {
+	Old *Link `json:"old"`
+	New *Link `json:"new"`
+}
+
+// A PortChange is a port which has links in both Graphs, but whose neighbors
+// changed.
+type PortChange struct {
+	Port Endpoint   `json:"port"`
+	Old  []Endpoint `json:"old"`
+	New  []Endpoint `json:"new"`
+}
+
+// Compare returns the changes from Graph before to Graph after.
+//
+// Nodes are matched by ID, and links by their pair of ends, regardless of
+// which end is A.  A link whose state or ambiguity changed is reported in
+// ChangedLinks rather than as a removal and an addition.
+func Compare(before, after *Graph) *Diff {
+	d := new(Diff)
+
+	var (
+		oldNodes = make(map[string]*Node, len(before.Nodes))
+		newNodes = make(map[string]*Node, len(after.Nodes))
+	)
+	for _, n := range before.Nodes {
+		oldNodes[n.ID] = n
+	}
+	for _, n := range after.Nodes {
+		newNodes[n.ID] = n
+	}
+
+	for _, n := range before.Nodes {
+		if _, ok := newNodes[n.ID]; !ok {
+			d.RemovedNodes = append(d.RemovedNodes, n)
+		}
+	}
+	for _, n := range after.Nodes {
+		o, ok := oldNodes[n.ID]
+		if !ok {
+			d.AddedNodes = append(d.AddedNodes, n)
+			continue
+		}
+
+		for _, f := range nodeFields {
+			if ov, nv := f.value(o), f.value(n); ov != nv {
+				d.ChangedNodes = append(d.ChangedNodes, NodeChange{
+					Node:  n.ID,
+					Field: f.name,
+					Old:   ov,
+					New:   nv,
+				})
+			}
+		}
+	}
+
+	var (
+		oldLinks = linkIndex(before.Links)
+		newLinks = linkIndex(after.Links)
+	)
+	for _, l := range before.Links {
+		if _, ok := newLinks[linkKey(l)]; !ok {
+			d.RemovedLinks = append(d.RemovedLinks, l)
+		}
+	}
+	for _, l := range after.Links {
+		o, ok := oldLinks[linkKey(l)]
+		switch {
+		case !ok:
+			d.AddedLinks = append(d.AddedLinks, l)
+		case o.State != l.State || o.Ambiguous != l.Ambiguous:
+			d.ChangedLinks = append(d.ChangedLinks, LinkChange{Old: o, New: l})
+		}
+	}
+
+	var (
+		oldPeers = peers(before.Links)
+		newPeers = peers(after.Links)
+	)
+	for e, np := range newPeers {
+		op, ok := oldPeers[e]
+		if !ok || equalEndpoints(op, np) {
+			continue
+		}
+
+		d.ChangedPorts = append(d.ChangedPorts, PortChange{
+			Port: e,
+			Old:  op,
+			New:  np,
+		})
+	}
+
+	d.sort()
+	return d
+}
+
+// sort sorts each list in a Diff.
+func (d *Diff) sort() {
+	sortNodes(d.AddedNodes)
+	sortNodes(d.RemovedNodes)
+	// Changes to the same node remain in nodeFields order.
+	sort.SliceStable(d.ChangedNodes, func(i, j int) bool {
+		return d.ChangedNodes[i].Node < d.ChangedNodes[j].Node
+	})
+
+	sortLinks(d.AddedLinks)
+	sortLinks(d.RemovedLinks)
+	sort.Slice(d.ChangedLinks, func(i, j int) bool {
+		return linkLess(d.ChangedLinks[i].New, d.ChangedLinks[j].New)
+	})
+
+	sort.Slice(d.ChangedPorts, func(i, j int) bool {
+		return less(d.ChangedPorts[i].Port, d.ChangedPorts[j].Port)
+	})
+}
+
+// Empty reports whether a Diff contains no changes.
+func (d *Diff) Empty() bool {
+	return len(d.AddedNodes) == 0 && len(d.RemovedNodes) == 0 &&
+		len(d.ChangedNodes) == 0 && len(d.AddedLinks) == 0 &&
+		len(d.RemovedLinks) == 0 && len(d.ChangedLinks) == 0 &&
+		len(d.ChangedPorts) == 0
+}
+
+// WriteText writes a Diff to w as text, one change per line.  Each line
+// begins with "+" for an addition, "-" for a removal, or "~" for a change.
+func (d *Diff) WriteText(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	for _, n := range d.RemovedNodes {
+		fmt.Fprintf(bw, "- device %s%s\n", n.ID, nodeName(n))
+	}
+	for _, n := range d.AddedNodes {
+		fmt.Fprintf(bw, "+ device %s%s\n", n.ID, nodeName(n))
+	}
+	for _, c := range d.ChangedNodes {
+		fmt.Fprintf(bw, "~ device %s %s: %q -> %q\n", c.Node, c.Field, c.Old, c.New)
+	}
+	for _, l := range d.RemovedLinks {
+		fmt.Fprintf(bw, "- link %s -- %s (%s)\n", l.A, l.B, linkState(l))
+	}
+	for _, l := range d.AddedLinks {
+		fmt.Fprintf(bw, "+ link %s -- %s (%s)\n", l.A, l.B, linkState(l))
+	}
+	for _, c := range d.ChangedLinks {
+		fmt.Fprintf(bw, "~ link %s -- %s: %s -> %s\n", c.New.A, c.New.B, linkState(c.Old), linkState(c.New))
+	}
+	for _, c := range d.ChangedPorts {
+		fmt.Fprintf(bw, "~ port %s neighbor: %s -> %s\n", c.Port, endpoints(c.Old), endpoints(c.New))
+	}
+
+	return bw.Flush()
+}
+
+// nodeFields are the fields of a Node compared by Compare.
+var nodeFields = []struct {
+	name  string
+	value func(n *Node) string
+}{
+	{name: "system_name", value: func(n *Node) string { return n.SystemName }},
+	{name: "system_description", value: func(n *Node) string { return n.SystemDescription }},
+	{name: "capabilities", value: func(n *Node) string { return n.Capabilities }},
+	{name: "enabled_capabilities", value: func(n *Node) string { return n.EnabledCapabilities }},
+}
+
+// linkKey returns a key which identifies a link by its ends, regardless of
+// their order.
+func linkKey(l *Link) [2]Endpoint {
+	if less(l.B, l.A) {
+		return [2]Endpoint{l.B, l.A}
+	}
+
+	return [2]Endpoint{l.A, l.B}
+}
+
+// linkIndex indexes links by linkKey.
+func linkIndex(ll []*Link) map[[2]Endpoint]*Link {
+	m := make(map[[2]Endpoint]*Link, len(ll))
+	for _, l := range ll {
+		m[linkKey(l)] = l
+	}
+
+	return m
+}
+
+// linkLess reports whether Link a sorts before Link b.
+func linkLess(a, b *Link) bool {
+	if a.A != b.A {
+		return less(a.A, b.A)
+	}
+
+	return less(a.B, b.B)
+}
+
+// sortNodes sorts nodes by ID.
+func sortNodes(nn []*Node) {
+	sort.Slice(nn, func(i, j int) bool {
+		return nn[i].ID < nn[j].ID
+	})
+}
+
+// sortLinks sorts links by their ends.
+func sortLinks(ll []*Link) {
+	sort.Slice(ll, func(i, j int) bool {
+		return linkLess(ll[i], ll[j])
+	})
+}
+
+// peers returns the sorted far ends of the links at each endpoint.
+func peers(ll []*Link) map[Endpoint][]Endpoint {
+	m := make(map[Endpoint][]Endpoint)
+	for _, l := range ll {
+		m[l.A] = append(m[l.A], l.B)
+		m[l.B] = append(m[l.B], l.A)
+	}
+
+	for _, ee := range m {
+		sort.Slice(ee, func(i, j int) bool {
+			return less(ee[i], ee[j])
+		})
+	}
+
+	return m
+}
+
+// equalEndpoints reports whether a and b contain the same endpoints.
+func equalEndpoints(a, b []Endpoint) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// endpoints formats a list of endpoints for WriteText.
+func endpoints(ee []Endpoint) string {
+	ss := make([]string, 0, len(ee))
+	for _, e := range ee {
+		ss = append(ss, e.String())
+	}
+
+	return strings.Join(ss, ", ")
+}
+
+// nodeName formats the system name of a node for WriteText.
+func nodeName(n *Node) string {
+	if n.SystemName == "" {
+		return ""
+	}
+
+	return fmt.Sprintf(" (%s)", n.SystemName)
+}
+
+// linkState formats the state of a link for WriteText.
+func linkState(l *Link) string {
+	if l.Ambiguous {
+		return l.State.String() + ", ambiguous"
+	}
+
+	return l.State.String()
+}
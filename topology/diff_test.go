@@ -0,0 +1,77 @@
+package topology
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mdlayher/lldp"
+)
+
+func TestCompare(t *testing.T) {
+	var (
+		sw1   = testDevice("sw1", "")
+		sw2   = testDevice("sw2", "")
+		host1 = testDevice("host1", "")
+		host2 = testDevice("host2", "")
+	)
+
+	// After maintenance, sw1 is upgraded and advertises routing, host1 is
+	// recabled from sw1 to sw2, and sw1 stops hearing from host2.
+	sw1New := sw1
+	sw1New.caps = lldp.CapabilityBridge | lldp.CapabilityRouter
+
+	before := graph(t,
+		record(t, sw1, "Ethernet1", host1, "eth0"),
+		record(t, host1, "eth0", sw1, "Ethernet1"),
+		record(t, sw1, "Ethernet2", host2, "eth0"),
+		record(t, host2, "eth0", sw1, "Ethernet2"),
+	)
+	after := graph(t,
+		record(t, sw2, "Ethernet4", host1, "eth0"),
+		record(t, host1, "eth0", sw2, "Ethernet4"),
+		record(t, host2, "eth0", sw1New, "Ethernet2"),
+		record(t, sw1New, "Ethernet3", testDevice("host3", ""), "eth0"),
+	)
+
+	d := Compare(before, after)
+
+	var buf bytes.Buffer
+	if err := d.WriteText(&buf); err != nil {
+		t.Fatalf("failed to write diff: %v", err)
+	}
+
+	want := `+ device local:host3 (host3)
++ device local:sw2 (sw2)
+~ device local:sw1 capabilities: "bridge" -> "bridge,router"
+~ device local:sw1 enabled_capabilities: "bridge" -> "bridge,router"
+- link local:host1/eth0 -- local:sw1/Ethernet1 (bidirectional)
++ link local:host1/eth0 -- local:sw2/Ethernet4 (bidirectional)
++ link local:sw1/Ethernet3 -- local:host3/eth0 (unconfirmed)
+~ link local:host2/eth0 -- local:sw1/Ethernet2: bidirectional -> unidirectional
+~ port local:host1/eth0 neighbor: local:sw1/Ethernet1 -> local:sw2/Ethernet4
+`
+
+	if got := buf.String(); want != got {
+		t.Fatalf("unexpected diff:\n- want: %s\n-  got: %s", want, got)
+	}
+
+	if d.Empty() {
+		t.Fatal("expected changes, but diff is empty")
+	}
+	if !Compare(after, after).Empty() {
+		t.Fatal("expected no changes comparing a graph to itself")
+	}
+}
+
+func graph(t *testing.T, records ...Record) *Graph {
+	t.Helper()
+
+	b := NewBuilder()
+	for _, r := range records {
+		if err := b.Add(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return b.Graph()
+}
@@ -0,0 +1,177 @@
+package topology
+
+import (
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WriteJSON writes a Graph to w as indented JSON.
+func (g *Graph) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(g)
+}
+
+// WriteDOT writes a Graph to w as an undirected Graphviz DOT graph.
+//
+// Nodes are labeled with their system names where known.  Unidirectional
+// links are drawn as dashed arrows from the end which was seen to the end
+// which saw it, unconfirmed links are dotted, and ambiguous links are red.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintln(bw, "graph lldp {")
+	for _, n := range g.Nodes {
+		label := n.ID
+		if n.SystemName != "" {
+			label = n.SystemName + "\n" + n.ID
+		}
+
+		attrs := []string{"label=" + dotQuote(label)}
+		if !n.Reported {
+			attrs = append(attrs, "style=dashed")
+		}
+
+		fmt.Fprintf(bw, "\t%s [%s];\n", dotQuote(n.ID), strings.Join(attrs, ", "))
+	}
+
+	for _, l := range g.Links {
+		attrs := []string{
+			"taillabel=" + dotQuote(l.A.Port.Value),
+			"headlabel=" + dotQuote(l.B.Port.Value),
+			"state=" + dotQuote(l.State.String()),
+		}
+
+		switch l.State {
+		case LinkUnidirectional:
+			attrs = append(attrs, "style=dashed", "dir=back")
+		case LinkUnconfirmed:
+			attrs = append(attrs, "style=dotted")
+		}
+		if l.Ambiguous {
+			attrs = append(attrs, "color=red")
+		}
+
+		fmt.Fprintf(bw, "\t%s -- %s [%s];\n",
+			dotQuote(l.A.Node), dotQuote(l.B.Node), strings.Join(attrs, ", "))
+	}
+	fmt.Fprintln(bw, "}")
+
+	return bw.Flush()
+}
+
+// dotQuote quotes s as a DOT string.
+func dotQuote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// GraphML document structure.
+type (
+	graphML struct {
+		XMLName xml.Name     `xml:"graphml"`
+		XMLNS   string       `xml:"xmlns,attr"`
+		Keys    []graphMLKey `xml:"key"`
+		Graph   graphMLGraph `xml:"graph"`
+	}
+
+	graphMLKey struct {
+		ID   string `xml:"id,attr"`
+		For  string `xml:"for,attr"`
+		Name string `xml:"attr.name,attr"`
+		Type string `xml:"attr.type,attr"`
+	}
+
+	graphMLGraph struct {
+		ID          string        `xml:"id,attr"`
+		EdgeDefault string        `xml:"edgedefault,attr"`
+		Nodes       []graphMLNode `xml:"node"`
+		Edges       []graphMLEdge `xml:"edge"`
+	}
+
+	graphMLNode struct {
+		ID   string        `xml:"id,attr"`
+		Data []graphMLData `xml:"data"`
+	}
+
+	graphMLEdge struct {
+		ID     string        `xml:"id,attr"`
+		Source string        `xml:"source,attr"`
+		Target string        `xml:"target,attr"`
+		Data   []graphMLData `xml:"data"`
+	}
+
+	graphMLData struct {
+		Key   string `xml:"key,attr"`
+		Value string `xml:",chardata"`
+	}
+)
+
+// graphMLKeys declares the attributes of GraphML nodes and edges.
+var graphMLKeys = []graphMLKey{
+	{ID: "system_name", For: "node", Name: "system_name", Type: "string"},
+	{ID: "chassis_id", For: "node", Name: "chassis_id", Type: "string"},
+	{ID: "chassis_id_subtype", For: "node", Name: "chassis_id_subtype", Type: "string"},
+	{ID: "reported", For: "node", Name: "reported", Type: "boolean"},
+	{ID: "source_port", For: "edge", Name: "source_port", Type: "string"},
+	{ID: "target_port", For: "edge", Name: "target_port", Type: "string"},
+	{ID: "state", For: "edge", Name: "state", Type: "string"},
+	{ID: "ambiguous", For: "edge", Name: "ambiguous", Type: "boolean"},
+}
+
+// WriteGraphML writes a Graph to w as an undirected GraphML document.  The
+// source and target of each edge are the A and B ends of its link.
+func (g *Graph) WriteGraphML(w io.Writer) error {
+	doc := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys:  graphMLKeys,
+		Graph: graphMLGraph{
+			ID:          "lldp",
+			EdgeDefault: "undirected",
+		},
+	}
+
+	for _, n := range g.Nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{
+			ID: n.ID,
+			Data: []graphMLData{
+				{Key: "system_name", Value: n.SystemName},
+				{Key: "chassis_id", Value: n.ChassisID.Value},
+				{Key: "chassis_id_subtype", Value: n.ChassisID.Subtype},
+				{Key: "reported", Value: strconv.FormatBool(n.Reported)},
+			},
+		})
+	}
+
+	for i, l := range g.Links {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			ID:     "e" + strconv.Itoa(i),
+			Source: l.A.Node,
+			Target: l.B.Node,
+			Data: []graphMLData{
+				{Key: "source_port", Value: l.A.Port.Value},
+				{Key: "target_port", Value: l.B.Port.Value},
+				{Key: "state", Value: l.State.String()},
+				{Key: "ambiguous", Value: strconv.FormatBool(l.Ambiguous)},
+			},
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
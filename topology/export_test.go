@@ -0,0 +1,129 @@
+package topology
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+)
+
+func TestGraphWriteDOT(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testGraph().WriteDOT(&buf); err != nil {
+		t.Fatalf("failed to write DOT: %v", err)
+	}
+
+	want := `graph lldp {
+	"local:host1" [label="host1\nlocal:host1"];
+	"local:sw1" [label="sw1\nlocal:sw1"];
+	"local:sw\"2" [label="local:sw\"2", style=dashed];
+	"local:host1" -- "local:sw1" [taillabel="eth0", headlabel="Ethernet1", state="bidirectional"];
+	"local:sw1" -- "local:host1" [taillabel="Ethernet2", headlabel="eth1", state="unidirectional", style=dashed, dir=back];
+	"local:sw1" -- "local:sw\"2" [taillabel="Ethernet3", headlabel="Ethernet1", state="unconfirmed", style=dotted, color=red];
+}
+`
+
+	if got := buf.String(); want != got {
+		t.Fatalf("unexpected DOT:\n- want: %s\n-  got: %s", want, got)
+	}
+}
+
+func TestGraphWriteGraphML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testGraph().WriteGraphML(&buf); err != nil {
+		t.Fatalf("failed to write GraphML: %v", err)
+	}
+
+	var doc graphML
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("failed to parse GraphML: %v", err)
+	}
+
+	if want, got := 3, len(doc.Graph.Nodes); want != got {
+		t.Fatalf("unexpected number of nodes: %d != %d", want, got)
+	}
+	if want, got := 3, len(doc.Graph.Edges); want != got {
+		t.Fatalf("unexpected number of edges: %d != %d", want, got)
+	}
+
+	e := doc.Graph.Edges[2]
+	if want, got := "local:sw\"2", e.Target; want != got {
+		t.Fatalf("unexpected edge target: %q != %q", want, got)
+	}
+
+	data := make(map[string]string)
+	for _, d := range e.Data {
+		data[d.Key] = d.Value
+	}
+	if data["state"] != "unconfirmed" || data["ambiguous"] != "true" || data["source_port"] != "Ethernet3" {
+		t.Fatalf("unexpected edge data: %v", data)
+	}
+}
+
+func TestGraphWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testGraph().WriteJSON(&buf); err != nil {
+		t.Fatalf("failed to write JSON: %v", err)
+	}
+
+	var g Graph
+	if err := json.Unmarshal(buf.Bytes(), &g); err != nil {
+		t.Fatalf("failed to parse JSON: %v", err)
+	}
+
+	if want, got := LinkUnidirectional, g.Links[1].State; want != got {
+		t.Fatalf("unexpected link state: %v != %v", want, got)
+	}
+	if want, got := "Ethernet2", g.Links[1].A.Port.Value; want != got {
+		t.Fatalf("unexpected port: %q != %q", want, got)
+	}
+	if !g.Nodes[0].Reported || g.Nodes[2].Reported {
+		t.Fatalf("unexpected reported nodes: %+v", g.Nodes)
+	}
+}
+
+// testGraph returns a Graph with a link in each LinkState.
+func testGraph() *Graph {
+	port := func(name string) ID {
+		return ID{Subtype: "interface-name", Value: name}
+	}
+
+	return &Graph{
+		Nodes: []*Node{
+			{
+				ID:         "local:host1",
+				ChassisID:  ID{Subtype: "local", Value: "host1"},
+				SystemName: "host1",
+				Reported:   true,
+			},
+			{
+				ID:         "local:sw1",
+				ChassisID:  ID{Subtype: "local", Value: "sw1"},
+				SystemName: "sw1",
+				Reported:   true,
+			},
+			{
+				ID:        "local:sw\"2",
+				ChassisID: ID{Subtype: "local", Value: "sw\"2"},
+			},
+		},
+		Links: []*Link{
+			{
+				A:     Endpoint{Node: "local:host1", Port: port("eth0")},
+				B:     Endpoint{Node: "local:sw1", Port: port("Ethernet1")},
+				State: LinkBidirectional,
+			},
+			{
+				A:     Endpoint{Node: "local:sw1", Port: port("Ethernet2")},
+				B:     Endpoint{Node: "local:host1", Port: port("eth1")},
+				State: LinkUnidirectional,
+			},
+			{
+				A:         Endpoint{Node: "local:sw1", Port: port("Ethernet3")},
+				B:         Endpoint{Node: "local:sw\"2", Port: port("Ethernet1")},
+				State:     LinkUnconfirmed,
+				Ambiguous: true,
+			},
+		},
+	}
+}
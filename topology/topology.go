@@ -0,0 +1,490 @@
+// Package topology builds a graph of the links between many devices from the
+// LLDP frames each device has received.
+//
+// Each device contributes Records, which pair a port on the device with the
+// frame it received on that port.  A Builder resolves the device at the far
+// end of each record by its chassis ID, or by its management addresses if
+// the device identifies itself differently than it is seen by its
+// neighbors, and pairs the records made at either end of each link.
+package topology
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/mdlayher/lldp"
+	"github.com/mdlayher/lldp/internal/format"
+)
+
+// An Identity identifies a device which contributes Records to a Builder.
+type Identity struct {
+	// ChassisID specifies the chassis ID advertised by the device.
+	ChassisID *lldp.ChassisID
+
+	// SystemName and SystemDescription specify the system name and
+	// description advertised by the device, if any.
+	SystemName        string
+	SystemDescription string
+
+	// Capabilities specifies the system capabilities advertised by the
+	// device, if any.
+	Capabilities *lldp.SystemCapabilities
+
+	// ManagementAddresses specifies the IP management addresses advertised
+	// by the device, used to recognize the device if its neighbors see
+	// a different chassis ID.
+	ManagementAddresses []net.IP
+}
+
+// NewIdentity returns the Identity advertised by a device in one of its own
+// frames, such as a frame returned by (*agent.Agent).LocalFrames.
+func NewIdentity(f *lldp.Frame) Identity {
+	id := Identity{ChassisID: f.ChassisID}
+
+	for _, t := range f.Optional {
+		switch t.Type {
+		case lldp.TLVTypeSystemName:
+			id.SystemName = strings.TrimSpace(string(t.Value))
+		case lldp.TLVTypeSystemDescription:
+			id.SystemDescription = strings.TrimSpace(string(t.Value))
+		case lldp.TLVTypeSystemCapabilities:
+			sc := new(lldp.SystemCapabilities)
+			if err := sc.UnmarshalBinary(t.Value); err == nil {
+				id.Capabilities = sc
+			}
+		case lldp.TLVTypeManagementAddress:
+			m := new(lldp.ManagementAddress)
+			if err := m.UnmarshalBinary(t.Value); err != nil {
+				continue
+			}
+
+			switch {
+			case m.Family == lldp.AddressFamilyIPv4 && len(m.Address) == net.IPv4len,
+				m.Family == lldp.AddressFamilyIPv6 && len(m.Address) == net.IPv6len:
+				id.ManagementAddresses = append(id.ManagementAddresses, net.IP(m.Address))
+			}
+		}
+	}
+
+	return id
+}
+
+// A Record is a frame received by a device on one of its ports.
+type Record struct {
+	// Local specifies the device which received Frame.
+	Local Identity
+
+	// Port specifies the port ID which the device advertises for the port
+	// on which it received Frame.
+	Port *lldp.PortID
+
+	// Frame specifies the received frame.
+	Frame *lldp.Frame
+}
+
+// ErrInvalidRecord is returned when a Record lacks a local chassis ID, local
+// port ID, or a received frame with a chassis ID and port ID.
+var ErrInvalidRecord = errors.New("topology: invalid record")
+
+// A Graph is a set of devices and the links between them.
+type Graph struct {
+	Nodes []*Node `json:"nodes"`
+	Links []*Link `json:"links"`
+}
+
+// An ID is a chassis ID or port ID, formatted for display.
+type ID struct {
+	// Subtype specifies the name of the ID subtype, such as "mac-address".
+	Subtype string `json:"subtype"`
+
+	// Value specifies the ID, formatted according to its subtype.
+	Value string `json:"value"`
+}
+
+// String returns the value of an ID.
+func (id ID) String() string {
+	return id.Value
+}
+
+// A Node is a device in a Graph.
+type Node struct {
+	// ID uniquely identifies the node, and is formed from the subtype and
+	// value of its chassis ID, such as "local:sw1".  A chassis ID whose
+	// value could be confused with that of another, such as a binary ID
+	// formatted in hexadecimal, is instead identified by its subtype and
+	// raw ID in hexadecimal, such as "local#0a0b".  The ID depends only on
+	// the chassis ID, so it is the same in every Graph.
+	ID string `json:"id"`
+
+	// ChassisID, SystemName, SystemDescription, and ManagementAddresses
+	// specify the identity of the device.
+	ChassisID           ID       `json:"chassis_id"`
+	SystemName          string   `json:"system_name,omitempty"`
+	SystemDescription   string   `json:"system_description,omitempty"`
+	ManagementAddresses []net.IP `json:"management_addresses,omitempty"`
+
+	// Capabilities and EnabledCapabilities specify the supported and
+	// enabled system capabilities of the device, as comma-separated lists
+	// of capability names such as "bridge,router", if the device
+	// advertises them.
+	Capabilities        string `json:"capabilities,omitempty"`
+	EnabledCapabilities string `json:"enabled_capabilities,omitempty"`
+
+	// Reported reports whether the device contributed records to the
+	// Graph, rather than only being seen by its neighbors.
+	Reported bool `json:"reported"`
+}
+
+// An Endpoint is one end of a Link.
+type Endpoint struct {
+	// Node specifies the ID of the Node at this end of the link.
+	Node string `json:"node"`
+
+	// Port specifies the port ID of the port at this end of the link.
+	Port ID `json:"port"`
+}
+
+// String returns an Endpoint's node ID and port ID, separated by a slash.
+func (e Endpoint) String() string {
+	return e.Node + "/" + e.Port.Value
+}
+
+// A LinkState describes which ends of a Link were seen.
+type LinkState int
+
+// List of valid LinkState values.
+const (
+	// LinkBidirectional indicates that each end of a link received frames
+	// from the other end.
+	LinkBidirectional LinkState = iota
+
+	// LinkUnidirectional indicates that only the A end of a link received
+	// frames from the B end, even though the device at the B end reported
+	// its records.
+	LinkUnidirectional
+
+	// LinkUnconfirmed indicates that the A end of a link received frames
+	// from a device at the B end which did not report its records.
+	LinkUnconfirmed
+)
+
+// linkStateNames maps LinkState values to their text form.
+var linkStateNames = map[LinkState]string{
+	LinkBidirectional:  "bidirectional",
+	LinkUnidirectional: "unidirectional",
+	LinkUnconfirmed:    "unconfirmed",
+}
+
+// String returns the text form of a LinkState.
+func (s LinkState) String() string {
+	if n, ok := linkStateNames[s]; ok {
+		return n
+	}
+
+	return fmt.Sprintf("LinkState(%d)", int(s))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s LinkState) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText parses one of "bidirectional", "unidirectional", or
+// "unconfirmed" into a LinkState.
+func (s *LinkState) UnmarshalText(b []byte) error {
+	for k, v := range linkStateNames {
+		if v == string(b) {
+			*s = k
+			return nil
+		}
+	}
+
+	return fmt.Errorf("topology: unknown link state %q", string(b))
+}
+
+// A Link is a connection between ports on two devices.
+type Link struct {
+	// A and B specify the ends of the link.  For a bidirectional link, A
+	// is the end which sorts first.  Otherwise, A is the end which
+	// received frames from B.
+	A Endpoint `json:"a"`
+	B Endpoint `json:"b"`
+
+	State LinkState `json:"state"`
+
+	// Ambiguous reports whether either end of the link also appears in
+	// another link, such as when several devices share a segment, or
+	// whether the device at the B end matched more than one reporting
+	// device by management address.
+	Ambiguous bool `json:"ambiguous"`
+}
+
+// A Builder builds a Graph from Records contributed by many devices.
+type Builder struct {
+	records []Record
+}
+
+// NewBuilder creates a new Builder.
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+// Add adds a Record to the Builder.  If the Record is incomplete, an error
+// wrapping ErrInvalidRecord is returned.
+func (b *Builder) Add(r Record) error {
+	switch {
+	case r.Local.ChassisID == nil:
+		return fmt.Errorf("%w: no local chassis ID", ErrInvalidRecord)
+	case r.Port == nil:
+		return fmt.Errorf("%w: no local port ID", ErrInvalidRecord)
+	case r.Frame == nil || r.Frame.ChassisID == nil || r.Frame.PortID == nil:
+		return fmt.Errorf("%w: no received chassis ID or port ID", ErrInvalidRecord)
+	}
+
+	b.records = append(b.records, r)
+	return nil
+}
+
+// An endpoint is a port on a node, as used while building a Graph.
+type endpoint struct {
+	node *Node
+	port string
+}
+
+// A half is a link as seen from the end which received frames.
+type half struct {
+	local, remote endpoint
+	ambiguous     bool
+}
+
+// Graph builds a Graph from the Records added so far.  Nodes are sorted by
+// ID, and links by the IDs and ports of their ends.
+func (b *Builder) Graph() *Graph {
+	var (
+		nodes = make(map[string]*Node)
+		ports = make(map[string]ID)
+	)
+
+	node := func(c *lldp.ChassisID) *Node {
+		key := format.ChassisIDKey(c)
+		n, ok := nodes[key]
+		if !ok {
+			subtype, value := format.ChassisID(c)
+			n = &Node{
+				ID:        nodeID(c),
+				ChassisID: ID{Subtype: subtype, Value: value},
+			}
+			nodes[key] = n
+		}
+
+		return n
+	}
+
+	port := func(p *lldp.PortID) string {
+		key := format.PortIDKey(p)
+		subtype, value := format.PortID(p)
+		ports[key] = ID{Subtype: subtype, Value: value}
+		return key
+	}
+
+	// Nodes for the reporting devices come first, so the far end of each
+	// record can be resolved against all of them.
+	for _, r := range b.records {
+		n := node(r.Local.ChassisID)
+		n.Reported = true
+		n.merge(r.Local)
+	}
+
+	var (
+		halves []half
+		seen   = make(map[[2]endpoint]bool)
+	)
+
+	for _, r := range b.records {
+		remote, ambiguous := resolve(nodes, r.Frame)
+		if remote == nil {
+			remote = node(r.Frame.ChassisID)
+		}
+		if !remote.Reported {
+			remote.merge(NewIdentity(r.Frame))
+		}
+
+		h := half{
+			local:     endpoint{node: nodes[format.ChassisIDKey(r.Local.ChassisID)], port: port(r.Port)},
+			remote:    endpoint{node: remote, port: port(r.Frame.PortID)},
+			ambiguous: ambiguous,
+		}
+
+		// Devices report the same neighbor repeatedly.
+		k := [2]endpoint{h.local, h.remote}
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+
+		halves = append(halves, h)
+	}
+
+	var (
+		links []*Link
+		uses  = make(map[endpoint][]*Link)
+		done  = make(map[[2]endpoint]bool)
+	)
+
+	for _, h := range halves {
+		if done[[2]endpoint{h.local, h.remote}] {
+			continue
+		}
+
+		l := &Link{
+			A:         newEndpoint(h.local, ports),
+			B:         newEndpoint(h.remote, ports),
+			Ambiguous: h.ambiguous,
+		}
+
+		switch {
+		case seen[[2]endpoint{h.remote, h.local}]:
+			l.State = LinkBidirectional
+			done[[2]endpoint{h.remote, h.local}] = true
+			if less(l.B, l.A) {
+				l.A, l.B = l.B, l.A
+			}
+		case h.remote.node.Reported:
+			l.State = LinkUnidirectional
+		default:
+			l.State = LinkUnconfirmed
+		}
+
+		links = append(links, l)
+		uses[h.local] = append(uses[h.local], l)
+		uses[h.remote] = append(uses[h.remote], l)
+	}
+
+	for _, ll := range uses {
+		if len(ll) < 2 {
+			continue
+		}
+		for _, l := range ll {
+			l.Ambiguous = true
+		}
+	}
+
+	g := &Graph{
+		Nodes: make([]*Node, 0, len(nodes)),
+		Links: links,
+	}
+	for _, n := range nodes {
+		g.Nodes = append(g.Nodes, n)
+	}
+
+	sortNodes(g.Nodes)
+	sortLinks(g.Links)
+
+	return g
+}
+
+// resolve finds the reporting device which sent f, first by chassis ID and
+// then by management address.  If more than one device matches by
+// management address, resolve returns no node and reports the ambiguity.
+func resolve(nodes map[string]*Node, f *lldp.Frame) (*Node, bool) {
+	if n, ok := nodes[format.ChassisIDKey(f.ChassisID)]; ok && n.Reported {
+		return n, false
+	}
+
+	addrs := NewIdentity(f).ManagementAddresses
+	if len(addrs) == 0 {
+		return nil, false
+	}
+
+	var matches []*Node
+	for _, n := range nodes {
+		if n.Reported && overlaps(n.ManagementAddresses, addrs) {
+			matches = append(matches, n)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, false
+	case 1:
+		return matches[0], false
+	default:
+		return nil, true
+	}
+}
+
+// newEndpoint converts an endpoint into an Endpoint.
+func newEndpoint(e endpoint, ports map[string]ID) Endpoint {
+	return Endpoint{
+		Node: e.node.ID,
+		Port: ports[e.port],
+	}
+}
+
+// less reports whether Endpoint a sorts before Endpoint b.
+func less(a, b Endpoint) bool {
+	if a.Node != b.Node {
+		return a.Node < b.Node
+	}
+
+	return a.Port.Value < b.Port.Value
+}
+
+// nodeID returns the ID of the node with chassis ID c.  Subtype names
+// contain neither ':' nor '#', so the two forms of ID cannot collide.
+func nodeID(c *lldp.ChassisID) string {
+	subtype, value := format.ChassisID(c)
+	if format.Exact(
+		c.Subtype == lldp.ChassisIDSubtypeMACAddress,
+		c.Subtype == lldp.ChassisIDSubtypeNetworkAddress,
+		c.ID,
+	) {
+		return subtype + ":" + value
+	}
+
+	return fmt.Sprintf("%s#%x", subtype, c.ID)
+}
+
+// merge adds the information in an Identity to a Node, keeping any
+// information the Node already has.
+func (n *Node) merge(id Identity) {
+	if n.SystemName == "" {
+		n.SystemName = id.SystemName
+	}
+	if n.SystemDescription == "" {
+		n.SystemDescription = id.SystemDescription
+	}
+	if n.Capabilities == "" && id.Capabilities != nil {
+		n.Capabilities = format.Capabilities(id.Capabilities.System)
+		n.EnabledCapabilities = format.Capabilities(id.Capabilities.Enabled)
+	}
+
+	n.ManagementAddresses = mergeAddresses(n.ManagementAddresses, id.ManagementAddresses)
+}
+
+// mergeAddresses appends the addresses in add which are not already in
+// addrs.
+func mergeAddresses(addrs, add []net.IP) []net.IP {
+	for _, a := range add {
+		if !overlaps(addrs, []net.IP{a}) {
+			addrs = append(addrs, a)
+		}
+	}
+
+	return addrs
+}
+
+// overlaps reports whether any address appears in both a and b.
+func overlaps(a, b []net.IP) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x.Equal(y) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
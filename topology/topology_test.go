@@ -0,0 +1,320 @@
+package topology
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/lldp"
+)
+
+func TestBuilderGraph(t *testing.T) {
+	var (
+		sw1   = testDevice("sw1", "192.0.2.1")
+		sw2   = testDevice("sw2", "192.0.2.2")
+		host1 = testDevice("host1", "")
+		host2 = testDevice("host2", "")
+		host3 = testDevice("host3", "")
+		phone = testDevice("phone", "")
+	)
+
+	// sw2 as seen by its neighbors, which identify it by MAC address.
+	sw2MAC := testDevice("", "192.0.2.2")
+	sw2MAC.chassis = &lldp.ChassisID{
+		Subtype: lldp.ChassisIDSubtypeMACAddress,
+		ID:      []byte{0xde, 0xad, 0xbe, 0xef, 0x00, 0x02},
+	}
+
+	// Devices whose chassis IDs are formatted identically: one printable,
+	// and one binary which is formatted in hexadecimal.
+	text := testDevice("", "")
+	text.chassis = &lldp.ChassisID{
+		Subtype: lldp.ChassisIDSubtypeLocallyAssigned,
+		ID:      []byte("0a0b"),
+	}
+	binary := testDevice("", "")
+	binary.chassis = &lldp.ChassisID{
+		Subtype: lldp.ChassisIDSubtypeLocallyAssigned,
+		ID:      []byte{0x0a, 0x0b},
+	}
+
+	var tests = []struct {
+		desc    string
+		records []Record
+		nodes   []string
+		links   []string
+	}{
+		{
+			desc: "bidirectional",
+			records: []Record{
+				record(t, sw1, "Ethernet1", host1, "eth0"),
+				record(t, host1, "eth0", sw1, "Ethernet1"),
+				// Duplicate records are ignored.
+				record(t, host1, "eth0", sw1, "Ethernet1"),
+			},
+			nodes: []string{"local:host1 host1 reported", "local:sw1 sw1 reported"},
+			links: []string{"local:host1/eth0 -- local:sw1/Ethernet1 bidirectional"},
+		},
+		{
+			desc: "unidirectional",
+			records: []Record{
+				record(t, sw1, "Ethernet1", host1, "eth0"),
+				record(t, host1, "eth1", host2, "eth0"),
+			},
+			nodes: []string{
+				"local:host1 host1 reported",
+				"local:host2 host2",
+				"local:sw1 sw1 reported",
+			},
+			links: []string{
+				"local:host1/eth1 -- local:host2/eth0 unconfirmed",
+				"local:sw1/Ethernet1 -- local:host1/eth0 unidirectional",
+			},
+		},
+		{
+			desc: "resolved by management address",
+			records: []Record{
+				record(t, sw2, "Ethernet2", sw1, "Ethernet1"),
+				record(t, sw1, "Ethernet1", sw2MAC, "Ethernet2"),
+			},
+			nodes: []string{"local:sw1 sw1 reported", "local:sw2 sw2 reported"},
+			links: []string{"local:sw1/Ethernet1 -- local:sw2/Ethernet2 bidirectional"},
+		},
+		{
+			desc: "ambiguous management address",
+			records: []Record{
+				record(t, sw2, "Ethernet2", host1, "eth0"),
+				record(t, testDevice("sw3", "192.0.2.2"), "Ethernet3", host1, "eth0"),
+				record(t, host1, "eth0", sw2MAC, "Ethernet2"),
+			},
+			nodes: []string{
+				"local:host1 host1 reported",
+				"local:sw2 sw2 reported",
+				"local:sw3 sw3 reported",
+				"mac-address:de:ad:be:ef:00:02",
+			},
+			links: []string{
+				"local:host1/eth0 -- mac-address:de:ad:be:ef:00:02/Ethernet2 unconfirmed ambiguous",
+				"local:sw2/Ethernet2 -- local:host1/eth0 unidirectional ambiguous",
+				"local:sw3/Ethernet3 -- local:host1/eth0 unidirectional ambiguous",
+			},
+		},
+		{
+			desc: "shared segment",
+			records: []Record{
+				record(t, sw1, "Ethernet1", host1, "eth0"),
+				record(t, sw1, "Ethernet1", host2, "eth0"),
+				record(t, host1, "eth0", sw1, "Ethernet1"),
+				record(t, host3, "eth0", phone, "lan"),
+			},
+			nodes: []string{
+				"local:host1 host1 reported",
+				"local:host3 host3 reported",
+				"local:phone phone",
+				"local:sw1 sw1 reported",
+				"local:host2 host2",
+			},
+			links: []string{
+				"local:host1/eth0 -- local:sw1/Ethernet1 bidirectional ambiguous",
+				"local:host3/eth0 -- local:phone/lan unconfirmed",
+				"local:sw1/Ethernet1 -- local:host2/eth0 unconfirmed ambiguous",
+			},
+		},
+		{
+			desc: "chassis IDs formatted identically",
+			records: []Record{
+				record(t, host1, "eth0", text, "1"),
+				record(t, host1, "eth1", binary, "1"),
+			},
+			nodes: []string{
+				"local#0a0b",
+				"local:0a0b",
+				"local:host1 host1 reported",
+			},
+			links: []string{
+				"local:host1/eth0 -- local:0a0b/1 unconfirmed",
+				"local:host1/eth1 -- local#0a0b/1 unconfirmed",
+			},
+		},
+		{
+			// The ID does not depend on the other nodes in the graph.
+			desc: "binary chassis ID alone",
+			records: []Record{
+				record(t, host1, "eth1", binary, "1"),
+			},
+			nodes: []string{"local#0a0b", "local:host1 host1 reported"},
+			links: []string{"local:host1/eth1 -- local#0a0b/1 unconfirmed"},
+		},
+		{
+			desc: "port IDs formatted identically",
+			records: []Record{
+				record(t, host1, "eth0", sw1, "0a0b"),
+				record(t, host1, "eth1", sw1, "\x0a\x0b"),
+			},
+			nodes: []string{"local:host1 host1 reported", "local:sw1 sw1"},
+			links: []string{
+				"local:host1/eth0 -- local:sw1/0a0b unconfirmed",
+				"local:host1/eth1 -- local:sw1/0a0b unconfirmed",
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		b := NewBuilder()
+		for _, r := range tt.records {
+			if err := b.Add(r); err != nil {
+				t.Fatalf("failed to add record: %v", err)
+			}
+		}
+
+		g := b.Graph()
+
+		var nodes, links []string
+		for _, n := range g.Nodes {
+			s := n.ID
+			if n.SystemName != "" {
+				s += " " + n.SystemName
+			}
+			if n.Reported {
+				s += " reported"
+			}
+			nodes = append(nodes, s)
+		}
+		for _, l := range g.Links {
+			s := fmt.Sprintf("%s/%s -- %s/%s %s", l.A.Node, l.A.Port, l.B.Node, l.B.Port, l.State)
+			if l.Ambiguous {
+				s += " ambiguous"
+			}
+			links = append(links, s)
+		}
+
+		sort.Strings(tt.nodes)
+		if want, got := tt.nodes, nodes; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected nodes:\n- want: %q\n-  got: %q", want, got)
+		}
+		if want, got := tt.links, links; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected links:\n- want: %q\n-  got: %q", want, got)
+		}
+	}
+}
+
+func TestBuilderAddInvalid(t *testing.T) {
+	valid := record(t, testDevice("sw1", ""), "Ethernet1", testDevice("host1", ""), "eth0")
+
+	var tests = []struct {
+		desc string
+		r    func(r Record) Record
+	}{
+		{
+			desc: "no local chassis ID",
+			r:    func(r Record) Record { r.Local.ChassisID = nil; return r },
+		},
+		{
+			desc: "no local port",
+			r:    func(r Record) Record { r.Port = nil; return r },
+		},
+		{
+			desc: "no frame",
+			r:    func(r Record) Record { r.Frame = nil; return r },
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		if err := NewBuilder().Add(tt.r(valid)); !errors.Is(err, ErrInvalidRecord) {
+			t.Fatalf("expected invalid record error, but got: %v", err)
+		}
+	}
+}
+
+func TestLinkStateText(t *testing.T) {
+	for _, s := range []LinkState{LinkBidirectional, LinkUnidirectional, LinkUnconfirmed} {
+		b, err := s.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got LinkState
+		if err := got.UnmarshalText(b); err != nil {
+			t.Fatal(err)
+		}
+		if want := s; want != got {
+			t.Fatalf("unexpected link state: %v != %v", want, got)
+		}
+	}
+
+	var s LinkState
+	if err := s.UnmarshalText([]byte("foo")); err == nil {
+		t.Fatal("expected an error, but none occurred")
+	}
+}
+
+// A device is a system with a locally assigned chassis ID, an optional IPv4
+// management address, and bridge capabilities.
+type device struct {
+	chassis *lldp.ChassisID
+	name    string
+	addr    net.IP
+	caps    lldp.Capability
+}
+
+func testDevice(name, addr string) device {
+	return device{
+		chassis: &lldp.ChassisID{
+			Subtype: lldp.ChassisIDSubtypeLocallyAssigned,
+			ID:      []byte(name),
+		},
+		name: name,
+		addr: net.ParseIP(addr).To4(),
+		caps: lldp.CapabilityBridge,
+	}
+}
+
+// frame returns the frame sent by d on port.
+func (d device) frame(t *testing.T, port string) *lldp.Frame {
+	t.Helper()
+
+	b := lldp.NewFrameBuilder().
+		ChassisID(d.chassis.Subtype, d.chassis.ID).
+		PortName(port).
+		TTL(2 * time.Minute)
+	if d.name != "" {
+		b.SystemName(d.name)
+	}
+	if d.caps != 0 {
+		b.SystemCapabilities(d.caps, d.caps)
+	}
+	if d.addr != nil {
+		b.ManagementAddress(&lldp.ManagementAddress{
+			Family:             lldp.AddressFamilyIPv4,
+			Address:            d.addr,
+			InterfaceNumbering: lldp.InterfaceNumberingUnknown,
+		})
+	}
+
+	f, err := b.Frame()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return f
+}
+
+// record returns the Record made when local receives a frame from remote.
+func record(t *testing.T, local device, lport string, remote device, rport string) Record {
+	t.Helper()
+
+	lf := local.frame(t, lport)
+	return Record{
+		Local: NewIdentity(lf),
+		Port:  lf.PortID,
+		Frame: remote.frame(t, rport),
+	}
+}
@@ -0,0 +1,469 @@
+package lldp
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sort"
+)
+
+// IEEE 802.1 organizationally specific subtypes used by the IEEE 802.1ABdh
+// LLDPDU extension mechanism.
+const (
+	// IEEE8021SubtypeManifest is the subtype for a Manifest TLV, which
+	// is carried in a normal LLDPDU and describes its XPDUs.
+	IEEE8021SubtypeManifest uint8 = 0x1a
+
+	// IEEE8021SubtypeXPDURequest is the subtype for an XPDU Request TLV,
+	// which is sent by a receiver to request one or more XPDUs.
+	IEEE8021SubtypeXPDURequest uint8 = 0x1b
+
+	// IEEE8021SubtypeXPDUDescriptor is the subtype for an XPDU Descriptor
+	// TLV, which identifies an XPDU.
+	IEEE8021SubtypeXPDUDescriptor uint8 = 0x1c
+)
+
+var (
+	// ErrInvalidXPDU is returned when an XPDU is invalid due to one of
+	// the following reasons:
+	//  - Any of the four mandatory TLV values are not present, or are in
+	//    an incorrect order:
+	//    - Chassis ID
+	//    - Port ID
+	//    - XPDU Descriptor
+	//    - End of XPDU
+	//  - The XPDU does not originate from the same chassis and port as
+	//    the Frame which carries its Manifest
+	//  - The XPDU is not listed in the Manifest, or its digest does not
+	//    match the digest listed in the Manifest
+	ErrInvalidXPDU = errors.New("invalid XPDU")
+
+	// ErrIncompleteFrame is returned when a Frame cannot be reassembled
+	// because one or more of the XPDUs listed in its Manifest have not
+	// been received.
+	ErrIncompleteFrame = errors.New("incomplete frame")
+)
+
+// manifestEntryLen is the length of a single entry in a Manifest TLV.
+const manifestEntryLen = 2 + sha256.Size
+
+// A ManifestEntry identifies a single XPDU by its ID, and carries the
+// SHA-256 digest of the XPDU in binary form.
+type ManifestEntry struct {
+	ID     uint16
+	Digest [sha256.Size]byte
+}
+
+// A Manifest is a structure parsed from one or more Manifest TLVs.  It lists
+// the XPDUs which carry additional information for a Frame that does not
+// fit in a single LLDPDU.
+type Manifest struct {
+	Entries []ManifestEntry
+}
+
+// MarshalBinary allocates a byte slice and marshals a Manifest into the
+// binary form of an organizationally specific TLV value.
+//
+// If the Manifest contains too many entries to fit in a single TLV,
+// ErrInvalidTLV is returned.
+func (m *Manifest) MarshalBinary() ([]byte, error) {
+	if 4+len(m.Entries)*manifestEntryLen > TLVLengthMax {
+		return nil, ErrInvalidTLV
+	}
+
+	//  2 bytes: XPDU ID
+	// 32 bytes: SHA-256 digest
+	// ... repeated for each entry
+	info := make([]byte, len(m.Entries)*manifestEntryLen)
+	for i, e := range m.Entries {
+		b := info[i*manifestEntryLen : (i+1)*manifestEntryLen]
+		binary.BigEndian.PutUint16(b[0:2], e.ID)
+		copy(b[2:], e.Digest[:])
+	}
+
+	return (&OrganizationSpecific{
+		OUI:     OUIIEEE8021,
+		Subtype: IEEE8021SubtypeManifest,
+		Info:    info,
+	}).MarshalBinary()
+}
+
+// UnmarshalBinary unmarshals an organizationally specific TLV value into
+// a Manifest.
+//
+// If the information string is not a multiple of the entry length,
+// io.ErrUnexpectedEOF is returned.
+//
+// If the byte slice does not carry the IEEE 802.1 OUI and Manifest subtype,
+// ErrInvalidTLV is returned.
+func (m *Manifest) UnmarshalBinary(b []byte) error {
+	info, err := organizationSpecificInfo(b, OUIIEEE8021, IEEE8021SubtypeManifest)
+	if err != nil {
+		return err
+	}
+	if len(info)%manifestEntryLen != 0 {
+		return io.ErrUnexpectedEOF
+	}
+
+	m.Entries = make([]ManifestEntry, len(info)/manifestEntryLen)
+	for i := range m.Entries {
+		eb := info[i*manifestEntryLen : (i+1)*manifestEntryLen]
+		m.Entries[i].ID = binary.BigEndian.Uint16(eb[0:2])
+		copy(m.Entries[i].Digest[:], eb[2:])
+	}
+
+	return nil
+}
+
+// An XPDURequest is a structure parsed from an XPDU Request TLV.  It is sent
+// by a receiver to request the XPDUs listed in a Manifest.
+type XPDURequest struct {
+	IDs []uint16
+}
+
+// MarshalBinary allocates a byte slice and marshals an XPDURequest into the
+// binary form of an organizationally specific TLV value.
+//
+// If the XPDURequest contains too many IDs to fit in a single TLV,
+// ErrInvalidTLV is returned.
+func (r *XPDURequest) MarshalBinary() ([]byte, error) {
+	if 4+len(r.IDs)*2 > TLVLengthMax {
+		return nil, ErrInvalidTLV
+	}
+
+	// 2 bytes: XPDU ID
+	// ... repeated for each ID
+	info := make([]byte, len(r.IDs)*2)
+	for i, id := range r.IDs {
+		binary.BigEndian.PutUint16(info[i*2:(i+1)*2], id)
+	}
+
+	return (&OrganizationSpecific{
+		OUI:     OUIIEEE8021,
+		Subtype: IEEE8021SubtypeXPDURequest,
+		Info:    info,
+	}).MarshalBinary()
+}
+
+// UnmarshalBinary unmarshals an organizationally specific TLV value into
+// an XPDURequest.
+//
+// If the information string does not contain an even number of bytes,
+// io.ErrUnexpectedEOF is returned.
+//
+// If the byte slice does not carry the IEEE 802.1 OUI and XPDU Request
+// subtype, ErrInvalidTLV is returned.
+func (r *XPDURequest) UnmarshalBinary(b []byte) error {
+	info, err := organizationSpecificInfo(b, OUIIEEE8021, IEEE8021SubtypeXPDURequest)
+	if err != nil {
+		return err
+	}
+	if len(info)%2 != 0 {
+		return io.ErrUnexpectedEOF
+	}
+
+	r.IDs = make([]uint16, len(info)/2)
+	for i := range r.IDs {
+		r.IDs[i] = binary.BigEndian.Uint16(info[i*2 : (i+1)*2])
+	}
+
+	return nil
+}
+
+// An XPDU is an LLDPDU extension data unit, used to carry TLVs which do not
+// fit in a single LLDPDU.  An XPDU is identified by the chassis ID and port
+// ID of the agent which sent it, and an ID listed in that agent's Manifest.
+type XPDU struct {
+	// ChassisID and PortID specify the agent which sent this XPDU, and
+	// must match those of the Frame which carries its Manifest.
+	ChassisID *ChassisID
+	PortID    *PortID
+
+	// ID specifies the ID of this XPDU, as listed in a Manifest.
+	ID uint16
+
+	// TLVs specifies zero or more TLV values carried in this XPDU.
+	TLVs []*TLV
+}
+
+// MarshalBinary allocates a byte slice and marshals an XPDU into binary form.
+//
+// If ChassisID or PortID are nil, ErrInvalidXPDU is returned.
+//
+// If any problems are detected with TLVs, ErrInvalidTLV is returned.
+func (x *XPDU) MarshalBinary() ([]byte, error) {
+	if x.ChassisID == nil || x.PortID == nil {
+		return nil, ErrInvalidXPDU
+	}
+
+	cb, err := x.ChassisID.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	pb, err := x.PortID.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	db := make([]byte, 2)
+	binary.BigEndian.PutUint16(db, x.ID)
+	xb, err := (&OrganizationSpecific{
+		OUI:     OUIIEEE8021,
+		Subtype: IEEE8021SubtypeXPDUDescriptor,
+		Info:    db,
+	}).MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	tt := make([]*TLV, 0, 4+len(x.TLVs))
+	tt = append(tt,
+		&TLV{Type: TLVTypeChassisID, Length: uint16(len(cb)), Value: cb},
+		&TLV{Type: TLVTypePortID, Length: uint16(len(pb)), Value: pb},
+		&TLV{Type: TLVTypeOrganizationSpecific, Length: uint16(len(xb)), Value: xb},
+	)
+	tt = append(tt, x.TLVs...)
+	tt = append(tt, &TLV{Type: TLVTypeEnd})
+
+	var b []byte
+	for _, t := range tt {
+		tb, err := t.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+
+		b = append(b, tb...)
+	}
+
+	return b, nil
+}
+
+// UnmarshalBinary unmarshals a byte slice into an XPDU.
+//
+// If the byte slice cannot be unmarshaled, a *DecodeError is returned which
+// describes the problem.  It wraps io.ErrUnexpectedEOF if the byte slice does
+// not contain enough data to unmarshal a valid XPDU, or ErrInvalidXPDU if the
+// four mandatory TLV values chassis ID, port ID, XPDU descriptor, and end of
+// XPDU, are missing or do not appear in order.
+func (x *XPDU) UnmarshalBinary(b []byte) error {
+	_, err := x.unmarshal(b)
+	return err
+}
+
+// unmarshal unmarshals a byte slice into an XPDU, and returns any bytes which
+// follow the end of XPDU TLV, such as Ethernet padding.
+func (x *XPDU) unmarshal(b []byte) ([]byte, error) {
+	tt, offs, trailing, err := parseTLVs(b)
+	if err != nil {
+		return nil, err
+	}
+
+	// Must have at least four mandatory TLVs, unless an early end of XPDU
+	// indicates that some are missing entirely
+	if len(tt) < 4 && (len(tt) == 0 || tt[len(tt)-1].Type != TLVTypeEnd) {
+		return nil, &DecodeError{
+			Offset: len(b),
+			Index:  -1,
+			Reason: ErrorReasonTooFewTLVs,
+			Err:    io.ErrUnexpectedEOF,
+		}
+	}
+
+	// decodeError creates a *DecodeError for the TLV at index i
+	decodeError := func(i int, r ErrorReason, err error) error {
+		return &DecodeError{
+			Offset: offs[i],
+			Index:  i,
+			Type:   tt[i].Type,
+			Reason: r,
+			Err:    err,
+		}
+	}
+
+	if tt[0].Type != TLVTypeChassisID {
+		return nil, decodeError(0, ErrorReasonMissingChassisID, ErrInvalidXPDU)
+	}
+	x.ChassisID = new(ChassisID)
+	if err := x.ChassisID.UnmarshalBinary(tt[0].Value); err != nil {
+		return nil, decodeError(0, ErrorReasonInvalidChassisID, err)
+	}
+
+	if tt[1].Type != TLVTypePortID {
+		return nil, decodeError(1, ErrorReasonMissingPortID, ErrInvalidXPDU)
+	}
+	x.PortID = new(PortID)
+	if err := x.PortID.UnmarshalBinary(tt[1].Value); err != nil {
+		return nil, decodeError(1, ErrorReasonInvalidPortID, err)
+	}
+
+	if !isOrganizationSpecific(tt[2], OUIIEEE8021, IEEE8021SubtypeXPDUDescriptor) {
+		return nil, decodeError(2, ErrorReasonMissingXPDUDescriptor, ErrInvalidXPDU)
+	}
+	if tt[2].Length != 6 {
+		return nil, decodeError(2, ErrorReasonInvalidXPDUDescriptor, ErrInvalidXPDU)
+	}
+	x.ID = binary.BigEndian.Uint16(tt[2].Value[4:6])
+
+	last := len(tt) - 1
+	if tt[last].Type != TLVTypeEnd {
+		return nil, decodeError(last, ErrorReasonMissingEnd, ErrInvalidXPDU)
+	}
+	if tt[last].Length != 0 {
+		return nil, decodeError(last, ErrorReasonInvalidEndLength, ErrInvalidXPDU)
+	}
+
+	x.TLVs = tt[3:last]
+
+	return trailing, nil
+}
+
+// Digest returns the SHA-256 digest of an XPDU in binary form, suitable for
+// use in a ManifestEntry.
+func (x *XPDU) Digest() ([sha256.Size]byte, error) {
+	b, err := x.MarshalBinary()
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+
+	return sha256.Sum256(b), nil
+}
+
+// Manifest returns the Manifest carried in a Frame's optional TLVs.  If
+// multiple Manifest TLVs are present, their entries are combined in the
+// order in which they appear.
+//
+// If no Manifest TLV is present, Manifest returns nil and false.  Any errors
+// encountered while unmarshaling the TLVs are also reported as false.
+func (f *Frame) Manifest() (*Manifest, bool) {
+	tt := f.OrganizationSpecific(OUIIEEE8021, IEEE8021SubtypeManifest)
+	if len(tt) == 0 {
+		return nil, false
+	}
+
+	m := new(Manifest)
+	for _, t := range tt {
+		mm := new(Manifest)
+		if err := mm.UnmarshalBinary(t.Value); err != nil {
+			return nil, false
+		}
+
+		m.Entries = append(m.Entries, mm.Entries...)
+	}
+
+	return m, true
+}
+
+// A Reassembler combines a Frame carrying a Manifest with the XPDUs listed
+// in that Manifest, producing a single logical Frame.
+type Reassembler struct {
+	base     *Frame
+	manifest *Manifest
+	xpdus    map[uint16]*XPDU
+}
+
+// NewReassembler creates a Reassembler for the input Frame.
+//
+// If the Frame does not carry a valid Manifest, ErrInvalidFrame is returned.
+func NewReassembler(f *Frame) (*Reassembler, error) {
+	m, ok := f.Manifest()
+	if !ok {
+		return nil, ErrInvalidFrame
+	}
+
+	return &Reassembler{
+		base:     f,
+		manifest: m,
+		xpdus:    make(map[uint16]*XPDU, len(m.Entries)),
+	}, nil
+}
+
+// Add unmarshals an XPDU from a byte slice and adds it to the Reassembler.
+// The XPDU's digest is computed over the bytes up to and including its end
+// of XPDU TLV, so any padding which follows is ignored.
+//
+// If the XPDU does not originate from the same chassis and port as the
+// Frame, is not listed in the Manifest, or its digest does not match the
+// digest listed in the Manifest, ErrInvalidXPDU is returned.
+func (r *Reassembler) Add(b []byte) error {
+	x := new(XPDU)
+	trailing, err := x.unmarshal(b)
+	if err != nil {
+		return err
+	}
+
+	if !r.originates(x) {
+		return ErrInvalidXPDU
+	}
+
+	digest := sha256.Sum256(b[:len(b)-len(trailing)])
+	for _, e := range r.manifest.Entries {
+		if e.ID != x.ID {
+			continue
+		}
+		if e.Digest != digest {
+			return ErrInvalidXPDU
+		}
+
+		r.xpdus[x.ID] = x
+		return nil
+	}
+
+	return ErrInvalidXPDU
+}
+
+// Missing returns the IDs of XPDUs listed in the Manifest which have not yet
+// been added to the Reassembler, in ascending order.  The result can be used
+// to populate an XPDURequest.
+func (r *Reassembler) Missing() []uint16 {
+	var ids []uint16
+	for _, e := range r.manifest.Entries {
+		if _, ok := r.xpdus[e.ID]; !ok {
+			ids = append(ids, e.ID)
+		}
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// Frame returns a single logical Frame containing the optional TLVs of the
+// original Frame, followed by the TLVs of each XPDU in Manifest order.
+// Manifest TLVs are removed from the resulting Frame.
+//
+// If any XPDUs listed in the Manifest have not been added, ErrIncompleteFrame
+// is returned.
+func (r *Reassembler) Frame() (*Frame, error) {
+	if len(r.Missing()) > 0 {
+		return nil, ErrIncompleteFrame
+	}
+
+	f := &Frame{
+		ChassisID: r.base.ChassisID,
+		PortID:    r.base.PortID,
+		TTL:       r.base.TTL,
+	}
+
+	for _, t := range r.base.Optional {
+		if isOrganizationSpecific(t, OUIIEEE8021, IEEE8021SubtypeManifest) {
+			continue
+		}
+
+		f.Optional = append(f.Optional, t)
+	}
+
+	for _, e := range r.manifest.Entries {
+		f.Optional = append(f.Optional, r.xpdus[e.ID].TLVs...)
+	}
+
+	return f, nil
+}
+
+// originates determines if an XPDU was sent by the same agent as the
+// Reassembler's Frame.
+func (r *Reassembler) originates(x *XPDU) bool {
+	return x.ChassisID.Subtype == r.base.ChassisID.Subtype &&
+		bytes.Equal(x.ChassisID.ID, r.base.ChassisID.ID) &&
+		x.PortID.Subtype == r.base.PortID.Subtype &&
+		bytes.Equal(x.PortID.ID, r.base.PortID.ID)
+}
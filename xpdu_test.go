@@ -0,0 +1,478 @@
+package lldp
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestManifestUnmarshalBinary(t *testing.T) {
+	digest := sha256.Sum256([]byte("foo"))
+
+	var tests = []struct {
+		desc string
+		b    []byte
+		m    *Manifest
+		err  error
+	}{
+		{
+			desc: "nil buffer",
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "wrong subtype",
+			b:    []byte{0x00, 0x80, 0xc2, IEEE8021SubtypeXPDURequest},
+			err:  ErrInvalidTLV,
+		},
+		{
+			desc: "short entry",
+			b:    []byte{0x00, 0x80, 0xc2, IEEE8021SubtypeManifest, 0x00, 0x01, 0xff},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "no entries",
+			b:    []byte{0x00, 0x80, 0xc2, IEEE8021SubtypeManifest},
+			m: &Manifest{
+				Entries: []ManifestEntry{},
+			},
+		},
+		{
+			desc: "one entry",
+			b: append(
+				[]byte{0x00, 0x80, 0xc2, IEEE8021SubtypeManifest, 0x00, 0x01},
+				digest[:]...,
+			),
+			m: &Manifest{
+				Entries: []ManifestEntry{{
+					ID:     1,
+					Digest: digest,
+				}},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		m := new(Manifest)
+		if err := m.UnmarshalBinary(tt.b); err != nil {
+			if want, got := tt.err, err; !errors.Is(got, want) {
+				t.Fatalf("unexpected error:\n- want: %v\n-  got: %v", want, got)
+			}
+
+			continue
+		}
+
+		if want, got := tt.m, m; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected Manifest:\n- want: %v\n-  got: %v", want, got)
+		}
+
+		b, err := m.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want, got := tt.b, b; !bytes.Equal(want, got) {
+			t.Fatalf("unexpected Manifest bytes:\n- want: %v\n-  got: %v", want, got)
+		}
+	}
+}
+
+func TestManifestMarshalBinaryTooLarge(t *testing.T) {
+	m := &Manifest{
+		Entries: make([]ManifestEntry, 15),
+	}
+
+	if _, err := m.MarshalBinary(); err != ErrInvalidTLV {
+		t.Fatalf("unexpected error:\n- want: %v\n-  got: %v", ErrInvalidTLV, err)
+	}
+}
+
+func TestXPDURequestUnmarshalBinary(t *testing.T) {
+	var tests = []struct {
+		desc string
+		b    []byte
+		r    *XPDURequest
+		err  error
+	}{
+		{
+			desc: "nil buffer",
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "wrong OUI",
+			b:    []byte{0x00, 0x12, 0x0f, IEEE8021SubtypeXPDURequest},
+			err:  ErrInvalidTLV,
+		},
+		{
+			desc: "odd length",
+			b:    []byte{0x00, 0x80, 0xc2, IEEE8021SubtypeXPDURequest, 0x00},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "OK",
+			b:    []byte{0x00, 0x80, 0xc2, IEEE8021SubtypeXPDURequest, 0x00, 0x01, 0x01, 0x00},
+			r: &XPDURequest{
+				IDs: []uint16{1, 256},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		r := new(XPDURequest)
+		if err := r.UnmarshalBinary(tt.b); err != nil {
+			if want, got := tt.err, err; !errors.Is(got, want) {
+				t.Fatalf("unexpected error:\n- want: %v\n-  got: %v", want, got)
+			}
+
+			continue
+		}
+
+		if want, got := tt.r, r; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected XPDURequest:\n- want: %v\n-  got: %v", want, got)
+		}
+
+		b, err := r.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want, got := tt.b, b; !bytes.Equal(want, got) {
+			t.Fatalf("unexpected XPDURequest bytes:\n- want: %v\n-  got: %v", want, got)
+		}
+	}
+}
+
+func TestXPDUUnmarshalBinary(t *testing.T) {
+	var tests = []struct {
+		desc string
+		b    []byte
+		x    *XPDU
+		err  error
+	}{
+		{
+			desc: "nil buffer",
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "first TLV not chassis ID type",
+			b: []byte{
+				0x04, 0x01, 0x00,
+				0x02, 0x01, 0x00,
+				0xfe, 0x06, 0x00, 0x80, 0xc2, IEEE8021SubtypeXPDUDescriptor, 0x00, 0x01,
+				0x00, 0x00,
+			},
+			err: ErrInvalidXPDU,
+		},
+		{
+			desc: "third TLV not XPDU descriptor",
+			b: []byte{
+				0x02, 0x01, 0x00,
+				0x04, 0x01, 0x00,
+				0x06, 0x02, 0x00, 0x00,
+				0x00, 0x00,
+			},
+			err: ErrInvalidXPDU,
+		},
+		{
+			desc: "last TLV not end of XPDU",
+			b: []byte{
+				0x02, 0x01, 0x00,
+				0x04, 0x01, 0x00,
+				0xfe, 0x06, 0x00, 0x80, 0xc2, IEEE8021SubtypeXPDUDescriptor, 0x00, 0x01,
+				0x0a, 0x00,
+			},
+			err: ErrInvalidXPDU,
+		},
+		{
+			desc: "OK",
+			b: []byte{
+				0x02, 0x04, 7, 'f', 'o', 'o',
+				0x04, 0x04, 7, 'b', 'a', 'r',
+				0xfe, 0x06, 0x00, 0x80, 0xc2, IEEE8021SubtypeXPDUDescriptor, 0x00, 0x01,
+				0x0a, 0x03, 'b', 'a', 'z',
+				0x00, 0x00,
+			},
+			x: &XPDU{
+				ChassisID: &ChassisID{
+					Subtype: 7,
+					ID:      []byte("foo"),
+				},
+				PortID: &PortID{
+					Subtype: 7,
+					ID:      []byte("bar"),
+				},
+				ID: 1,
+				TLVs: []*TLV{{
+					Type:   TLVTypeSystemName,
+					Length: 3,
+					Value:  []byte("baz"),
+				}},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		x := new(XPDU)
+		if err := x.UnmarshalBinary(tt.b); err != nil {
+			if want, got := tt.err, err; !errors.Is(got, want) {
+				t.Fatalf("unexpected error:\n- want: %v\n-  got: %v", want, got)
+			}
+
+			continue
+		}
+
+		if want, got := tt.x, x; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected XPDU:\n- want: %v\n-  got: %v", want, got)
+		}
+
+		b, err := x.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want, got := tt.b, b; !bytes.Equal(want, got) {
+			t.Fatalf("unexpected XPDU bytes:\n- want: %v\n-  got: %v", want, got)
+		}
+	}
+}
+
+func TestXPDUUnmarshalBinaryDecodeError(t *testing.T) {
+	var tests = []struct {
+		desc string
+		b    []byte
+		err  *DecodeError
+	}{
+		{
+			desc: "too few TLVs",
+			b: []byte{
+				0x02, 0x01, 0x00,
+				0x04, 0x01, 0x00,
+			},
+			err: &DecodeError{
+				Offset: 6,
+				Index:  -1,
+				Reason: ErrorReasonTooFewTLVs,
+				Err:    io.ErrUnexpectedEOF,
+			},
+		},
+		{
+			desc: "first TLV not chassis ID type",
+			b: []byte{
+				0x04, 0x01, 0x00,
+				0x02, 0x01, 0x00,
+				0xfe, 0x06, 0x00, 0x80, 0xc2, IEEE8021SubtypeXPDUDescriptor, 0x00, 0x01,
+				0x00, 0x00,
+			},
+			err: &DecodeError{
+				Offset: 0,
+				Index:  0,
+				Type:   TLVTypePortID,
+				Reason: ErrorReasonMissingChassisID,
+				Err:    ErrInvalidXPDU,
+			},
+		},
+		{
+			desc: "missing XPDU descriptor",
+			b: []byte{
+				0x02, 0x01, 0x00,
+				0x04, 0x01, 0x00,
+				0x00, 0x00,
+			},
+			err: &DecodeError{
+				Offset: 6,
+				Index:  2,
+				Type:   TLVTypeEnd,
+				Reason: ErrorReasonMissingXPDUDescriptor,
+				Err:    ErrInvalidXPDU,
+			},
+		},
+		{
+			desc: "XPDU descriptor too long",
+			b: []byte{
+				0x02, 0x01, 0x00,
+				0x04, 0x01, 0x00,
+				0xfe, 0x07, 0x00, 0x80, 0xc2, IEEE8021SubtypeXPDUDescriptor, 0x00, 0x01, 0x00,
+				0x00, 0x00,
+			},
+			err: &DecodeError{
+				Offset: 6,
+				Index:  2,
+				Type:   TLVTypeOrganizationSpecific,
+				Reason: ErrorReasonInvalidXPDUDescriptor,
+				Err:    ErrInvalidXPDU,
+			},
+		},
+		{
+			desc: "last TLV not end of XPDU",
+			b: []byte{
+				0x02, 0x01, 0x00,
+				0x04, 0x01, 0x00,
+				0xfe, 0x06, 0x00, 0x80, 0xc2, IEEE8021SubtypeXPDUDescriptor, 0x00, 0x01,
+				0x0a, 0x00,
+			},
+			err: &DecodeError{
+				Offset: 14,
+				Index:  3,
+				Type:   TLVTypeSystemName,
+				Reason: ErrorReasonMissingEnd,
+				Err:    ErrInvalidXPDU,
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("[%02d] test %q", i, tt.desc)
+
+		err := new(XPDU).UnmarshalBinary(tt.b)
+
+		var de *DecodeError
+		if !errors.As(err, &de) {
+			t.Fatalf("expected *DecodeError, but got: %#v", err)
+		}
+
+		if want, got := tt.err, de; !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected DecodeError:\n- want: %#v\n-  got: %#v", want, got)
+		}
+	}
+}
+
+func TestReassembler(t *testing.T) {
+	chassis := &ChassisID{Subtype: 7, ID: []byte("foo")}
+	port := &PortID{Subtype: 7, ID: []byte("bar")}
+
+	x1 := &XPDU{
+		ChassisID: chassis,
+		PortID:    port,
+		ID:        1,
+		TLVs: []*TLV{{
+			Type:   TLVTypeSystemDescription,
+			Length: 3,
+			Value:  []byte("baz"),
+		}},
+	}
+	x2 := &XPDU{
+		ChassisID: chassis,
+		PortID:    port,
+		ID:        2,
+		TLVs: []*TLV{{
+			Type:   TLVTypePortDescription,
+			Length: 3,
+			Value:  []byte("qux"),
+		}},
+	}
+
+	b1 := mustMarshal(t, x1)
+	b2 := mustMarshal(t, x2)
+	mb := mustMarshal(t, &Manifest{
+		Entries: []ManifestEntry{
+			{ID: 1, Digest: sha256.Sum256(b1)},
+			{ID: 2, Digest: sha256.Sum256(b2)},
+		},
+	})
+
+	name := &TLV{
+		Type:   TLVTypeSystemName,
+		Length: 4,
+		Value:  []byte("host"),
+	}
+	f := &Frame{
+		ChassisID: chassis,
+		PortID:    port,
+		TTL:       120 * time.Second,
+		Optional: []*TLV{
+			name,
+			{
+				Type:   TLVTypeOrganizationSpecific,
+				Length: uint16(len(mb)),
+				Value:  mb,
+			},
+		},
+	}
+
+	if _, err := NewReassembler(&Frame{}); err != ErrInvalidFrame {
+		t.Fatalf("unexpected error for Frame without Manifest: %v", err)
+	}
+
+	r, err := NewReassembler(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.Frame(); err != ErrIncompleteFrame {
+		t.Fatalf("unexpected error for incomplete Frame: %v", err)
+	}
+
+	// XPDU from a different port must be rejected.
+	other := mustMarshal(t, &XPDU{
+		ChassisID: chassis,
+		PortID:    &PortID{Subtype: 7, ID: []byte("qux")},
+		ID:        1,
+	})
+	if err := r.Add(other); err != ErrInvalidXPDU {
+		t.Fatalf("unexpected error for foreign XPDU: %v", err)
+	}
+
+	// XPDU with modified contents must fail digest verification.
+	bad := mustMarshal(t, &XPDU{
+		ChassisID: chassis,
+		PortID:    port,
+		ID:        2,
+	})
+	if err := r.Add(bad); err != ErrInvalidXPDU {
+		t.Fatalf("unexpected error for XPDU with bad digest: %v", err)
+	}
+
+	if err := r.Add(b2); err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := []uint16{1}, r.Missing(); !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected missing XPDUs:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	// Padding after the end of XPDU TLV is not included in the digest.
+	padded := append(append([]byte(nil), b1...), make([]byte, 16)...)
+	if err := r.Add(padded); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := r.Frame()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &Frame{
+		ChassisID: chassis,
+		PortID:    port,
+		TTL:       120 * time.Second,
+		Optional: []*TLV{
+			name,
+			x1.TLVs[0],
+			x2.TLVs[0],
+		},
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected Frame:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+func mustMarshal(t *testing.T, m interface {
+	MarshalBinary() ([]byte, error)
+}) []byte {
+	b, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return b
+}